@@ -4,9 +4,21 @@ import (
 	"github.com/joho/godotenv"
 	"github.com/sirupsen/logrus"
 	"os"
+	"strings"
 	"sync"
 )
 
+const defaultExternalApiProviderName = "default"
+
+type ExternalApiProvider struct {
+	Name            string
+	Url             string
+	RequestTemplate string
+	ApiKey          string
+	ApiKeyHeader    string
+	BearerToken     string
+}
+
 type Config struct {
 	DbHost               string
 	DbPort               string
@@ -14,7 +26,13 @@ type Config struct {
 	DbPassword           string
 	DbName               string
 	DbSSLMode            string
-	ExternalApiClientUrl string
+	ExternalApiProviders []ExternalApiProvider
+	EnrichmentFieldRules string
+	// EnrichmentPrecedence Приоритет значений клиента над значениями провайдеров: client, upstream или fill_missing
+	EnrichmentPrecedence      string
+	EnrichmentFieldPrecedence string
+	// ExplicitWordListsDir Каталог дополнительных списков нецензурных слов
+	ExplicitWordListsDir string
 	ServerPort           string
 }
 
@@ -34,10 +52,45 @@ func Get() Config {
 		config.DbUser = os.Getenv("DB_USER")
 		config.DbPassword = os.Getenv("DB_PASSWORD")
 		config.DbName = os.Getenv("DB_NAME")
-		config.ExternalApiClientUrl = os.Getenv("EXTERNAL_API_CLIENT_URL")
+		config.ExternalApiProviders = loadExternalApiProviders()
+		config.EnrichmentFieldRules = os.Getenv("ENRICHMENT_FIELD_RULES")
+		config.EnrichmentPrecedence = os.Getenv("ENRICHMENT_PRECEDENCE")
+		config.EnrichmentFieldPrecedence = os.Getenv("ENRICHMENT_FIELD_PRECEDENCE")
+		config.ExplicitWordListsDir = os.Getenv("EXPLICIT_WORDLISTS_DIR")
 		config.ServerPort = os.Getenv("SERVER_PORT")
 		config.DbSSLMode = os.Getenv("DB_SSL_MODE")
 	})
 
 	return config
 }
+
+// loadExternalApiProviders Загрузка провайдеров из EXTERNAL_API_PROVIDERS в порядке приоритета,
+// настройки каждого берутся из EXTERNAL_API_<NAME>_*. Без списка используется провайдер EXTERNAL_API_CLIENT_*
+func loadExternalApiProviders() []ExternalApiProvider {
+	rawNames := os.Getenv("EXTERNAL_API_PROVIDERS")
+	if strings.TrimSpace(rawNames) == "" {
+		return []ExternalApiProvider{loadExternalApiProvider(defaultExternalApiProviderName, "EXTERNAL_API_CLIENT_")}
+	}
+
+	providers := make([]ExternalApiProvider, 0)
+	for _, name := range strings.Split(rawNames, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		envPrefix := "EXTERNAL_API_" + strings.ToUpper(strings.ReplaceAll(name, "-", "_")) + "_"
+		providers = append(providers, loadExternalApiProvider(name, envPrefix))
+	}
+	return providers
+}
+
+func loadExternalApiProvider(name, envPrefix string) ExternalApiProvider {
+	return ExternalApiProvider{
+		Name:            name,
+		Url:             os.Getenv(envPrefix + "URL"),
+		RequestTemplate: os.Getenv(envPrefix + "TEMPLATE"),
+		ApiKey:          os.Getenv(envPrefix + "API_KEY"),
+		ApiKeyHeader:    os.Getenv(envPrefix + "API_KEY_HEADER"),
+		BearerToken:     os.Getenv(envPrefix + "BEARER_TOKEN"),
+	}
+}
@@ -0,0 +1,252 @@
+package main
+
+import (
+	"BestMusicLibrary/internal/model"
+	"BestMusicLibrary/internal/service"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/sirupsen/logrus"
+	"os"
+	"reflect"
+	"sync"
+	"time"
+)
+
+type backfiller struct {
+	songs     service.Song
+	filter    model.SongFilter
+	workers   int
+	rate      float64
+	batchSize int
+	statePath string
+	dryRun    bool
+	reindex   bool
+}
+
+// backfillState Прогресс обработки, сохраняемый после каждой обработанной песни
+type backfillState struct {
+	Filter    model.SongFilter `json:"filter"`
+	Reindex   bool             `json:"reindex"`
+	LastId    int64            `json:"last_id"`
+	Processed int              `json:"processed"`
+	Updated   int              `json:"updated"`
+	Unchanged int              `json:"unchanged"`
+	FailedIds []int64          `json:"failed_ids"`
+}
+
+type enrichResult struct {
+	id   int64
+	diff service.EnrichmentDiff
+	err  error
+}
+
+func (b *backfiller) Run(ctx context.Context, reset bool) error {
+	if b.workers <= 0 || b.batchSize <= 0 {
+		return errors.New("workers and batch size must be positive")
+	}
+
+	state, err := b.loadState(reset)
+	if err != nil {
+		return err
+	}
+
+	total, err := b.songs.CountSongs(b.filter)
+	if err != nil {
+		return err
+	}
+
+	var limiter <-chan time.Time
+	// Пересчет не обращается к провайдерам, поэтому частота запросов не ограничивается
+	if b.rate > 0 && !b.reindex {
+		ticker := time.NewTicker(time.Duration(float64(time.Second) / b.rate))
+		defer ticker.Stop()
+		limiter = ticker.C
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"total":   total,
+		"last_id": state.LastId,
+		"dry_run": b.dryRun,
+		"reindex": b.reindex,
+	}).Info("backfill started")
+
+	started := time.Now()
+	processedBefore := state.Processed
+	for ctx.Err() == nil {
+		ids, err := b.songs.GetSongIds(b.filter, state.LastId, b.batchSize)
+		if err != nil {
+			return err
+		}
+		if len(ids) == 0 {
+			b.report("backfill finished", state, total, processedBefore, started)
+			return nil
+		}
+
+		completed, err := b.processBatch(ctx, ids, limiter, func(result enrichResult) error {
+			state.Processed++
+			switch {
+			case result.err != nil:
+				state.FailedIds = append(state.FailedIds, result.id)
+				logrus.WithField("id", result.id).Error(result.err)
+			case len(result.diff.Changes) > 0:
+				state.Updated++
+			default:
+				state.Unchanged++
+			}
+			state.LastId = result.id
+			return b.saveState(state)
+		})
+		if err != nil {
+			return err
+		}
+		if !completed {
+			break
+		}
+		b.report("backfill progress", state, total, processedBefore, started)
+	}
+
+	b.report("backfill interrupted, rerun to resume", state, total, processedBefore, started)
+	return nil
+}
+
+// processBatch Обработка пачки песен пулом воркеров. Результаты передаются в done по возрастанию id: песня
+// передается, как только обработаны все песни пачки перед ней, поэтому курсор сохраняется после каждой песни
+// и никогда не пропускает необработанные. При отмене контекста пачка считается незавершенной, а готовые
+// результаты после первой необработанной песни отбрасываются и обрабатываются заново при следующем запуске
+func (b *backfiller) processBatch(ctx context.Context, ids []int64, limiter <-chan time.Time, done func(enrichResult) error) (bool, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	jobs := make(chan int, len(ids))
+	for index := range ids {
+		jobs <- index
+	}
+	close(jobs)
+
+	finished := make(chan int, len(ids))
+	results := make([]enrichResult, len(ids))
+	var wg sync.WaitGroup
+	for worker := 0; worker < b.workers; worker++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for index := range jobs {
+				if limiter != nil {
+					select {
+					case <-limiter:
+					case <-ctx.Done():
+						return
+					}
+				}
+				if ctx.Err() != nil {
+					return
+				}
+
+				diff, err := b.process(ids[index])
+				results[index] = enrichResult{id: ids[index], diff: diff, err: err}
+				finished <- index
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(finished)
+	}()
+
+	ready := make([]bool, len(ids))
+	next := 0
+	var doneErr error
+	for index := range finished {
+		ready[index] = true
+		for doneErr == nil && next < len(ids) && ready[next] {
+			if doneErr = done(results[next]); doneErr != nil {
+				cancel()
+			}
+			next++
+		}
+	}
+	if doneErr != nil {
+		return false, doneErr
+	}
+	return next == len(ids), nil
+}
+
+// process Повторное обогащение песни у провайдеров или пересчет ее производных полей в режиме reindex
+func (b *backfiller) process(id int64) (service.EnrichmentDiff, error) {
+	if b.reindex {
+		return b.songs.ReindexSong(id, b.dryRun)
+	}
+	return b.songs.EnrichSong(id, b.dryRun)
+}
+
+func (b *backfiller) report(message string, state backfillState, total, processedBefore int, started time.Time) {
+	elapsed := time.Since(started)
+	processed := state.Processed - processedBefore
+	percent := 100.0
+	if total > 0 {
+		percent = min(100, float64(state.Processed)*100/float64(total))
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"processed": state.Processed,
+		"total":     total,
+		"percent":   fmt.Sprintf("%.1f", percent),
+		"updated":   state.Updated,
+		"unchanged": state.Unchanged,
+		"failed":    len(state.FailedIds),
+		"last_id":   state.LastId,
+		"elapsed":   elapsed.Round(time.Second).String(),
+		"per_sec":   fmt.Sprintf("%.2f", float64(processed)/max(elapsed.Seconds(), 1)),
+	}).Info(message)
+}
+
+func (b *backfiller) loadState(reset bool) (backfillState, error) {
+	state := backfillState{Filter: b.filter, Reindex: b.reindex, FailedIds: make([]int64, 0)}
+	if reset || b.dryRun {
+		return state, nil
+	}
+
+	data, err := os.ReadFile(b.statePath)
+	if errors.Is(err, os.ErrNotExist) {
+		return state, nil
+	}
+	if err != nil {
+		return backfillState{}, err
+	}
+
+	var saved backfillState
+	if err = json.Unmarshal(data, &saved); err != nil {
+		return backfillState{}, fmt.Errorf("reading %s: %w", b.statePath, err)
+	}
+	if !reflect.DeepEqual(saved.Filter, b.filter) {
+		return backfillState{}, fmt.Errorf("%s was saved for another filter, use -reset to start over", b.statePath)
+	}
+	if saved.Reindex != b.reindex {
+		return backfillState{}, fmt.Errorf("%s was saved for another mode, use -reset to start over", b.statePath)
+	}
+	if saved.FailedIds == nil {
+		saved.FailedIds = make([]int64, 0)
+	}
+
+	return saved, nil
+}
+
+// saveState Атомарная запись прогресса через временный файл. В режиме dry-run прогресс не сохраняется
+func (b *backfiller) saveState(state backfillState) error {
+	if b.dryRun {
+		return nil
+	}
+
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmpPath := b.statePath + ".tmp"
+	if err = os.WriteFile(tmpPath, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, b.statePath)
+}
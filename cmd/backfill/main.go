@@ -0,0 +1,89 @@
+package main
+
+import (
+	"BestMusicLibrary/cfg"
+	"BestMusicLibrary/cmd/internal/providers"
+	"BestMusicLibrary/internal/model"
+	"BestMusicLibrary/internal/profanity"
+	"BestMusicLibrary/internal/repository"
+	"BestMusicLibrary/internal/service"
+	"BestMusicLibrary/migrations"
+	"context"
+	"flag"
+	"github.com/jmoiron/sqlx"
+	"github.com/sirupsen/logrus"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// Повторное обогащение или пересчет производных полей всех песен, подходящих под фильтр
+func main() {
+	group := flag.String("group", "", "Filter by group name")
+	song := flag.String("song", "", "Filter by song name")
+	workers := flag.Int("workers", 4, "Number of songs enriched concurrently")
+	rate := flag.Float64("rate", 5, "Maximum enrichment requests per second, 0 disables the limit")
+	batchSize := flag.Int("batch", 100, "Number of songs loaded per batch")
+	statePath := flag.String("state", "backfill.state.json", "File with the progress used to resume an interrupted run")
+	reset := flag.Bool("reset", false, "Ignore the saved progress and start from the first song")
+	dryRun := flag.Bool("dry-run", false, "Only report the changes without applying them")
+	reindex := flag.Bool("reindex", false, "Recompute search keys, language, explicit flag and similar song terms from the stored data instead of querying providers")
+	flag.Parse()
+
+	config := cfg.Get()
+	db, err := repository.NewPostgresDb(repository.Config{Host: config.DbHost, Port: config.DbPort, UserName: config.DbUser, Password: config.DbPassword, DbName: config.DbName, SSLMode: config.DbSSLMode})
+	if err != nil {
+		logrus.Fatal(err)
+		return
+	}
+	defer func(db *sqlx.DB) {
+		err = db.Close()
+		if err != nil {
+			logrus.Error(err)
+		}
+	}(db)
+
+	dbMigrator := migrations.NewDbMigrator(db, "migrations")
+	if err = dbMigrator.Migrate(); err != nil {
+		logrus.Error(err)
+		return
+	}
+
+	providerChain, err := providers.NewChain(config)
+	if err != nil {
+		logrus.Error(err)
+		return
+	}
+
+	precedence, err := service.ParsePrecedence(config.EnrichmentPrecedence, config.EnrichmentFieldPrecedence)
+	if err != nil {
+		logrus.Error(err)
+		return
+	}
+
+	explicitDetector, err := profanity.NewDetector(config.ExplicitWordListsDir)
+	if err != nil {
+		logrus.Error(err)
+		return
+	}
+
+	mainService := service.NewService(repository.NewRepository(db), providerChain, precedence, explicitDetector)
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	b := &backfiller{
+		songs:     mainService.Song,
+		filter:    model.SongFilter{Group: *group, Name: *song},
+		workers:   *workers,
+		rate:      *rate,
+		batchSize: *batchSize,
+		statePath: *statePath,
+		dryRun:    *dryRun,
+		reindex:   *reindex,
+	}
+	if err = b.Run(ctx, *reset); err != nil {
+		logrus.Error(err)
+		os.Exit(1)
+	}
+}
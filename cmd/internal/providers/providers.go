@@ -0,0 +1,34 @@
+package providers
+
+import (
+	"BestMusicLibrary/cfg"
+	"BestMusicLibrary/internal/client"
+	"BestMusicLibrary/internal/service"
+	"fmt"
+)
+
+// NewChain Цепочка провайдеров обогащения по конфигурации. Связывает клиентов внешних API с сервисом,
+// поэтому находится рядом с командами, а не в пакете client
+func NewChain(config cfg.Config) (*service.ProviderChain, error) {
+	fieldRules, err := service.ParseFieldRules(config.EnrichmentFieldRules)
+	if err != nil {
+		return nil, err
+	}
+
+	songProviders := make([]service.SongDataProvider, 0, len(config.ExternalApiProviders))
+	for _, provider := range config.ExternalApiProviders {
+		apiClient, err := client.NewExternalSongApiClient(client.ExternalSongApiClientConfig{
+			BaseUrl:         provider.Url,
+			RequestTemplate: provider.RequestTemplate,
+			ApiKey:          provider.ApiKey,
+			ApiKeyHeader:    provider.ApiKeyHeader,
+			BearerToken:     provider.BearerToken,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("provider %s: %w", provider.Name, err)
+		}
+		songProviders = append(songProviders, service.SongDataProvider{Name: provider.Name, Fetcher: apiClient})
+	}
+
+	return service.NewProviderChain(songProviders, fieldRules)
+}
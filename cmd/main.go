@@ -3,9 +3,10 @@ package main
 import (
 	"BestMusicLibrary"
 	"BestMusicLibrary/cfg"
+	"BestMusicLibrary/cmd/internal/providers"
 	_ "BestMusicLibrary/docs"
-	"BestMusicLibrary/internal/client"
 	"BestMusicLibrary/internal/handler"
+	"BestMusicLibrary/internal/profanity"
 	"BestMusicLibrary/internal/repository"
 	"BestMusicLibrary/internal/service"
 	"BestMusicLibrary/migrations"
@@ -47,8 +48,25 @@ func main() {
 	}
 
 	repos := repository.NewRepository(db)
-	externalClient := client.NewExternalSongApiClient(config.ExternalApiClientUrl)
-	mainService := service.NewService(repos, externalClient)
+	providerChain, err := providers.NewChain(config)
+	if err != nil {
+		logrus.Error(err)
+		return
+	}
+
+	precedence, err := service.ParsePrecedence(config.EnrichmentPrecedence, config.EnrichmentFieldPrecedence)
+	if err != nil {
+		logrus.Error(err)
+		return
+	}
+
+	explicitDetector, err := profanity.NewDetector(config.ExplicitWordListsDir)
+	if err != nil {
+		logrus.Error(err)
+		return
+	}
+
+	mainService := service.NewService(repos, providerChain, precedence, explicitDetector)
 	hand := handler.NewHandler(mainService)
 	srv := BestMusicLibrary.Server{}
 
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"BestMusicLibrary/internal/mockinfo"
+	"context"
+	"flag"
+	"github.com/sirupsen/logrus"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+// Локальная замена внешнего API /info?group=&song= на фикстурах
+func main() {
+	addr := flag.String("addr", ":8081", "Address to listen on")
+	fixturesDir := flag.String("fixtures", "fixtures/mockinfo", "Directory with JSON or YAML fixtures")
+	latency := flag.Duration("latency", 0, "Delay added to every response")
+	jitter := flag.Duration("jitter", 0, "Maximum random delay added on top of latency")
+	errorRate := flag.Float64("error-rate", 0, "Share of requests failing with error-status, from 0 to 1")
+	errorStatus := flag.Int("error-status", http.StatusInternalServerError, "Status code of injected errors")
+	flag.Parse()
+
+	fixtures, err := mockinfo.LoadFixtures(*fixturesDir)
+	if err != nil {
+		logrus.Fatal(err)
+		return
+	}
+
+	srv := &http.Server{
+		Addr: *addr,
+		Handler: mockinfo.NewServer(fixtures, mockinfo.Options{
+			Latency:     *latency,
+			Jitter:      *jitter,
+			ErrorRate:   *errorRate,
+			ErrorStatus: *errorStatus,
+		}),
+	}
+
+	go func() {
+		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			logrus.Error(err)
+		}
+	}()
+
+	logrus.WithField("fixtures", len(fixtures)).Infof("mock song info api listening on %s", *addr)
+
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, os.Interrupt, syscall.SIGTERM, syscall.SIGINT)
+	<-quit
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	_ = srv.Shutdown(ctx)
+}
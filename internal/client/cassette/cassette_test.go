@@ -0,0 +1,60 @@
+package cassette
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+)
+
+func get(t *testing.T, transport http.RoundTripper, url string) (int, string) {
+	resp, err := (&http.Client{Transport: transport}).Get(url)
+	require.NoError(t, err)
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return resp.StatusCode, string(body)
+}
+
+func TestRecordAndReplay(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Set-Cookie", "session=secret")
+		if r.URL.Query().Get("song") == "Unknown" {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte(`{"link": "https://example.com"}`))
+	}))
+	path := filepath.Join(t.TempDir(), "cassette.json")
+
+	recorder, err := New(path, ModeRecord, nil)
+	require.NoError(t, err)
+	status, body := get(t, recorder, server.URL+"/info?group=Muse&song=Uprising")
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, `{"link": "https://example.com"}`, body)
+	status, _ = get(t, recorder, server.URL+"/info?group=Muse&song=Unknown")
+	assert.Equal(t, http.StatusNotFound, status)
+	require.NoError(t, recorder.Save())
+	server.Close()
+
+	player, err := New(path, ModeReplay, nil)
+	require.NoError(t, err)
+	assert.Equal(t, OriginRecorded, player.Origin())
+	assert.Contains(t, player.cassette.Source, server.URL)
+	assert.Len(t, player.cassette.Interactions, 2)
+	assert.NotContains(t, player.cassette.Interactions[0].Response.Headers, "Set-Cookie")
+
+	status, body = get(t, player, "https://another-host.com/info?group=Muse&song=Uprising")
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, `{"link": "https://example.com"}`, body)
+	status, _ = get(t, player, "https://another-host.com/info?group=Muse&song=Unknown")
+	assert.Equal(t, http.StatusNotFound, status)
+
+	_, err = (&http.Client{Transport: player}).Get("https://another-host.com/info?group=Muse&song=Hysteria")
+	assert.Error(t, err)
+}
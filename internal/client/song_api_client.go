@@ -7,6 +7,15 @@ import (
 	"github.com/sirupsen/logrus"
 	"io"
 	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	DefaultRequestTemplate = "/info?group={group}&song={song}"
+	DefaultApiKeyHeader    = "X-API-Key"
+	defaultRequestTimeout  = 10 * time.Second
 )
 
 type songApiResponse struct {
@@ -15,28 +24,96 @@ type songApiResponse struct {
 	Link        string `json:"link"`
 }
 
-func NewExternalSongApiClient(baseUrl string) *ExternalSongApiClient {
-	return &ExternalSongApiClient{baseUrl: baseUrl}
+// ExternalSongApiClientConfig Настройки клиента совместимого внешнего API.
+// В шаблоне запроса {group} и {song} заменяются экранированными названиями группы и песни
+type ExternalSongApiClientConfig struct {
+	BaseUrl         string
+	RequestTemplate string
+	ApiKey          string
+	ApiKeyHeader    string
+	BearerToken     string
+	HttpClient      *http.Client
+}
+
+func NewExternalSongApiClient(config ExternalSongApiClientConfig) (*ExternalSongApiClient, error) {
+	baseUrl, err := url.Parse(config.BaseUrl)
+	if err != nil {
+		return nil, err
+	}
+	if baseUrl.Scheme == "" || baseUrl.Host == "" {
+		return nil, fmt.Errorf("external api url %q must be absolute", config.BaseUrl)
+	}
+
+	template := config.RequestTemplate
+	if template == "" {
+		template = DefaultRequestTemplate
+	}
+	path, rawQuery, _ := strings.Cut(template, "?")
+	query, err := url.ParseQuery(rawQuery)
+	if err != nil {
+		return nil, fmt.Errorf("invalid request template %q: %w", template, err)
+	}
+
+	apiKeyHeader := config.ApiKeyHeader
+	if apiKeyHeader == "" {
+		apiKeyHeader = DefaultApiKeyHeader
+	}
+
+	httpClient := config.HttpClient
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: defaultRequestTimeout}
+	}
+
+	return &ExternalSongApiClient{
+		baseUrl:       baseUrl,
+		pathTemplate:  path,
+		queryTemplate: query,
+		apiKey:        config.ApiKey,
+		apiKeyHeader:  apiKeyHeader,
+		bearerToken:   config.BearerToken,
+		httpClient:    httpClient,
+	}, nil
 }
 
 type ExternalSongApiClient struct {
-	baseUrl string
+	baseUrl       *url.URL
+	pathTemplate  string
+	queryTemplate url.Values
+	apiKey        string
+	apiKeyHeader  string
+	bearerToken   string
+	httpClient    *http.Client
 }
 
 func (c *ExternalSongApiClient) FetchSongDetails(group, song string) (service.SongFetchData, error) {
-	url := fmt.Sprintf("%s/info?group=%s&song=%s", c.baseUrl, group, song)
-	resp, err := http.Get(url)
-	if err != nil || resp.StatusCode != http.StatusOK {
+	req, err := http.NewRequest(http.MethodGet, c.requestUrl(group, song), nil)
+	if err != nil {
+		return service.SongFetchData{}, err
+	}
+	req.Header.Set("Accept", "application/json")
+	if c.apiKey != "" {
+		req.Header.Set(c.apiKeyHeader, c.apiKey)
+	}
+	if c.bearerToken != "" {
+		req.Header.Set("Authorization", "Bearer "+c.bearerToken)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
 		return service.SongFetchData{}, err
 	}
 
 	defer func(Body io.ReadCloser) {
 		err = Body.Close()
 		if err != nil {
-			logrus.Errorf("error closing client response body from %s\n", url)
+			logrus.Errorf("error closing client response body from %s\n", req.URL.Redacted())
 		}
 	}(resp.Body)
 
+	if resp.StatusCode != http.StatusOK {
+		return service.SongFetchData{}, fmt.Errorf("external api responded with status %d", resp.StatusCode)
+	}
+
 	var songResponse songApiResponse
 	if err = json.NewDecoder(resp.Body).Decode(&songResponse); err != nil {
 		return service.SongFetchData{}, err
@@ -44,3 +121,23 @@ func (c *ExternalSongApiClient) FetchSongDetails(group, song string) (service.So
 
 	return service.SongFetchData{ReleaseDate: songResponse.ReleaseDate, Link: songResponse.Link, Text: songResponse.Text}, nil
 }
+
+// requestUrl Подстановка группы и песни в шаблон: в пути значения экранируются как сегменты, в запросе через url.Values
+func (c *ExternalSongApiClient) requestUrl(group, song string) string {
+	escapedReplacer := strings.NewReplacer("{group}", url.PathEscape(group), "{song}", url.PathEscape(song))
+	rawReplacer := strings.NewReplacer("{group}", group, "{song}", song)
+
+	requestUrl := *c.baseUrl
+	requestUrl.Path = strings.TrimRight(c.baseUrl.Path, "/") + rawReplacer.Replace(c.pathTemplate)
+	requestUrl.RawPath = strings.TrimRight(c.baseUrl.EscapedPath(), "/") + escapedReplacer.Replace(c.pathTemplate)
+
+	query := url.Values{}
+	for key, values := range c.queryTemplate {
+		for _, value := range values {
+			query.Add(key, rawReplacer.Replace(value))
+		}
+	}
+	requestUrl.RawQuery = query.Encode()
+
+	return requestUrl.String()
+}
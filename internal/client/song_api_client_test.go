@@ -0,0 +1,145 @@
+package client
+
+import (
+	"BestMusicLibrary/internal/client/cassette"
+	"BestMusicLibrary/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// newCassetteClient Клиент, воспроизводящий кассету из testdata/cassettes.
+// С CASSETTE_MODE=record запросы уходят на EXTERNAL_API_CLIENT_URL и кассета перезаписывается
+func newCassetteClient(t *testing.T, name string) *ExternalSongApiClient {
+	mode := cassette.ModeReplay
+	baseUrl := "https://external-api.com"
+	if os.Getenv("CASSETTE_MODE") == string(cassette.ModeRecord) {
+		mode = cassette.ModeRecord
+		baseUrl = os.Getenv("EXTERNAL_API_CLIENT_URL")
+	}
+
+	transport, err := cassette.New(filepath.Join("testdata", "cassettes", name+".json"), mode, nil)
+	require.NoError(t, err)
+	if transport.Origin() == cassette.OriginSynthetic {
+		t.Logf("cassette %s is synthetic, record it from the real API with CASSETTE_MODE=record", name)
+	}
+	t.Cleanup(func() {
+		assert.NoError(t, transport.Save())
+	})
+
+	apiClient, err := NewExternalSongApiClient(ExternalSongApiClientConfig{BaseUrl: baseUrl, HttpClient: &http.Client{Transport: transport}})
+	require.NoError(t, err)
+	return apiClient
+}
+
+func TestFetchSongDetailsCassettes(t *testing.T) {
+	tests := []struct {
+		cassette string
+		group    string
+		song     string
+		expected service.SongFetchData
+	}{
+		{
+			cassette: "muse_supermassive_black_hole",
+			group:    "Muse",
+			song:     "Supermassive Black Hole",
+			expected: service.SongFetchData{
+				ReleaseDate: "16.07.2006",
+				Text:        "First line of the first verse\nSecond line of the first verse\n\nFirst line of the chorus\nSecond line of the chorus",
+				Link:        "https://www.youtube.com/watch?v=Xsp3_a-PMTw",
+			},
+		},
+		{
+			cassette: "kino_gruppa_krovi",
+			group:    "Кино",
+			song:     "Группа крови",
+			expected: service.SongFetchData{
+				ReleaseDate: "1988",
+				Text:        "Первая строка первого куплета\r\nВторая строка первого куплета\r\n\r\nПервая строка припева\r\nВторая строка припева",
+				Link:        "https://example.com/kino/gruppa-krovi",
+			},
+		},
+		{
+			cassette: "extra_fields",
+			group:    "AC/DC",
+			song:     "Back In Black",
+			expected: service.SongFetchData{
+				Text: "Verse with a slash in the group name",
+				Link: "https://example.com/acdc/back-in-black",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.cassette, func(t *testing.T) {
+			data, err := newCassetteClient(t, test.cassette).FetchSongDetails(test.group, test.song)
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, data)
+		})
+	}
+}
+
+func TestFetchSongDetailsCassetteErrors(t *testing.T) {
+	_, err := newCassetteClient(t, "not_found").FetchSongDetails("Muse", "Unknown")
+	assert.Error(t, err)
+
+	_, err = newCassetteClient(t, "html_error_page").FetchSongDetails("Muse", "Uprising")
+	assert.Error(t, err)
+}
+
+func TestRequestUrlEscapesQuery(t *testing.T) {
+	apiClient, err := NewExternalSongApiClient(ExternalSongApiClientConfig{BaseUrl: "https://external-api.com"})
+	assert.NoError(t, err)
+
+	assert.Equal(t, "https://external-api.com/info?group=AC%2FDC&song=Back+In+Black", apiClient.requestUrl("AC/DC", "Back In Black"))
+	assert.Equal(t, "https://external-api.com/info?group=Simon+%26+Garfunkel&song=The+Boxer", apiClient.requestUrl("Simon & Garfunkel", "The Boxer"))
+}
+
+func TestRequestUrlTemplate(t *testing.T) {
+	apiClient, err := NewExternalSongApiClient(ExternalSongApiClientConfig{
+		BaseUrl:         "https://lyrics-api.com/v2/",
+		RequestTemplate: "/artists/{group}/songs/{song}?format=json",
+	})
+	assert.NoError(t, err)
+
+	assert.Equal(t, "https://lyrics-api.com/v2/artists/AC%2FDC/songs/T.N.T.?format=json", apiClient.requestUrl("AC/DC", "T.N.T."))
+}
+
+func TestFetchSongDetailsSendsAuthHeaders(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "secret", r.Header.Get("X-Token"))
+		assert.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+		assert.Equal(t, "Simon & Garfunkel", r.URL.Query().Get("group"))
+		_, _ = w.Write([]byte(`{"release_date": "1969", "text": "I am just a poor boy", "link": "https://example.com"}`))
+	}))
+	defer server.Close()
+
+	apiClient, err := NewExternalSongApiClient(ExternalSongApiClientConfig{
+		BaseUrl:      server.URL,
+		ApiKey:       "secret",
+		ApiKeyHeader: "X-Token",
+		BearerToken:  "token",
+	})
+	assert.NoError(t, err)
+
+	data, err := apiClient.FetchSongDetails("Simon & Garfunkel", "The Boxer")
+	assert.NoError(t, err)
+	assert.Equal(t, service.SongFetchData{ReleaseDate: "1969", Text: "I am just a poor boy", Link: "https://example.com"}, data)
+}
+
+func TestFetchSongDetailsUnexpectedStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	apiClient, err := NewExternalSongApiClient(ExternalSongApiClientConfig{BaseUrl: server.URL})
+	assert.NoError(t, err)
+
+	_, err = apiClient.FetchSongDetails("Muse", "Unknown")
+	assert.Error(t, err)
+}
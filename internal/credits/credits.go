@@ -0,0 +1,39 @@
+// Package credits Разбор участников из названий группы и песни: "A feat. B", "Песня (ft. B)" и "A & B"
+package credits
+
+import (
+	"regexp"
+	"strings"
+)
+
+// featuringPattern Отметка приглашенного участника в конце названия, в том числе в круглых или квадратных скобках
+var featuringPattern = regexp.MustCompile(`(?i)(?:\s+|\s*[(\[]\s*)(?:feat\.?|ft\.?|featuring)\s+(.+?)\s*[)\]]?\s*$`)
+
+// separatorPattern Разделитель нескольких имен в одной строке. Запятая не считается разделителем, потому что
+// входит во многие названия групп: "Earth, Wind & Fire", "Crosby, Stills, Nash & Young"
+var separatorPattern = regexp.MustCompile(`\s*&\s*`)
+
+// Featuring Название без отметки приглашенных участников и строка с их именами, пустая без отметки.
+// Строка не делится на имена: делить ли ее по "&", решает вызывающий код
+func Featuring(name string) (string, string) {
+	match := featuringPattern.FindStringSubmatchIndex(name)
+	if match == nil {
+		return strings.TrimSpace(name), ""
+	}
+	main := strings.TrimSpace(name[:match[0]])
+	if main == "" {
+		return strings.TrimSpace(name), ""
+	}
+	return main, strings.TrimSpace(name[match[2]:match[3]])
+}
+
+// SplitNames Имена, перечисленные через "&", без пустых
+func SplitNames(names string) []string {
+	result := make([]string, 0)
+	for _, name := range separatorPattern.Split(names, -1) {
+		if name = strings.TrimSpace(name); name != "" {
+			result = append(result, name)
+		}
+	}
+	return result
+}
@@ -0,0 +1,35 @@
+package credits
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestFeaturing(t *testing.T) {
+	cases := []struct {
+		name     string
+		main     string
+		featured string
+	}{
+		{"Eminem feat. Rihanna", "Eminem", "Rihanna"},
+		{"Баста ft. Полина Гагарина", "Баста", "Полина Гагарина"},
+		{"Lose Yourself (feat. Dr. Dre & 50 Cent)", "Lose Yourself", "Dr. Dre & 50 Cent"},
+		{"Song [Ft Earth, Wind & Fire]", "Song", "Earth, Wind & Fire"},
+		{"Daft Punk featuring Pharrell Williams", "Daft Punk", "Pharrell Williams"},
+		{"Soft Cell", "Soft Cell", ""},
+		{"Кино", "Кино", ""},
+		{"feat. Someone", "feat. Someone", ""},
+	}
+	for _, c := range cases {
+		main, featured := Featuring(c.name)
+		assert.Equal(t, c.main, main, c.name)
+		assert.Equal(t, c.featured, featured, c.name)
+	}
+}
+
+func TestSplitNames(t *testing.T) {
+	assert.Equal(t, []string{"Simon", "Garfunkel"}, SplitNames("Simon & Garfunkel"))
+	assert.Equal(t, []string{"Crosby, Stills, Nash", "Young"}, SplitNames("Crosby, Stills, Nash & Young"))
+	assert.Equal(t, []string{"Кино"}, SplitNames(" Кино "))
+	assert.Equal(t, []string{}, SplitNames(" & "))
+}
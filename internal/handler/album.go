@@ -0,0 +1,361 @@
+package handler
+
+import (
+	"BestMusicLibrary/internal/model"
+	"encoding/json"
+	"fmt"
+	"github.com/sirupsen/logrus"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+type albumRequest struct {
+	Title       string `json:"title"`
+	ArtistId    int64  `json:"artist_id,omitempty"`
+	ReleaseDate string `json:"release_date,omitempty" example:"1988-01-01"`
+	Type        string `json:"type" enums:"lp,ep,single,compilation"`
+}
+
+func (r albumRequest) toAlbum(id int64) (model.Album, error) {
+	album := model.Album{Id: id, ArtistId: r.ArtistId, Title: r.Title, Type: model.AlbumType(r.Type)}
+	if r.ReleaseDate != "" {
+		releaseDate, err := time.Parse(time.DateOnly, r.ReleaseDate)
+		if err != nil {
+			return model.Album{}, err
+		}
+		album.ReleaseDate = &releaseDate
+	}
+	return album, nil
+}
+
+type albumTrackRequest struct {
+	SongId      int64 `json:"song_id"`
+	DiscNumber  int   `json:"disc_number,omitempty"`
+	TrackNumber int   `json:"track_number,omitempty"`
+}
+
+func (r albumTrackRequest) toTrack() model.AlbumTrack {
+	return model.AlbumTrack{SongId: r.SongId, DiscNumber: r.DiscNumber, TrackNumber: r.TrackNumber}
+}
+
+// GetAlbums godoc
+// @Summary      Get list of albums
+// @Description  Retrieves albums without tracklists, newest releases first. The title filter works in either Cyrillic or Latin script.
+// @Tags         albums
+// @Produce      json
+// @Param        artist_id  query  int     false  "Filter by artist ID"
+// @Param        title      query  string  false  "Filter by album title"
+// @Param        page       query  int     false  "Page number for pagination"
+// @Param        limit      query  int     false  "Limit the number of albums per page"
+// @Success      200  {array}   model.Album  "Albums"
+// @Failure      400  {string}  string  "Invalid query parameters"
+// @Failure      500  {string}  string  "Internal server error"
+// @Router       /albums [get]
+func (h *Handler) GetAlbums(w http.ResponseWriter, r *http.Request) {
+	filter := model.AlbumFilter{Title: r.URL.Query().Get("title")}
+	if rawArtistId := r.URL.Query().Get("artist_id"); rawArtistId != "" {
+		artistId, err := strconv.ParseInt(rawArtistId, 10, 64)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			logrus.Error(err)
+			return
+		}
+		filter.ArtistId = artistId
+	}
+	page, limit, err := parsePagingData(r.URL.Query().Get("page"), r.URL.Query().Get("limit"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		logrus.Error(err)
+		return
+	}
+
+	albums, err := h.service.Album.GetAlbums(filter, page, limit)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+	if err = json.NewEncoder(w).Encode(albums); err != nil {
+		handleError(w, err)
+		return
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"artist_id": filter.ArtistId,
+		"title":     filter.Title,
+		"count":     len(albums),
+	}).Info("albums successfully sent")
+}
+
+// GetAlbum godoc
+// @Summary      Get an album
+// @Description  Retrieves an album with its tracklist ordered by disc and track number.
+// @Tags         albums
+// @Produce      json
+// @Param        id  path  int  true  "Album ID"
+// @Success      200  {object}  model.Album  "Album with tracklist"
+// @Failure      400  {string}  string  "Invalid album ID"
+// @Failure      404  {string}  string  "Album not found"
+// @Failure      500  {string}  string  "Internal server error"
+// @Router       /albums/{id} [get]
+func (h *Handler) GetAlbum(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		logrus.Error(err)
+		return
+	}
+
+	album, err := h.service.Album.GetAlbum(int64(id))
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+	if err = json.NewEncoder(w).Encode(album); err != nil {
+		handleError(w, err)
+		return
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"id":     id,
+		"tracks": len(album.Tracks),
+	}).Info("album successfully sent")
+}
+
+// AddAlbum godoc
+// @Summary      Add an album
+// @Description  Adds an album without tracks. Type is lp by default. Every album type except compilation requires an artist.
+// @Tags         albums
+// @Accept       json
+// @Produce      json
+// @Param        album  body  albumRequest  true  "Album details"
+// @Success      201  {string}  string  "Successfully added album with its ID"
+// @Failure      400  {string}  string  "Invalid request body or unknown artist"
+// @Failure      500  {string}  string  "Internal server error"
+// @Router       /albums [post]
+func (h *Handler) AddAlbum(w http.ResponseWriter, r *http.Request) {
+	var request albumRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		logrus.Error(err)
+		return
+	}
+	album, err := request.toAlbum(0)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		logrus.Error(err)
+		return
+	}
+
+	albumId, err := h.service.Album.AddAlbum(album)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	if _, err = fmt.Fprintf(w, "%d", albumId); err != nil {
+		handleError(w, err)
+		return
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"id":    albumId,
+		"title": request.Title,
+	}).Info("album successfully added")
+}
+
+// UpdateAlbum godoc
+// @Summary      Update an album
+// @Description  Replaces the album title, artist, release date and type. The tracklist is not changed.
+// @Tags         albums
+// @Accept       json
+// @Produce      json
+// @Param        id     path  int           true  "Album ID"
+// @Param        album  body  albumRequest  true  "Album details"
+// @Success      200  {string}  string  "Album successfully updated"
+// @Failure      400  {string}  string  "Invalid album ID, request body or unknown artist"
+// @Failure      404  {string}  string  "Album not found"
+// @Failure      500  {string}  string  "Internal server error"
+// @Router       /albums/{id} [put]
+func (h *Handler) UpdateAlbum(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		logrus.Error(err)
+		return
+	}
+
+	var request albumRequest
+	if err = json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		logrus.Error(err)
+		return
+	}
+	album, err := request.toAlbum(int64(id))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		logrus.Error(err)
+		return
+	}
+
+	if err = h.service.Album.UpdateAlbum(album); err != nil {
+		handleError(w, err)
+		return
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"id":    id,
+		"title": request.Title,
+	}).Info("album successfully updated")
+	w.WriteHeader(http.StatusOK)
+}
+
+// DeleteAlbum godoc
+// @Summary      Delete an album
+// @Description  Deletes an album and its tracklist. The songs stay in the library.
+// @Tags         albums
+// @Param        id  path  int  true  "Album ID"
+// @Success      200  {string}  string  "Album successfully deleted"
+// @Failure      400  {string}  string  "Invalid album ID"
+// @Failure      404  {string}  string  "Album not found"
+// @Failure      500  {string}  string  "Internal server error"
+// @Router       /albums/{id} [delete]
+func (h *Handler) DeleteAlbum(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		logrus.Error(err)
+		return
+	}
+
+	if err = h.service.Album.DeleteAlbum(int64(id)); err != nil {
+		handleError(w, err)
+		return
+	}
+
+	logrus.WithField("id", id).Info("album successfully deleted")
+	w.WriteHeader(http.StatusOK)
+}
+
+// ReplaceAlbumTracks godoc
+// @Summary      Replace or reorder the tracklist
+// @Description  Replaces the whole tracklist of an album, which is also how tracks are reordered. Every track needs a track number, the disc number is 1 by default. A song may appear on an album once and on any number of albums.
+// @Tags         albums
+// @Accept       json
+// @Produce      json
+// @Param        id      path  int                  true  "Album ID"
+// @Param        tracks  body  []albumTrackRequest  true  "Tracklist"
+// @Success      200  {string}  string  "Tracklist successfully saved"
+// @Failure      400  {string}  string  "Invalid album ID, request body, repeated song or position, or unknown song"
+// @Failure      404  {string}  string  "Album not found"
+// @Failure      500  {string}  string  "Internal server error"
+// @Router       /albums/{id}/tracks [put]
+func (h *Handler) ReplaceAlbumTracks(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		logrus.Error(err)
+		return
+	}
+
+	var requests []albumTrackRequest
+	if err = json.NewDecoder(r.Body).Decode(&requests); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		logrus.Error(err)
+		return
+	}
+	tracks := make([]model.AlbumTrack, 0, len(requests))
+	for _, request := range requests {
+		tracks = append(tracks, request.toTrack())
+	}
+
+	if err = h.service.Album.ReplaceAlbumTracks(int64(id), tracks); err != nil {
+		handleError(w, err)
+		return
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"id":     id,
+		"tracks": len(tracks),
+	}).Info("tracklist successfully saved")
+	w.WriteHeader(http.StatusOK)
+}
+
+// AddAlbumTrack godoc
+// @Summary      Add a track
+// @Description  Adds a song to the album. Without a track number the song becomes the last track of the disc, the disc number is 1 by default.
+// @Tags         albums
+// @Accept       json
+// @Produce      json
+// @Param        id     path  int                true  "Album ID"
+// @Param        track  body  albumTrackRequest  true  "Track"
+// @Success      201  {string}  string  "Track successfully added"
+// @Failure      400  {string}  string  "Invalid album ID, request body or unknown song"
+// @Failure      404  {string}  string  "Album not found"
+// @Failure      409  {string}  string  "Song or position is already on the album"
+// @Failure      500  {string}  string  "Internal server error"
+// @Router       /albums/{id}/tracks [post]
+func (h *Handler) AddAlbumTrack(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		logrus.Error(err)
+		return
+	}
+
+	var request albumTrackRequest
+	if err = json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		logrus.Error(err)
+		return
+	}
+
+	if err = h.service.Album.AddAlbumTrack(int64(id), request.toTrack()); err != nil {
+		handleError(w, err)
+		return
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"id":      id,
+		"song_id": request.SongId,
+	}).Info("album track successfully added")
+	w.WriteHeader(http.StatusCreated)
+}
+
+// DeleteAlbumTrack godoc
+// @Summary      Remove a track
+// @Description  Removes a song from the album tracklist. Other tracks keep their numbers.
+// @Tags         albums
+// @Param        id       path  int  true  "Album ID"
+// @Param        song_id  path  int  true  "Song ID"
+// @Success      200  {string}  string  "Track successfully removed"
+// @Failure      400  {string}  string  "Invalid album or song ID"
+// @Failure      404  {string}  string  "Song is not on the album"
+// @Failure      500  {string}  string  "Internal server error"
+// @Router       /albums/{id}/tracks/{song_id} [delete]
+func (h *Handler) DeleteAlbumTrack(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		logrus.Error(err)
+		return
+	}
+	songId, err := strconv.Atoi(r.PathValue("song_id"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		logrus.Error(err)
+		return
+	}
+
+	if err = h.service.Album.DeleteAlbumTrack(int64(id), int64(songId)); err != nil {
+		handleError(w, err)
+		return
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"id":      id,
+		"song_id": songId,
+	}).Info("album track successfully removed")
+	w.WriteHeader(http.StatusOK)
+}
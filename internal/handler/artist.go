@@ -0,0 +1,205 @@
+package handler
+
+import (
+	"BestMusicLibrary/internal/model"
+	"encoding/json"
+	"fmt"
+	"github.com/sirupsen/logrus"
+	"net/http"
+	"strconv"
+)
+
+type artistRequest struct {
+	Name          string   `json:"name"`
+	Aliases       []string `json:"aliases,omitempty"`
+	Country       string   `json:"country,omitempty"`
+	FormedYear    *int     `json:"formed_year,omitempty"`
+	DisbandedYear *int     `json:"disbanded_year,omitempty"`
+}
+
+func (r artistRequest) toArtist(id int64) model.Artist {
+	return model.Artist{
+		Id:            id,
+		Name:          r.Name,
+		Aliases:       r.Aliases,
+		Country:       r.Country,
+		FormedYear:    r.FormedYear,
+		DisbandedYear: r.DisbandedYear,
+	}
+}
+
+// GetArtists godoc
+// @Summary      Get list of artists
+// @Description  Retrieves artists ordered by name. The name filter matches the canonical name or any alias in either Cyrillic or Latin script.
+// @Tags         artists
+// @Produce      json
+// @Param        name   query  string  false  "Filter by artist name or alias"
+// @Param        page   query  int     false  "Page number for pagination"
+// @Param        limit  query  int     false  "Limit the number of artists per page"
+// @Success      200  {array}   model.Artist  "Artists"
+// @Failure      400  {string}  string  "Invalid query parameters"
+// @Failure      500  {string}  string  "Internal server error"
+// @Router       /artists [get]
+func (h *Handler) GetArtists(w http.ResponseWriter, r *http.Request) {
+	filter := model.ArtistFilter{Name: r.URL.Query().Get("name")}
+	page, limit, err := parsePagingData(r.URL.Query().Get("page"), r.URL.Query().Get("limit"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		logrus.Error(err)
+		return
+	}
+
+	artists, err := h.service.Artist.GetArtists(filter, page, limit)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+	if err = json.NewEncoder(w).Encode(artists); err != nil {
+		handleError(w, err)
+		return
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"name":  filter.Name,
+		"count": len(artists),
+	}).Info("artists successfully sent")
+}
+
+// GetArtist godoc
+// @Summary      Get an artist
+// @Description  Retrieves an artist with aliases by ID.
+// @Tags         artists
+// @Produce      json
+// @Param        id  path  int  true  "Artist ID"
+// @Success      200  {object}  model.Artist  "Artist"
+// @Failure      400  {string}  string  "Invalid artist ID"
+// @Failure      404  {string}  string  "Artist not found"
+// @Failure      500  {string}  string  "Internal server error"
+// @Router       /artists/{id} [get]
+func (h *Handler) GetArtist(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		logrus.Error(err)
+		return
+	}
+
+	artist, err := h.service.Artist.GetArtist(int64(id))
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+	if err = json.NewEncoder(w).Encode(artist); err != nil {
+		handleError(w, err)
+		return
+	}
+
+	logrus.WithField("id", id).Info("artist successfully sent")
+}
+
+// AddArtist godoc
+// @Summary      Add an artist
+// @Description  Adds an artist. Neither the name nor any alias may match the name or an alias of another artist after Unicode, quote, case and script normalization. Country is an ISO 3166-1 alpha-2 code.
+// @Tags         artists
+// @Accept       json
+// @Produce      json
+// @Param        artist  body  artistRequest  true  "Artist details"
+// @Success      201  {string}  string  "Successfully added artist with its ID"
+// @Failure      400  {string}  string  "Invalid request body"
+// @Failure      409  {string}  string  "Name or alias already belongs to another artist"
+// @Failure      500  {string}  string  "Internal server error"
+// @Router       /artists [post]
+func (h *Handler) AddArtist(w http.ResponseWriter, r *http.Request) {
+	var request artistRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		logrus.Error(err)
+		return
+	}
+
+	artistId, err := h.service.Artist.AddArtist(request.toArtist(0))
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	if _, err = fmt.Fprintf(w, "%d", artistId); err != nil {
+		handleError(w, err)
+		return
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"id":   artistId,
+		"name": request.Name,
+	}).Info("artist successfully added")
+}
+
+// UpdateArtist godoc
+// @Summary      Update an artist
+// @Description  Replaces the artist details and aliases. A new canonical name is shown in all songs of the artist.
+// @Tags         artists
+// @Accept       json
+// @Produce      json
+// @Param        id      path  int            true  "Artist ID"
+// @Param        artist  body  artistRequest  true  "Artist details"
+// @Success      200  {string}  string  "Artist successfully updated"
+// @Failure      400  {string}  string  "Invalid artist ID or request body"
+// @Failure      404  {string}  string  "Artist not found"
+// @Failure      409  {string}  string  "Name or alias already belongs to another artist"
+// @Failure      500  {string}  string  "Internal server error"
+// @Router       /artists/{id} [put]
+func (h *Handler) UpdateArtist(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		logrus.Error(err)
+		return
+	}
+
+	var request artistRequest
+	if err = json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		logrus.Error(err)
+		return
+	}
+
+	if err = h.service.Artist.UpdateArtist(request.toArtist(int64(id))); err != nil {
+		handleError(w, err)
+		return
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"id":   id,
+		"name": request.Name,
+	}).Info("artist successfully updated")
+	w.WriteHeader(http.StatusOK)
+}
+
+// DeleteArtist godoc
+// @Summary      Delete an artist
+// @Description  Deletes an artist that has no songs or albums.
+// @Tags         artists
+// @Param        id  path  int  true  "Artist ID"
+// @Success      200  {string}  string  "Artist successfully deleted"
+// @Failure      400  {string}  string  "Invalid artist ID"
+// @Failure      404  {string}  string  "Artist not found"
+// @Failure      409  {string}  string  "Artist still has songs or albums"
+// @Failure      500  {string}  string  "Internal server error"
+// @Router       /artists/{id} [delete]
+func (h *Handler) DeleteArtist(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		logrus.Error(err)
+		return
+	}
+
+	if err = h.service.Artist.DeleteArtist(int64(id)); err != nil {
+		handleError(w, err)
+		return
+	}
+
+	logrus.WithField("id", id).Info("artist successfully deleted")
+	w.WriteHeader(http.StatusOK)
+}
@@ -0,0 +1,94 @@
+package handler
+
+import (
+	"BestMusicLibrary/internal/model"
+	"encoding/json"
+	"github.com/sirupsen/logrus"
+	"net/http"
+	"strconv"
+)
+
+type songCreditRequest struct {
+	ArtistId int64  `json:"artist_id"`
+	Role     string `json:"role" enums:"primary,featured,remixer,producer,songwriter,composer"`
+}
+
+// GetSongCredits godoc
+// @Summary      Get song credits
+// @Description  Returns every artist credited on the song with their roles, the primary artist of the song first.
+// @Tags         songs
+// @Produce      json
+// @Param        id  path  int  true  "Song ID"
+// @Success      200  {array}   model.SongCredit  "Song credits"
+// @Failure      400  {string}  string  "Invalid song ID"
+// @Failure      404  {string}  string  "Song not found"
+// @Failure      500  {string}  string  "Internal server error"
+// @Router       /songs/{id}/credits [get]
+func (h *Handler) GetSongCredits(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		logrus.Error(err)
+		return
+	}
+
+	credits, err := h.service.Song.GetSongCredits(int64(id))
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+	if err = json.NewEncoder(w).Encode(credits); err != nil {
+		handleError(w, err)
+		return
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"id":    id,
+		"count": len(credits),
+	}).Info("song credits successfully sent")
+}
+
+// SetSongCredits godoc
+// @Summary      Replace song credits
+// @Description  Replaces all credits of the song. At least one primary artist is required, the first one becomes the artist shown as the song group. Primary and featured artists parsed from "feat.", "ft." and "&" in group and song names replace the primary and featured credits when the song is updated.
+// @Tags         songs
+// @Accept       json
+// @Produce      json
+// @Param        id       path  int                  true  "Song ID"
+// @Param        credits  body  []songCreditRequest  true  "Credited artists with roles"
+// @Success      200  {string}  string  "Credits successfully saved"
+// @Failure      400  {string}  string  "Invalid song ID, request body, role or unknown artist"
+// @Failure      404  {string}  string  "Song not found"
+// @Failure      409  {string}  string  "Another song of the primary artist has the same name"
+// @Failure      500  {string}  string  "Internal server error"
+// @Router       /songs/{id}/credits [put]
+func (h *Handler) SetSongCredits(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		logrus.Error(err)
+		return
+	}
+
+	var requests []songCreditRequest
+	if err = json.NewDecoder(r.Body).Decode(&requests); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		logrus.Error(err)
+		return
+	}
+	credits := make([]model.SongCredit, 0, len(requests))
+	for _, request := range requests {
+		credits = append(credits, model.SongCredit{ArtistId: request.ArtistId, Role: model.CreditRole(request.Role)})
+	}
+
+	if err = h.service.Song.SetSongCredits(int64(id), credits); err != nil {
+		handleError(w, err)
+		return
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"id":      id,
+		"credits": len(credits),
+	}).Info("song credits successfully saved")
+	w.WriteHeader(http.StatusOK)
+}
@@ -0,0 +1,52 @@
+package handler
+
+import (
+	"encoding/json"
+	"github.com/sirupsen/logrus"
+	"net/http"
+	"strconv"
+)
+
+type explicitOverrideRequest struct {
+	Explicit *bool `json:"explicit"`
+}
+
+// SetExplicitOverride godoc
+// @Summary      Override explicit flag
+// @Description  Marks a song as explicit or clean regardless of the word list detection. A null value returns the song to automatic detection, which runs on every write.
+// @Tags         songs
+// @Accept       json
+// @Produce      json
+// @Param        id        path  int                      true  "Song ID"
+// @Param        explicit  body  explicitOverrideRequest  true  "Editor decision, null for automatic detection"
+// @Success      200  {string}  string  "Explicit flag successfully saved"
+// @Failure      400  {string}  string  "Invalid song ID or request body"
+// @Failure      404  {string}  string  "Song not found"
+// @Failure      500  {string}  string  "Internal server error"
+// @Router       /songs/{id}/explicit [put]
+func (h *Handler) SetExplicitOverride(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		logrus.Error(err)
+		return
+	}
+
+	var request explicitOverrideRequest
+	if err = json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		logrus.Error(err)
+		return
+	}
+
+	if err = h.service.Song.SetExplicitOverride(int64(id), request.Explicit); err != nil {
+		handleError(w, err)
+		return
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"id":       id,
+		"explicit": request.Explicit,
+	}).Info("explicit override successfully saved")
+	w.WriteHeader(http.StatusOK)
+}
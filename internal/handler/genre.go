@@ -0,0 +1,250 @@
+package handler
+
+import (
+	"BestMusicLibrary/internal/model"
+	"encoding/json"
+	"fmt"
+	"github.com/sirupsen/logrus"
+	"net/http"
+	"strconv"
+)
+
+type genreRequest struct {
+	Name     string `json:"name"`
+	ParentId int64  `json:"parent_id,omitempty"`
+}
+
+// GetGenres godoc
+// @Summary      Get genre tree
+// @Description  Returns the whole genre hierarchy: root genres with nested subgenres, alphabetically on every level.
+// @Tags         genres
+// @Produce      json
+// @Success      200  {array}   model.Genre  "Genre tree"
+// @Failure      500  {string}  string  "Internal server error"
+// @Router       /genres [get]
+func (h *Handler) GetGenres(w http.ResponseWriter, _ *http.Request) {
+	genres, err := h.service.Genre.GetGenreTree()
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+	if err = json.NewEncoder(w).Encode(genres); err != nil {
+		handleError(w, err)
+		return
+	}
+
+	logrus.WithField("roots", len(genres)).Info("genre tree successfully sent")
+}
+
+// AddGenre godoc
+// @Summary      Add a genre
+// @Description  Adds a root genre or, with parent_id, a subgenre. Genre names are unique across the hierarchy after Unicode, quote, case and script normalization.
+// @Tags         genres
+// @Accept       json
+// @Produce      json
+// @Param        genre  body  genreRequest  true  "Genre"
+// @Success      201  {string}  string  "Successfully added genre with its ID"
+// @Failure      400  {string}  string  "Invalid request body or unknown parent genre"
+// @Failure      409  {string}  string  "Genre with the same name already exists"
+// @Failure      500  {string}  string  "Internal server error"
+// @Router       /genres [post]
+func (h *Handler) AddGenre(w http.ResponseWriter, r *http.Request) {
+	var request genreRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		logrus.Error(err)
+		return
+	}
+
+	genreId, err := h.service.Genre.AddGenre(model.Genre{Name: request.Name, ParentId: request.ParentId})
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	if _, err = fmt.Fprintf(w, "%d", genreId); err != nil {
+		handleError(w, err)
+		return
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"id":   genreId,
+		"name": request.Name,
+	}).Info("genre successfully added")
+}
+
+// UpdateGenre godoc
+// @Summary      Update a genre
+// @Description  Renames a genre or moves it under another parent. Without parent_id the genre becomes a root genre. A genre cannot be moved under itself or its own subgenre.
+// @Tags         genres
+// @Accept       json
+// @Produce      json
+// @Param        id     path  int           true  "Genre ID"
+// @Param        genre  body  genreRequest  true  "Genre"
+// @Success      200  {string}  string  "Genre successfully updated"
+// @Failure      400  {string}  string  "Invalid genre ID, request body, unknown parent or cycle"
+// @Failure      404  {string}  string  "Genre not found"
+// @Failure      409  {string}  string  "Genre with the same name already exists"
+// @Failure      500  {string}  string  "Internal server error"
+// @Router       /genres/{id} [put]
+func (h *Handler) UpdateGenre(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		logrus.Error(err)
+		return
+	}
+
+	var request genreRequest
+	if err = json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		logrus.Error(err)
+		return
+	}
+
+	if err = h.service.Genre.UpdateGenre(model.Genre{Id: int64(id), Name: request.Name, ParentId: request.ParentId}); err != nil {
+		handleError(w, err)
+		return
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"id":        id,
+		"name":      request.Name,
+		"parent_id": request.ParentId,
+	}).Info("genre successfully updated")
+	w.WriteHeader(http.StatusOK)
+}
+
+// DeleteGenre godoc
+// @Summary      Delete a genre
+// @Description  Deletes a genre without subgenres that no smart playlist rule refers to. Songs of the genre lose only this genre.
+// @Tags         genres
+// @Param        id  path  int  true  "Genre ID"
+// @Success      200  {string}  string  "Genre successfully deleted"
+// @Failure      400  {string}  string  "Invalid genre ID"
+// @Failure      404  {string}  string  "Genre not found"
+// @Failure      409  {string}  string  "Genre has subgenres or is used by smart playlists"
+// @Failure      500  {string}  string  "Internal server error"
+// @Router       /genres/{id} [delete]
+func (h *Handler) DeleteGenre(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		logrus.Error(err)
+		return
+	}
+
+	if err = h.service.Genre.DeleteGenre(int64(id)); err != nil {
+		handleError(w, err)
+		return
+	}
+
+	logrus.WithField("id", id).Info("genre successfully deleted")
+	w.WriteHeader(http.StatusOK)
+}
+
+// GetSongGenres godoc
+// @Summary      Get song genres
+// @Description  Returns the genres assigned to the song.
+// @Tags         genres
+// @Produce      json
+// @Param        id  path  int  true  "Song ID"
+// @Success      200  {array}   model.Genre  "Song genres"
+// @Failure      400  {string}  string  "Invalid song ID"
+// @Failure      404  {string}  string  "Song not found"
+// @Failure      500  {string}  string  "Internal server error"
+// @Router       /songs/{id}/genres [get]
+func (h *Handler) GetSongGenres(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		logrus.Error(err)
+		return
+	}
+
+	genres, err := h.service.Genre.GetSongGenres(int64(id))
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+	if err = json.NewEncoder(w).Encode(genres); err != nil {
+		handleError(w, err)
+		return
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"id":    id,
+		"count": len(genres),
+	}).Info("song genres successfully sent")
+}
+
+// AddSongGenre godoc
+// @Summary      Assign a genre
+// @Description  Assigns a genre to the song. Assigning a genre twice changes nothing.
+// @Tags         genres
+// @Param        id        path  int  true  "Song ID"
+// @Param        genre_id  path  int  true  "Genre ID"
+// @Success      200  {string}  string  "Genre successfully assigned"
+// @Failure      400  {string}  string  "Invalid song or genre ID"
+// @Failure      404  {string}  string  "Song or genre not found"
+// @Failure      500  {string}  string  "Internal server error"
+// @Router       /songs/{id}/genres/{genre_id} [put]
+func (h *Handler) AddSongGenre(w http.ResponseWriter, r *http.Request) {
+	id, genreId, err := parseSongGenrePath(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		logrus.Error(err)
+		return
+	}
+
+	if err = h.service.Genre.AddSongGenre(id, genreId); err != nil {
+		handleError(w, err)
+		return
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"id":       id,
+		"genre_id": genreId,
+	}).Info("song genre successfully assigned")
+	w.WriteHeader(http.StatusOK)
+}
+
+// DeleteSongGenre godoc
+// @Summary      Unassign a genre
+// @Description  Removes a genre from the song.
+// @Tags         genres
+// @Param        id        path  int  true  "Song ID"
+// @Param        genre_id  path  int  true  "Genre ID"
+// @Success      200  {string}  string  "Genre successfully unassigned"
+// @Failure      400  {string}  string  "Invalid song or genre ID"
+// @Failure      404  {string}  string  "Song does not have the genre"
+// @Failure      500  {string}  string  "Internal server error"
+// @Router       /songs/{id}/genres/{genre_id} [delete]
+func (h *Handler) DeleteSongGenre(w http.ResponseWriter, r *http.Request) {
+	id, genreId, err := parseSongGenrePath(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		logrus.Error(err)
+		return
+	}
+
+	if err = h.service.Genre.DeleteSongGenre(id, genreId); err != nil {
+		handleError(w, err)
+		return
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"id":       id,
+		"genre_id": genreId,
+	}).Info("song genre successfully unassigned")
+	w.WriteHeader(http.StatusOK)
+}
+
+func parseSongGenrePath(r *http.Request) (songId, genreId int64, err error) {
+	if songId, err = strconv.ParseInt(r.PathValue("id"), 10, 64); err != nil {
+		return
+	}
+	genreId, err = strconv.ParseInt(r.PathValue("genre_id"), 10, 64)
+	return
+}
@@ -1,7 +1,9 @@
 package handler
 
 import (
+	"BestMusicLibrary/internal/model"
 	"BestMusicLibrary/internal/service"
+	"errors"
 	"github.com/sirupsen/logrus"
 	"net/http"
 )
@@ -10,12 +12,70 @@ type Handler struct {
 	service *service.Service
 }
 
+// InitRoutes Регистрация маршрутов с методом в шаблоне: на запрос другим методом mux отвечает 405 Method Not Allowed
+// с заголовком Allow
 func (h *Handler) InitRoutes() {
-	http.HandleFunc("/songs/get", h.GetSongs)
-	http.HandleFunc("/songs/add", h.AddSong)
-	http.HandleFunc("/songs/delete", h.DeleteSong)
-	http.HandleFunc("/songs/update", h.UpdateSong)
-	http.HandleFunc("/songs/verses", h.GetSongVerses)
+	http.HandleFunc("GET /songs/get", h.GetSongs)
+	http.HandleFunc("POST /songs/add", h.AddSong)
+	http.HandleFunc("DELETE /songs/delete", h.DeleteSong)
+	http.HandleFunc("PUT /songs/update", h.UpdateSong)
+	http.HandleFunc("GET /songs/verses", h.GetSongVerses)
+	http.HandleFunc("GET /songs/verses/lines", h.GetVerseLines)
+	http.HandleFunc("GET /songs/sources", h.GetSongSources)
+	http.HandleFunc("POST /songs/{id}/enrich", h.EnrichSong)
+	http.HandleFunc("POST /songs/{id}/lyrics/import", h.ImportSyncedLyrics)
+	http.HandleFunc("GET /songs/{id}/lyrics.lrc", h.ExportLyricsLRC)
+	http.HandleFunc("GET /songs/{id}/lyrics.vtt", h.ExportLyricsWebVTT)
+	http.HandleFunc("PUT /songs/{id}/translations", h.SetTranslation)
+	http.HandleFunc("PUT /songs/{id}/explicit", h.SetExplicitOverride)
+	http.HandleFunc("GET /songs/{id}/stats", h.GetSongStats)
+	http.HandleFunc("GET /songs/{id}/similar", h.GetSimilarSongs)
+	http.HandleFunc("GET /songs/{id}/credits", h.GetSongCredits)
+	http.HandleFunc("PUT /songs/{id}/credits", h.SetSongCredits)
+	http.HandleFunc("GET /songs/{id}/genres", h.GetSongGenres)
+	http.HandleFunc("PUT /songs/{id}/genres/{genre_id}", h.AddSongGenre)
+	http.HandleFunc("DELETE /songs/{id}/genres/{genre_id}", h.DeleteSongGenre)
+	http.HandleFunc("GET /songs/{id}/tags", h.GetSongTags)
+	http.HandleFunc("POST /songs/{id}/tags", h.AddSongTag)
+	http.HandleFunc("DELETE /songs/{id}/tags/{tag}", h.DeleteSongTag)
+	http.HandleFunc("GET /artists", h.GetArtists)
+	http.HandleFunc("POST /artists", h.AddArtist)
+	http.HandleFunc("GET /artists/{id}", h.GetArtist)
+	http.HandleFunc("PUT /artists/{id}", h.UpdateArtist)
+	http.HandleFunc("DELETE /artists/{id}", h.DeleteArtist)
+	http.HandleFunc("GET /albums", h.GetAlbums)
+	http.HandleFunc("POST /albums", h.AddAlbum)
+	http.HandleFunc("GET /albums/{id}", h.GetAlbum)
+	http.HandleFunc("PUT /albums/{id}", h.UpdateAlbum)
+	http.HandleFunc("DELETE /albums/{id}", h.DeleteAlbum)
+	http.HandleFunc("PUT /albums/{id}/tracks", h.ReplaceAlbumTracks)
+	http.HandleFunc("POST /albums/{id}/tracks", h.AddAlbumTrack)
+	http.HandleFunc("DELETE /albums/{id}/tracks/{song_id}", h.DeleteAlbumTrack)
+	http.HandleFunc("GET /genres", h.GetGenres)
+	http.HandleFunc("POST /genres", h.AddGenre)
+	http.HandleFunc("PUT /genres/{id}", h.UpdateGenre)
+	http.HandleFunc("DELETE /genres/{id}", h.DeleteGenre)
+	http.HandleFunc("GET /tags", h.GetTagCounts)
+	http.HandleFunc("GET /tags/autocomplete", h.SuggestTags)
+	http.HandleFunc("GET /playlists", h.GetPlaylists)
+	http.HandleFunc("POST /playlists", h.AddPlaylist)
+	http.HandleFunc("GET /playlists/{id}", h.GetPlaylist)
+	http.HandleFunc("PUT /playlists/{id}", h.UpdatePlaylist)
+	http.HandleFunc("DELETE /playlists/{id}", h.DeletePlaylist)
+	http.HandleFunc("POST /playlists/{id}/entries", h.AddPlaylistEntry)
+	http.HandleFunc("PUT /playlists/{id}/entries/{entry_id}", h.MovePlaylistEntry)
+	http.HandleFunc("DELETE /playlists/{id}/entries/{entry_id}", h.DeletePlaylistEntry)
+	http.HandleFunc("GET /smart-playlists", h.GetSmartPlaylists)
+	http.HandleFunc("POST /smart-playlists", h.AddSmartPlaylist)
+	http.HandleFunc("POST /smart-playlists/preview", h.PreviewSmartPlaylist)
+	http.HandleFunc("GET /smart-playlists/{id}", h.GetSmartPlaylist)
+	http.HandleFunc("PUT /smart-playlists/{id}", h.UpdateSmartPlaylist)
+	http.HandleFunc("DELETE /smart-playlists/{id}", h.DeleteSmartPlaylist)
+	http.HandleFunc("GET /smart-playlists/{id}/songs", h.GetSmartPlaylistSongs)
+	http.HandleFunc("GET /stats/groups", h.GetSongsPerGroup)
+	http.HandleFunc("GET /stats/release-periods", h.GetSongsPerReleasePeriod)
+	http.HandleFunc("GET /stats/lyrics-length", h.GetLyricsLengthReport)
+	http.HandleFunc("GET /stats/coverage", h.GetEnrichmentCoverage)
 }
 
 func NewHandler(service *service.Service) *Handler {
@@ -23,6 +83,15 @@ func NewHandler(service *service.Service) *Handler {
 }
 
 func handleError(w http.ResponseWriter, err error) {
-	http.Error(w, err.Error(), http.StatusInternalServerError)
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, model.ErrNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, model.ErrInvalidInput):
+		status = http.StatusBadRequest
+	case errors.Is(err, model.ErrConflict):
+		status = http.StatusConflict
+	}
+	http.Error(w, err.Error(), status)
 	logrus.Error(err)
 }
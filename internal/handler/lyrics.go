@@ -0,0 +1,161 @@
+package handler
+
+import (
+	"BestMusicLibrary/internal/service"
+	"encoding/json"
+	"fmt"
+	"github.com/sirupsen/logrus"
+	"io"
+	"net/http"
+	"strconv"
+)
+
+// maxLyricsSize Максимальный размер загружаемого LRC-файла
+const maxLyricsSize = 1 << 20
+
+// ImportSyncedLyrics godoc
+// @Summary      Import synced lyrics
+// @Description  Attaches timestamps from an LRC file to the lines of a song. Enhanced LRC per-word timings are kept. LRC lines are matched in order with the lines of the expanded lyrics ignoring case and punctuation, previous timings of the song are replaced. Overlapping or non-monotonic timestamps are rejected.
+// @Tags         lyrics
+// @Accept       plain
+// @Produce      json
+// @Param        id    path  int     true  "Song ID"
+// @Param        lrc   body  string  true  "LRC file"
+// @Success      200  {array}   model.TimedLine  "Imported line timings"
+// @Failure      400  {string}  string  "Invalid LRC"
+// @Failure      404  {string}  string  "Song not found"
+// @Failure      500  {string}  string  "Internal server error"
+// @Router       /songs/{id}/lyrics/import [post]
+func (h *Handler) ImportSyncedLyrics(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		logrus.Error(err)
+		return
+	}
+
+	lrc, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxLyricsSize))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		logrus.Error(err)
+		return
+	}
+
+	lines, err := h.service.Song.ImportSyncedLyrics(int64(id), string(lrc))
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"id":    id,
+		"lines": len(lines),
+	}).Info("synced lyrics successfully imported")
+
+	err = json.NewEncoder(w).Encode(lines)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+}
+
+// ExportLyricsLRC godoc
+// @Summary      Export synced lyrics as LRC
+// @Description  Returns the synced lyrics of a song in enhanced LRC format.
+// @Tags         lyrics
+// @Produce      plain
+// @Param        id   path  int  true  "Song ID"
+// @Success      200  {string}  string  "LRC file"
+// @Failure      404  {string}  string  "Song or synced lyrics not found"
+// @Failure      500  {string}  string  "Internal server error"
+// @Router       /songs/{id}/lyrics.lrc [get]
+func (h *Handler) ExportLyricsLRC(w http.ResponseWriter, r *http.Request) {
+	h.exportSyncedLyrics(w, r, service.LyricsFormatLRC, "text/plain; charset=utf-8")
+}
+
+// ExportLyricsWebVTT godoc
+// @Summary      Export synced lyrics as WebVTT
+// @Description  Returns the synced lyrics of a song as WebVTT subtitles, per-word timings become cue timestamps.
+// @Tags         lyrics
+// @Produce      plain
+// @Param        id   path  int  true  "Song ID"
+// @Success      200  {string}  string  "WebVTT file"
+// @Failure      404  {string}  string  "Song or synced lyrics not found"
+// @Failure      500  {string}  string  "Internal server error"
+// @Router       /songs/{id}/lyrics.vtt [get]
+func (h *Handler) ExportLyricsWebVTT(w http.ResponseWriter, r *http.Request) {
+	h.exportSyncedLyrics(w, r, service.LyricsFormatWebVTT, "text/vtt; charset=utf-8")
+}
+
+func (h *Handler) exportSyncedLyrics(w http.ResponseWriter, r *http.Request, format service.LyricsFormat, contentType string) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		logrus.Error(err)
+		return
+	}
+
+	lyrics, err := h.service.Song.ExportSyncedLyrics(int64(id), format)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	_, err = fmt.Fprint(w, lyrics)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"id":     id,
+		"format": format,
+	}).Info("response successfully sent")
+}
+
+type translationRequest struct {
+	Text string `json:"text"`
+}
+
+// SetTranslation godoc
+// @Summary      Set lyrics translation
+// @Description  Stores a translation of the song lyrics into the language given by a BCP 47 tag. Verses of the translation are aligned with the original in order, the text may either follow the expanded lyrics or contain every distinct verse once. An empty text removes the translation.
+// @Tags         lyrics
+// @Accept       json
+// @Produce      json
+// @Param        id           path   int                 true  "Song ID"
+// @Param        lang         query  string              true  "BCP 47 language tag of the translation"
+// @Param        translation  body   translationRequest  true  "Translated lyrics"
+// @Success      200  {string}  string  "Translation successfully saved"
+// @Failure      400  {string}  string  "Invalid language, verse count"
+// @Failure      404  {string}  string  "Song not found"
+// @Failure      500  {string}  string  "Internal server error"
+// @Router       /songs/{id}/translations [put]
+func (h *Handler) SetTranslation(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		logrus.Error(err)
+		return
+	}
+	lang := r.URL.Query().Get("lang")
+
+	var translation translationRequest
+	if err = json.NewDecoder(r.Body).Decode(&translation); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		logrus.Error(err)
+		return
+	}
+
+	if err = h.service.Song.SetTranslation(int64(id), lang, translation.Text); err != nil {
+		handleError(w, err)
+		return
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"id":   id,
+		"lang": lang,
+	}).Info("translation successfully saved")
+	w.WriteHeader(http.StatusOK)
+}
@@ -0,0 +1,343 @@
+package handler
+
+import (
+	"BestMusicLibrary/internal/model"
+	"encoding/json"
+	"fmt"
+	"github.com/sirupsen/logrus"
+	"net/http"
+	"strconv"
+)
+
+type playlistRequest struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Owner       string `json:"owner"`
+	Public      bool   `json:"public"`
+}
+
+func (r playlistRequest) toPlaylist(id int64) model.Playlist {
+	return model.Playlist{Id: id, Name: r.Name, Description: r.Description, Owner: r.Owner, Public: r.Public}
+}
+
+// callerHeader Заголовок с именем пользователя, от которого выполняется запрос. Закрытые плейлисты
+// видны только пользователю, совпадающему с владельцем
+const callerHeader = "X-User"
+
+// playlistEntryRequest Без after_entry_id запись попадает в конец плейлиста, с нулем — в начало
+type playlistEntryRequest struct {
+	SongId       int64  `json:"song_id,omitempty"`
+	AfterEntryId *int64 `json:"after_entry_id,omitempty"`
+}
+
+// GetPlaylists godoc
+// @Summary      Get list of playlists
+// @Description  Retrieves public playlists and private playlists of the caller without entries, newest first. With owner returns only playlists of the owner.
+// @Tags         playlists
+// @Produce      json
+// @Param        X-User  header  string  false  "Caller, private playlists are visible only to their owner"
+// @Param        owner   query   string  false  "Playlist owner"
+// @Param        page    query   int     false  "Page number for pagination"
+// @Param        limit   query   int     false  "Limit the number of playlists per page"
+// @Success      200  {array}   model.Playlist  "Playlists"
+// @Failure      400  {string}  string  "Invalid query parameters"
+// @Failure      500  {string}  string  "Internal server error"
+// @Router       /playlists [get]
+func (h *Handler) GetPlaylists(w http.ResponseWriter, r *http.Request) {
+	filter := model.PlaylistFilter{Owner: r.URL.Query().Get("owner"), Caller: r.Header.Get(callerHeader)}
+	page, limit, err := parsePagingData(r.URL.Query().Get("page"), r.URL.Query().Get("limit"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		logrus.Error(err)
+		return
+	}
+
+	playlists, err := h.service.Playlist.GetPlaylists(filter, page, limit)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+	if err = json.NewEncoder(w).Encode(playlists); err != nil {
+		handleError(w, err)
+		return
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"owner": filter.Owner,
+		"count": len(playlists),
+	}).Info("playlists successfully sent")
+}
+
+// GetPlaylist godoc
+// @Summary      Get a playlist
+// @Description  Retrieves a playlist with its entries in order. Private playlists of other users are not found.
+// @Tags         playlists
+// @Produce      json
+// @Param        id      path    int     true   "Playlist ID"
+// @Param        X-User  header  string  false  "Caller, private playlists are visible only to their owner"
+// @Success      200  {object}  model.Playlist  "Playlist with entries"
+// @Failure      400  {string}  string  "Invalid playlist ID"
+// @Failure      404  {string}  string  "Playlist not found"
+// @Failure      500  {string}  string  "Internal server error"
+// @Router       /playlists/{id} [get]
+func (h *Handler) GetPlaylist(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		logrus.Error(err)
+		return
+	}
+
+	playlist, err := h.service.Playlist.GetPlaylist(int64(id), r.Header.Get(callerHeader))
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+	if err = json.NewEncoder(w).Encode(playlist); err != nil {
+		handleError(w, err)
+		return
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"id":      id,
+		"entries": len(playlist.Entries),
+	}).Info("playlist successfully sent")
+}
+
+// AddPlaylist godoc
+// @Summary      Add a playlist
+// @Description  Adds an empty playlist. Name and owner are required, playlists are private by default.
+// @Tags         playlists
+// @Accept       json
+// @Produce      json
+// @Param        playlist  body  playlistRequest  true  "Playlist details"
+// @Success      201  {string}  string  "Successfully added playlist with its ID"
+// @Failure      400  {string}  string  "Invalid request body"
+// @Failure      500  {string}  string  "Internal server error"
+// @Router       /playlists [post]
+func (h *Handler) AddPlaylist(w http.ResponseWriter, r *http.Request) {
+	var request playlistRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		logrus.Error(err)
+		return
+	}
+
+	playlistId, err := h.service.Playlist.AddPlaylist(request.toPlaylist(0))
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	if _, err = fmt.Fprintf(w, "%d", playlistId); err != nil {
+		handleError(w, err)
+		return
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"id":    playlistId,
+		"name":  request.Name,
+		"owner": request.Owner,
+	}).Info("playlist successfully added")
+}
+
+// UpdatePlaylist godoc
+// @Summary      Update a playlist
+// @Description  Replaces the playlist name, description, owner and visibility. The entries are not changed. Only the owner may change a playlist, for other callers it is not found.
+// @Tags         playlists
+// @Accept       json
+// @Produce      json
+// @Param        id        path    int              true  "Playlist ID"
+// @Param        playlist  body    playlistRequest  true  "Playlist details"
+// @Param        X-User    header  string           true  "Caller, only the owner may change the playlist"
+// @Success      200  {string}  string  "Playlist successfully updated"
+// @Failure      400  {string}  string  "Invalid playlist ID or request body"
+// @Failure      404  {string}  string  "Playlist of the caller not found"
+// @Failure      500  {string}  string  "Internal server error"
+// @Router       /playlists/{id} [put]
+func (h *Handler) UpdatePlaylist(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		logrus.Error(err)
+		return
+	}
+
+	var request playlistRequest
+	if err = json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		logrus.Error(err)
+		return
+	}
+
+	if err = h.service.Playlist.UpdatePlaylist(request.toPlaylist(int64(id)), r.Header.Get(callerHeader)); err != nil {
+		handleError(w, err)
+		return
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"id":   id,
+		"name": request.Name,
+	}).Info("playlist successfully updated")
+	w.WriteHeader(http.StatusOK)
+}
+
+// DeletePlaylist godoc
+// @Summary      Delete a playlist
+// @Description  Deletes a playlist and its entries. The songs stay in the library. Only the owner may delete a playlist, for other callers it is not found.
+// @Tags         playlists
+// @Param        id      path    int     true  "Playlist ID"
+// @Param        X-User  header  string  true  "Caller, only the owner may delete the playlist"
+// @Success      200  {string}  string  "Playlist successfully deleted"
+// @Failure      400  {string}  string  "Invalid playlist ID"
+// @Failure      404  {string}  string  "Playlist of the caller not found"
+// @Failure      500  {string}  string  "Internal server error"
+// @Router       /playlists/{id} [delete]
+func (h *Handler) DeletePlaylist(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		logrus.Error(err)
+		return
+	}
+
+	if err = h.service.Playlist.DeletePlaylist(int64(id), r.Header.Get(callerHeader)); err != nil {
+		handleError(w, err)
+		return
+	}
+
+	logrus.WithField("id", id).Info("playlist successfully deleted")
+	w.WriteHeader(http.StatusOK)
+}
+
+// AddPlaylistEntry godoc
+// @Summary      Add an entry
+// @Description  Adds a song to the playlist after the entry after_entry_id. Without after_entry_id the song goes to the end, with 0 to the beginning. A song may appear in a playlist several times. Positions of other entries do not change. Only the owner may change entries, for other callers the playlist is not found.
+// @Tags         playlists
+// @Accept       json
+// @Produce      json
+// @Param        id      path    int                   true  "Playlist ID"
+// @Param        entry   body    playlistEntryRequest  true  "Entry"
+// @Param        X-User  header  string                true  "Caller, only the owner may change the playlist"
+// @Success      201  {string}  string  "Successfully added entry with its ID"
+// @Failure      400  {string}  string  "Invalid playlist ID, request body, unknown song or entry"
+// @Failure      404  {string}  string  "Playlist of the caller not found"
+// @Failure      500  {string}  string  "Internal server error"
+// @Router       /playlists/{id}/entries [post]
+func (h *Handler) AddPlaylistEntry(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		logrus.Error(err)
+		return
+	}
+
+	var request playlistEntryRequest
+	if err = json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		logrus.Error(err)
+		return
+	}
+
+	entryId, err := h.service.Playlist.AddPlaylistEntry(int64(id), r.Header.Get(callerHeader), request.SongId, request.AfterEntryId)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	if _, err = fmt.Fprintf(w, "%d", entryId); err != nil {
+		handleError(w, err)
+		return
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"id":       id,
+		"song_id":  request.SongId,
+		"entry_id": entryId,
+	}).Info("playlist entry successfully added")
+}
+
+// MovePlaylistEntry godoc
+// @Summary      Move an entry
+// @Description  Moves an entry after the entry after_entry_id, to the beginning with 0 or to the end without after_entry_id. song_id is ignored. Concurrent changes of one playlist are applied one by one. Only the owner may move entries.
+// @Tags         playlists
+// @Accept       json
+// @Produce      json
+// @Param        id        path    int                   true  "Playlist ID"
+// @Param        entry_id  path    int                   true  "Entry ID"
+// @Param        entry     body    playlistEntryRequest  true  "New place of the entry"
+// @Param        X-User    header  string                true  "Caller, only the owner may change the playlist"
+// @Success      200  {string}  string  "Entry successfully moved"
+// @Failure      400  {string}  string  "Invalid playlist or entry ID, request body or unknown after_entry_id"
+// @Failure      404  {string}  string  "Playlist of the caller or entry not found"
+// @Failure      500  {string}  string  "Internal server error"
+// @Router       /playlists/{id}/entries/{entry_id} [put]
+func (h *Handler) MovePlaylistEntry(w http.ResponseWriter, r *http.Request) {
+	id, entryId, err := parsePlaylistEntryPath(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		logrus.Error(err)
+		return
+	}
+
+	var request playlistEntryRequest
+	if err = json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		logrus.Error(err)
+		return
+	}
+
+	if err = h.service.Playlist.MovePlaylistEntry(id, r.Header.Get(callerHeader), entryId, request.AfterEntryId); err != nil {
+		handleError(w, err)
+		return
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"id":             id,
+		"entry_id":       entryId,
+		"after_entry_id": request.AfterEntryId,
+	}).Info("playlist entry successfully moved")
+	w.WriteHeader(http.StatusOK)
+}
+
+// DeletePlaylistEntry godoc
+// @Summary      Remove an entry
+// @Description  Removes an entry from the playlist. Other entries keep their positions. Only the owner may remove entries.
+// @Tags         playlists
+// @Param        id        path    int     true  "Playlist ID"
+// @Param        entry_id  path    int     true  "Entry ID"
+// @Param        X-User    header  string  true  "Caller, only the owner may change the playlist"
+// @Success      200  {string}  string  "Entry successfully removed"
+// @Failure      400  {string}  string  "Invalid playlist or entry ID"
+// @Failure      404  {string}  string  "Playlist of the caller or entry not found"
+// @Failure      500  {string}  string  "Internal server error"
+// @Router       /playlists/{id}/entries/{entry_id} [delete]
+func (h *Handler) DeletePlaylistEntry(w http.ResponseWriter, r *http.Request) {
+	id, entryId, err := parsePlaylistEntryPath(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		logrus.Error(err)
+		return
+	}
+
+	if err = h.service.Playlist.DeletePlaylistEntry(id, r.Header.Get(callerHeader), entryId); err != nil {
+		handleError(w, err)
+		return
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"id":       id,
+		"entry_id": entryId,
+	}).Info("playlist entry successfully removed")
+	w.WriteHeader(http.StatusOK)
+}
+
+func parsePlaylistEntryPath(r *http.Request) (playlistId, entryId int64, err error) {
+	if playlistId, err = strconv.ParseInt(r.PathValue("id"), 10, 64); err != nil {
+		return
+	}
+	entryId, err = strconv.ParseInt(r.PathValue("entry_id"), 10, 64)
+	return
+}
@@ -0,0 +1,250 @@
+package handler
+
+import (
+	"BestMusicLibrary/internal/model"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"github.com/sirupsen/logrus"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// reportFormatCSV Значение параметра format для выгрузки отчета в CSV
+const reportFormatCSV = "csv"
+
+// GetSongsPerGroup godoc
+// @Summary      Songs per group
+// @Description  Counts songs of every group, groups with the most songs first. Spellings of a group that share a search key are counted together. Accepts the song list filters, format=csv returns a spreadsheet.
+// @Tags         stats
+// @Produce      json
+// @Produce      text/csv
+// @Param        group     query  string  false  "Filter by group name"
+// @Param        song      query  string  false  "Filter by song name"
+// @Param        language  query  string  false  "Filter by BCP 47 language tag"
+// @Param        explicit  query  bool    false  "Filter by explicit content"
+// @Param        album_id  query  int     false  "Filter by album"
+// @Param        artist_id  query  int    false  "Filter by credited artist"
+// @Param        genre_id  query  int     false  "Filter by genre including subgenres"
+// @Param        tag       query  string  false  "Filter by tag"
+// @Param        released_from  query  string  false  "Filter by release date from, YYYY-MM-DD"
+// @Param        released_to    query  string  false  "Filter by release date to, YYYY-MM-DD"
+// @Param        limit     query  int     false  "Number of groups, all groups when omitted"
+// @Param        format    query  string  false  "json or csv"
+// @Success      200  {array}   model.GroupSongCount  "Songs per group"
+// @Failure      400  {string}  string  "Invalid query parameters"
+// @Failure      500  {string}  string  "Internal server error"
+// @Router       /stats/groups [get]
+func (h *Handler) GetSongsPerGroup(w http.ResponseWriter, r *http.Request) {
+	filter, format, ok := parseReportRequest(w, r)
+	if !ok {
+		return
+	}
+	limit := 0
+	if rawLimit := r.URL.Query().Get("limit"); rawLimit != "" {
+		var err error
+		if limit, err = strconv.Atoi(rawLimit); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			logrus.Error(err)
+			return
+		}
+	}
+
+	counts, err := h.service.Report.SongsPerGroup(filter, limit)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	records := make([][]string, 0, len(counts))
+	for _, count := range counts {
+		records = append(records, []string{count.Group, strconv.Itoa(count.SongCount)})
+	}
+	writeReport(w, format, counts, []string{"group", "song_count"}, records)
+}
+
+// GetSongsPerReleasePeriod godoc
+// @Summary      Songs per release year or decade
+// @Description  Counts songs with a known release date per year or per decade, a decade is identified by its first year. Accepts the song list filters, format=csv returns a spreadsheet.
+// @Tags         stats
+// @Produce      json
+// @Produce      text/csv
+// @Param        group     query  string  false  "Filter by group name"
+// @Param        song      query  string  false  "Filter by song name"
+// @Param        language  query  string  false  "Filter by BCP 47 language tag"
+// @Param        explicit  query  bool    false  "Filter by explicit content"
+// @Param        album_id  query  int     false  "Filter by album"
+// @Param        artist_id  query  int    false  "Filter by credited artist"
+// @Param        genre_id  query  int     false  "Filter by genre including subgenres"
+// @Param        tag       query  string  false  "Filter by tag"
+// @Param        released_from  query  string  false  "Filter by release date from, YYYY-MM-DD"
+// @Param        released_to    query  string  false  "Filter by release date to, YYYY-MM-DD"
+// @Param        period    query  string  false  "year (default) or decade"
+// @Param        format    query  string  false  "json or csv"
+// @Success      200  {array}   model.ReleasePeriodCount  "Songs per period"
+// @Failure      400  {string}  string  "Invalid query parameters"
+// @Failure      500  {string}  string  "Internal server error"
+// @Router       /stats/release-periods [get]
+func (h *Handler) GetSongsPerReleasePeriod(w http.ResponseWriter, r *http.Request) {
+	filter, format, ok := parseReportRequest(w, r)
+	if !ok {
+		return
+	}
+
+	counts, err := h.service.Report.SongsPerReleasePeriod(filter, model.ReleasePeriod(r.URL.Query().Get("period")))
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	records := make([][]string, 0, len(counts))
+	for _, count := range counts {
+		records = append(records, []string{strconv.Itoa(count.Year), strconv.Itoa(count.SongCount)})
+	}
+	writeReport(w, format, counts, []string{"year", "song_count"}, records)
+}
+
+// GetLyricsLengthReport godoc
+// @Summary      Average lyrics length
+// @Description  Averages verse, line, word and character counts over songs that have lyrics, verses are counted in performance order. Accepts the song list filters, format=csv returns a spreadsheet.
+// @Tags         stats
+// @Produce      json
+// @Produce      text/csv
+// @Param        group     query  string  false  "Filter by group name"
+// @Param        song      query  string  false  "Filter by song name"
+// @Param        language  query  string  false  "Filter by BCP 47 language tag"
+// @Param        explicit  query  bool    false  "Filter by explicit content"
+// @Param        album_id  query  int     false  "Filter by album"
+// @Param        artist_id  query  int    false  "Filter by credited artist"
+// @Param        genre_id  query  int     false  "Filter by genre including subgenres"
+// @Param        tag       query  string  false  "Filter by tag"
+// @Param        released_from  query  string  false  "Filter by release date from, YYYY-MM-DD"
+// @Param        released_to    query  string  false  "Filter by release date to, YYYY-MM-DD"
+// @Param        format    query  string  false  "json or csv"
+// @Success      200  {object}  model.LyricsLengthReport  "Average lyrics length"
+// @Failure      400  {string}  string  "Invalid query parameters"
+// @Failure      500  {string}  string  "Internal server error"
+// @Router       /stats/lyrics-length [get]
+func (h *Handler) GetLyricsLengthReport(w http.ResponseWriter, r *http.Request) {
+	filter, format, ok := parseReportRequest(w, r)
+	if !ok {
+		return
+	}
+
+	report, err := h.service.Report.LyricsLength(filter)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	writeReport(w, format, report,
+		[]string{"song_count", "average_verses", "average_lines", "average_words", "average_characters"},
+		[][]string{{
+			strconv.Itoa(report.SongCount),
+			formatAverage(report.AverageVerses),
+			formatAverage(report.AverageLines),
+			formatAverage(report.AverageWords),
+			formatAverage(report.AverageCharacters),
+		}})
+}
+
+// GetEnrichmentCoverage godoc
+// @Summary      Enrichment coverage
+// @Description  Counts songs and songs missing a release date, a link or lyrics. Accepts the song list filters, format=csv returns a spreadsheet.
+// @Tags         stats
+// @Produce      json
+// @Produce      text/csv
+// @Param        group     query  string  false  "Filter by group name"
+// @Param        song      query  string  false  "Filter by song name"
+// @Param        language  query  string  false  "Filter by BCP 47 language tag"
+// @Param        explicit  query  bool    false  "Filter by explicit content"
+// @Param        album_id  query  int     false  "Filter by album"
+// @Param        artist_id  query  int    false  "Filter by credited artist"
+// @Param        genre_id  query  int     false  "Filter by genre including subgenres"
+// @Param        tag       query  string  false  "Filter by tag"
+// @Param        released_from  query  string  false  "Filter by release date from, YYYY-MM-DD"
+// @Param        released_to    query  string  false  "Filter by release date to, YYYY-MM-DD"
+// @Param        format    query  string  false  "json or csv"
+// @Success      200  {object}  model.EnrichmentCoverage  "Enrichment coverage"
+// @Failure      400  {string}  string  "Invalid query parameters"
+// @Failure      500  {string}  string  "Internal server error"
+// @Router       /stats/coverage [get]
+func (h *Handler) GetEnrichmentCoverage(w http.ResponseWriter, r *http.Request) {
+	filter, format, ok := parseReportRequest(w, r)
+	if !ok {
+		return
+	}
+
+	coverage, err := h.service.Report.EnrichmentCoverage(filter)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	writeReport(w, format, coverage,
+		[]string{"song_count", "missing_release_date", "missing_link", "missing_lyrics"},
+		[][]string{{
+			strconv.Itoa(coverage.SongCount),
+			strconv.Itoa(coverage.MissingReleaseDate),
+			strconv.Itoa(coverage.MissingLink),
+			strconv.Itoa(coverage.MissingLyrics),
+		}})
+}
+
+// parseReportRequest Фильтр списка песен и формат отчета из параметров запроса. При ошибке ответ уже отправлен
+func parseReportRequest(w http.ResponseWriter, r *http.Request) (model.SongFilter, string, bool) {
+	format := r.URL.Query().Get("format")
+	if format != "" && format != "json" && format != reportFormatCSV {
+		err := fmt.Errorf("unknown report format %q", format)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		logrus.Error(err)
+		return model.SongFilter{}, "", false
+	}
+
+	filter, err := parseSongFilter(r.URL.Query())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		logrus.Error(err)
+		return model.SongFilter{}, "", false
+	}
+	return filter, format, true
+}
+
+// writeReport Отправка отчета в JSON или в CSV с заголовком header и строками records
+func writeReport(w http.ResponseWriter, format string, value any, header []string, records [][]string) {
+	if format != reportFormatCSV {
+		if err := json.NewEncoder(w).Encode(value); err != nil {
+			handleError(w, err)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	writer := csv.NewWriter(w)
+	if err := writer.Write(header); err != nil {
+		logrus.Error(err)
+		return
+	}
+	for _, record := range records {
+		for index, cell := range record {
+			record[index] = escapeCSVFormula(cell)
+		}
+	}
+	if err := writer.WriteAll(records); err != nil {
+		logrus.Error(err)
+	}
+}
+
+// escapeCSVFormula Апостроф перед значением, которое электронная таблица приняла бы за формулу,
+// например перед названием группы "=HYPERLINK(...)"
+func escapeCSVFormula(cell string) string {
+	if cell != "" && strings.ContainsRune("=+-@\t\r", rune(cell[0])) {
+		return "'" + cell
+	}
+	return cell
+}
+
+func formatAverage(value float64) string {
+	return strconv.FormatFloat(value, 'f', 2, 64)
+}
@@ -0,0 +1,72 @@
+package handler
+
+import (
+	"encoding/json"
+	"github.com/sirupsen/logrus"
+	"net/http"
+	"strconv"
+)
+
+type similarSongResponse struct {
+	songResponse
+	Similarity float64 `json:"similarity"`
+}
+
+// GetSimilarSongs godoc
+// @Summary      Get songs with similar lyrics
+// @Description  Returns songs whose lyrics are most similar to the song by cosine similarity of TF-IDF term vectors, most similar first. Terms are updated whenever a song is added or updated.
+// @Tags         songs
+// @Produce      json
+// @Param        id         path   int     true   "Song ID"
+// @Param        threshold  query  number  false  "Minimum similarity from 0 to 1, 0.1 by default"
+// @Param        limit      query  int     false  "Maximum number of songs, 10 by default and at most 100"
+// @Success      200  {array}   similarSongResponse  "Similar songs"
+// @Failure      400  {string}  string  "Invalid query parameters"
+// @Failure      404  {string}  string  "Song not found"
+// @Failure      500  {string}  string  "Internal server error"
+// @Router       /songs/{id}/similar [get]
+func (h *Handler) GetSimilarSongs(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		logrus.Error(err)
+		return
+	}
+
+	var threshold *float64
+	if rawThreshold := r.URL.Query().Get("threshold"); rawThreshold != "" {
+		value, err := strconv.ParseFloat(rawThreshold, 64)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			logrus.Error(err)
+			return
+		}
+		threshold = &value
+	}
+	_, limit, err := parsePagingData("", r.URL.Query().Get("limit"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		logrus.Error(err)
+		return
+	}
+
+	similar, err := h.service.Song.GetSimilarSongs(int64(id), threshold, limit)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	response := make([]similarSongResponse, 0, len(similar))
+	for _, song := range similar {
+		response = append(response, similarSongResponse{songResponse: newSongResponse(song.Song), Similarity: song.Similarity})
+	}
+	if err = json.NewEncoder(w).Encode(response); err != nil {
+		handleError(w, err)
+		return
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"id":    id,
+		"count": len(response),
+	}).Info("similar songs successfully sent")
+}
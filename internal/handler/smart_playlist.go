@@ -0,0 +1,340 @@
+package handler
+
+import (
+	"BestMusicLibrary/internal/model"
+	"encoding/json"
+	"fmt"
+	"github.com/sirupsen/logrus"
+	"net/http"
+	"strconv"
+)
+
+type smartRuleRequest struct {
+	Group        string `json:"group,omitempty"`
+	GenreId      int64  `json:"genre_id,omitempty"`
+	ReleasedFrom string `json:"released_from,omitempty" example:"1980-01-01"`
+	ReleasedTo   string `json:"released_to,omitempty" example:"1989-12-31"`
+	Language     string `json:"language,omitempty"`
+	Explicit     *bool  `json:"explicit,omitempty"`
+	Tag          string `json:"tag,omitempty"`
+}
+
+type smartPlaylistRequest struct {
+	Name        string           `json:"name"`
+	Description string           `json:"description,omitempty"`
+	Owner       string           `json:"owner"`
+	Public      bool             `json:"public"`
+	Rule        smartRuleRequest `json:"rule"`
+	Sort        string           `json:"sort,omitempty" enums:"added,title,group,release_date,random"`
+	Descending  bool             `json:"descending,omitempty"`
+	Limit       int              `json:"limit,omitempty"`
+}
+
+func (r smartPlaylistRequest) toSmartPlaylist(id int64) (model.SmartPlaylist, error) {
+	playlist := model.SmartPlaylist{
+		Id:          id,
+		Name:        r.Name,
+		Description: r.Description,
+		Owner:       r.Owner,
+		Public:      r.Public,
+		Rule: model.SmartRule{
+			Group:    r.Rule.Group,
+			GenreId:  r.Rule.GenreId,
+			Language: r.Rule.Language,
+			Explicit: r.Rule.Explicit,
+			Tag:      r.Rule.Tag,
+		},
+		Sort:       model.SongSort(r.Sort),
+		Descending: r.Descending,
+		Limit:      r.Limit,
+	}
+	var err error
+	if playlist.Rule.ReleasedFrom, err = parseOptionalDate(r.Rule.ReleasedFrom); err != nil {
+		return model.SmartPlaylist{}, err
+	}
+	if playlist.Rule.ReleasedTo, err = parseOptionalDate(r.Rule.ReleasedTo); err != nil {
+		return model.SmartPlaylist{}, err
+	}
+	return playlist, nil
+}
+
+type smartPlaylistSongsResponse struct {
+	Total int            `json:"total"`
+	Songs []songResponse `json:"songs"`
+}
+
+func newSmartPlaylistSongsResponse(songs []model.Song, total int) smartPlaylistSongsResponse {
+	response := smartPlaylistSongsResponse{Total: total, Songs: make([]songResponse, 0, len(songs))}
+	for _, song := range songs {
+		response.Songs = append(response.Songs, newSongResponse(song))
+	}
+	return response
+}
+
+// GetSmartPlaylists godoc
+// @Summary      Get list of smart playlists
+// @Description  Retrieves public smart playlists and private smart playlists of the caller with their rules, newest first. With owner returns only smart playlists of the owner.
+// @Tags         smart-playlists
+// @Produce      json
+// @Param        X-User  header  string  false  "Caller, private playlists are visible only to their owner"
+// @Param        owner   query   string  false  "Playlist owner"
+// @Param        page    query   int     false  "Page number for pagination"
+// @Param        limit   query   int     false  "Limit the number of playlists per page"
+// @Success      200  {array}   model.SmartPlaylist  "Smart playlists"
+// @Failure      400  {string}  string  "Invalid query parameters"
+// @Failure      500  {string}  string  "Internal server error"
+// @Router       /smart-playlists [get]
+func (h *Handler) GetSmartPlaylists(w http.ResponseWriter, r *http.Request) {
+	filter := model.PlaylistFilter{Owner: r.URL.Query().Get("owner"), Caller: r.Header.Get(callerHeader)}
+	page, limit, err := parsePagingData(r.URL.Query().Get("page"), r.URL.Query().Get("limit"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		logrus.Error(err)
+		return
+	}
+
+	playlists, err := h.service.SmartPlaylist.GetSmartPlaylists(filter, page, limit)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+	if err = json.NewEncoder(w).Encode(playlists); err != nil {
+		handleError(w, err)
+		return
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"owner": filter.Owner,
+		"count": len(playlists),
+	}).Info("smart playlists successfully sent")
+}
+
+// GetSmartPlaylist godoc
+// @Summary      Get a smart playlist
+// @Description  Retrieves a smart playlist with its rule, sort order and song limit. Private playlists of other users are not found.
+// @Tags         smart-playlists
+// @Produce      json
+// @Param        id      path    int     true   "Smart playlist ID"
+// @Param        X-User  header  string  false  "Caller, private playlists are visible only to their owner"
+// @Success      200  {object}  model.SmartPlaylist  "Smart playlist"
+// @Failure      400  {string}  string  "Invalid playlist ID"
+// @Failure      404  {string}  string  "Smart playlist not found"
+// @Failure      500  {string}  string  "Internal server error"
+// @Router       /smart-playlists/{id} [get]
+func (h *Handler) GetSmartPlaylist(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		logrus.Error(err)
+		return
+	}
+
+	playlist, err := h.service.SmartPlaylist.GetSmartPlaylist(int64(id), r.Header.Get(callerHeader))
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+	if err = json.NewEncoder(w).Encode(playlist); err != nil {
+		handleError(w, err)
+		return
+	}
+
+	logrus.WithField("id", id).Info("smart playlist successfully sent")
+}
+
+// GetSmartPlaylistSongs godoc
+// @Summary      Get smart playlist songs
+// @Description  Evaluates the rule of the smart playlist now with the same filters as the song list and returns at most limit songs in the playlist order together with the number of all matching songs. Private playlists of other users are not found.
+// @Tags         smart-playlists
+// @Produce      json
+// @Param        id      path    int     true   "Smart playlist ID"
+// @Param        X-User  header  string  false  "Caller, private playlists are visible only to their owner"
+// @Success      200  {object}  smartPlaylistSongsResponse  "Matching songs"
+// @Failure      400  {string}  string  "Invalid playlist ID"
+// @Failure      404  {string}  string  "Smart playlist not found"
+// @Failure      500  {string}  string  "Internal server error"
+// @Router       /smart-playlists/{id}/songs [get]
+func (h *Handler) GetSmartPlaylistSongs(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		logrus.Error(err)
+		return
+	}
+
+	songs, total, err := h.service.SmartPlaylist.GetSmartPlaylistSongs(int64(id), r.Header.Get(callerHeader))
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+	if err = json.NewEncoder(w).Encode(newSmartPlaylistSongsResponse(songs, total)); err != nil {
+		handleError(w, err)
+		return
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"id":    id,
+		"count": len(songs),
+		"total": total,
+	}).Info("smart playlist songs successfully sent")
+}
+
+// PreviewSmartPlaylist godoc
+// @Summary      Preview a smart playlist rule
+// @Description  Shows which songs a rule matches now without saving a playlist. Name and owner are not required.
+// @Tags         smart-playlists
+// @Accept       json
+// @Produce      json
+// @Param        playlist  body  smartPlaylistRequest  true  "Rule, sort order and song limit"
+// @Success      200  {object}  smartPlaylistSongsResponse  "Matching songs"
+// @Failure      400  {string}  string  "Invalid request body, rule, sort or limit"
+// @Failure      500  {string}  string  "Internal server error"
+// @Router       /smart-playlists/preview [post]
+func (h *Handler) PreviewSmartPlaylist(w http.ResponseWriter, r *http.Request) {
+	var request smartPlaylistRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		logrus.Error(err)
+		return
+	}
+	playlist, err := request.toSmartPlaylist(0)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		logrus.Error(err)
+		return
+	}
+
+	songs, total, err := h.service.SmartPlaylist.PreviewSmartPlaylist(playlist)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+	if err = json.NewEncoder(w).Encode(newSmartPlaylistSongsResponse(songs, total)); err != nil {
+		handleError(w, err)
+		return
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"count": len(songs),
+		"total": total,
+	}).Info("smart playlist preview successfully sent")
+}
+
+// AddSmartPlaylist godoc
+// @Summary      Add a smart playlist
+// @Description  Saves a smart playlist. Songs are matched by the rule every time the playlist is requested: group, genre with subgenres, release date range, language, explicit flag and tag, all conditions at once. Sort is added by default, limit is 100 by default and at most 500.
+// @Tags         smart-playlists
+// @Accept       json
+// @Produce      json
+// @Param        playlist  body  smartPlaylistRequest  true  "Smart playlist"
+// @Success      201  {string}  string  "Successfully added smart playlist with its ID"
+// @Failure      400  {string}  string  "Invalid request body, rule, sort or limit"
+// @Failure      500  {string}  string  "Internal server error"
+// @Router       /smart-playlists [post]
+func (h *Handler) AddSmartPlaylist(w http.ResponseWriter, r *http.Request) {
+	var request smartPlaylistRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		logrus.Error(err)
+		return
+	}
+	playlist, err := request.toSmartPlaylist(0)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		logrus.Error(err)
+		return
+	}
+
+	playlistId, err := h.service.SmartPlaylist.AddSmartPlaylist(playlist)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	if _, err = fmt.Fprintf(w, "%d", playlistId); err != nil {
+		handleError(w, err)
+		return
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"id":    playlistId,
+		"name":  request.Name,
+		"owner": request.Owner,
+	}).Info("smart playlist successfully added")
+}
+
+// UpdateSmartPlaylist godoc
+// @Summary      Update a smart playlist
+// @Description  Replaces the smart playlist details, rule, sort order and song limit. Only the owner may change a smart playlist, for other callers it is not found.
+// @Tags         smart-playlists
+// @Accept       json
+// @Produce      json
+// @Param        id        path    int                   true  "Smart playlist ID"
+// @Param        playlist  body    smartPlaylistRequest  true  "Smart playlist"
+// @Param        X-User    header  string                true  "Caller, only the owner may change the playlist"
+// @Success      200  {string}  string  "Smart playlist successfully updated"
+// @Failure      400  {string}  string  "Invalid playlist ID, request body, rule, sort or limit"
+// @Failure      404  {string}  string  "Smart playlist of the caller not found"
+// @Failure      500  {string}  string  "Internal server error"
+// @Router       /smart-playlists/{id} [put]
+func (h *Handler) UpdateSmartPlaylist(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		logrus.Error(err)
+		return
+	}
+
+	var request smartPlaylistRequest
+	if err = json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		logrus.Error(err)
+		return
+	}
+	playlist, err := request.toSmartPlaylist(int64(id))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		logrus.Error(err)
+		return
+	}
+
+	if err = h.service.SmartPlaylist.UpdateSmartPlaylist(playlist, r.Header.Get(callerHeader)); err != nil {
+		handleError(w, err)
+		return
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"id":   id,
+		"name": request.Name,
+	}).Info("smart playlist successfully updated")
+	w.WriteHeader(http.StatusOK)
+}
+
+// DeleteSmartPlaylist godoc
+// @Summary      Delete a smart playlist
+// @Description  Deletes a smart playlist. The songs stay in the library. Only the owner may delete a smart playlist, for other callers it is not found.
+// @Tags         smart-playlists
+// @Param        id      path    int     true  "Smart playlist ID"
+// @Param        X-User  header  string  true  "Caller, only the owner may delete the playlist"
+// @Success      200  {string}  string  "Smart playlist successfully deleted"
+// @Failure      400  {string}  string  "Invalid playlist ID"
+// @Failure      404  {string}  string  "Smart playlist of the caller not found"
+// @Failure      500  {string}  string  "Internal server error"
+// @Router       /smart-playlists/{id} [delete]
+func (h *Handler) DeleteSmartPlaylist(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		logrus.Error(err)
+		return
+	}
+
+	if err = h.service.SmartPlaylist.DeleteSmartPlaylist(int64(id), r.Header.Get(callerHeader)); err != nil {
+		handleError(w, err)
+		return
+	}
+
+	logrus.WithField("id", id).Info("smart playlist successfully deleted")
+	w.WriteHeader(http.StatusOK)
+}
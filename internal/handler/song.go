@@ -2,55 +2,97 @@ package handler
 
 import (
 	"BestMusicLibrary/internal/model"
+	"BestMusicLibrary/internal/service"
 	"encoding/json"
-	"errors"
 	"fmt"
 	"github.com/sirupsen/logrus"
+	"golang.org/x/text/language"
 	"net/http"
+	"net/url"
 	"strconv"
 	"time"
 )
 
 type songResponse struct {
-	Id          int64     `json:"id"`
-	Group       string    `json:"group"`
-	Name        string    `json:"name"`
-	ReleaseDate time.Time `json:"release_date"`
-	Link        string    `json:"link"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+	Id                   int64      `json:"id"`
+	ArtistId             int64      `json:"artist_id"`
+	Group                string     `json:"group"`
+	Name                 string     `json:"name"`
+	ReleaseDate          *time.Time `json:"release_date"`
+	ReleaseDatePrecision string     `json:"release_date_precision,omitempty"`
+	Language             string     `json:"language,omitempty"`
+	Explicit             *bool      `json:"explicit"`
+	Link                 string     `json:"link"`
+	CreatedAt            time.Time  `json:"created_at"`
+	UpdatedAt            time.Time  `json:"updated_at"`
+}
+
+func newSongResponse(song model.Song) songResponse {
+	response := songResponse{
+		Id:        song.Id,
+		ArtistId:  song.ArtistId,
+		Group:     song.Group,
+		Name:      song.Name,
+		Language:  song.Language,
+		Explicit:  song.IsExplicit(),
+		Link:      song.Link,
+		CreatedAt: song.CreatedAt,
+		UpdatedAt: song.UpdatedAt,
+	}
+	if !song.ReleaseDate.IsZero() {
+		releaseDate := song.ReleaseDate
+		response.ReleaseDate = &releaseDate
+		response.ReleaseDatePrecision = string(song.ReleaseDatePrecision)
+	}
+	return response
 }
 
 // GetSongs godoc
 // @Summary      Get list of songs
-// @Description  Retrieves a list of songs from the database. You can filter the results by group name and song name, and paginate the results using the page and limit query parameters.
+// @Description  Retrieves a list of songs from the database. You can filter the results by the name or alias of any credited artist and song name in either Cyrillic or Latin script, and paginate the results using the page and limit query parameters. Without any filter the list is empty.
 // @Tags         songs
 // @Accept       json
 // @Produce      json
 // @Param        group   query   string  false  "Filter by group name"
 // @Param        song    query   string  false  "Filter by song name"
+// @Param        language  query  string  false  "Filter by BCP 47 language tag, a tag without region also matches regional variants"
+// @Param        explicit  query  bool    false  "Only explicit songs when true, only songs without explicit content when false"
+// @Param        album_id  query  int     false  "Only songs on the album tracklist"
+// @Param        artist_id  query  int    false  "Only songs crediting the artist in any role"
+// @Param        genre_id  query  int     false  "Only songs of the genre or any of its subgenres"
+// @Param        tag       query  string  false  "Only songs with the tag"
+// @Param        released_from  query  string  false  "Only songs released on or after the date, YYYY-MM-DD"
+// @Param        released_to    query  string  false  "Only songs released on or before the date, YYYY-MM-DD"
 // @Param        page    query   int     false  "Page number for pagination"
 // @Param        limit   query   int     false  "Limit the number of songs per page"
 // @Success      200     {array} songResponse  "Successful response"
-// @Failure      400     {string} string "Invalid query parameters or request method"
+// @Failure      400     {string} string "Invalid query parameters"
 // @Failure      500     {string} string "Internal server error"
 // @Router       /songs/get [get]
 func (h *Handler) GetSongs(w http.ResponseWriter, r *http.Request) {
-	if err := handleRequestMethod(w, http.MethodGet, r.Method); err != nil {
+	page := r.URL.Query().Get("page")
+	limit := r.URL.Query().Get("limit")
+
+	filter, err := parseSongFilter(r.URL.Query())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
 		logrus.Error(err)
 		return
 	}
 
-	group := r.URL.Query().Get("group")
-	song := r.URL.Query().Get("song")
-	page := r.URL.Query().Get("page")
-	limit := r.URL.Query().Get("limit")
-
 	logrus.WithFields(logrus.Fields{
-		"group": group,
-		"song":  song,
-		"page":  page,
-		"limit": limit,
+		"group":         filter.Group,
+		"song":          filter.Name,
+		"language":      filter.Language,
+		"explicit":      r.URL.Query().Get("explicit"),
+		"album_id":      filter.AlbumId,
+		"artist_id":     filter.ArtistId,
+		"genre_id":      filter.GenreId,
+		"tag":           filter.Tag,
+		"released_from": r.URL.Query().Get("released_from"),
+		"released_to":   r.URL.Query().Get("released_to"),
+		"page":          page,
+		"limit":         limit,
 	}).Debug("received query parameters")
 
 	pageNum, limitNum, err := parsePagingData(page, limit)
@@ -69,12 +111,12 @@ func (h *Handler) GetSongs(w http.ResponseWriter, r *http.Request) {
 		"limit": limitNum,
 	}).Info("parsed paging data")
 
-	songs, err := h.service.Song.GetSongs(group, song, pageNum, limitNum)
+	songs, err := h.service.Song.GetSongs(filter, pageNum, limitNum)
 	if err != nil {
 		handleError(w, err)
 		logrus.WithFields(logrus.Fields{
-			"group": group,
-			"song":  song,
+			"group": filter.Group,
+			"song":  filter.Name,
 		}).Error("error fetching songs")
 		return
 	}
@@ -85,15 +127,7 @@ func (h *Handler) GetSongs(w http.ResponseWriter, r *http.Request) {
 
 	songResponses := make([]songResponse, 0, len(songs))
 	for _, s := range songs {
-		songResponses = append(songResponses, songResponse{
-			Id:          s.Id,
-			Group:       s.Group,
-			Name:        s.Name,
-			ReleaseDate: s.ReleaseDate,
-			Link:        s.Link,
-			CreatedAt:   s.CreatedAt,
-			UpdatedAt:   s.UpdatedAt,
-		})
+		songResponses = append(songResponses, newSongResponse(s))
 	}
 
 	err = json.NewEncoder(w).Encode(songResponses)
@@ -108,26 +142,28 @@ func (h *Handler) GetSongs(w http.ResponseWriter, r *http.Request) {
 }
 
 type newSongRequest struct {
-	Group string `json:"group"`
-	Song  string `json:"song"`
+	ArtistId    int64   `json:"artist_id,omitempty"`
+	Group       string  `json:"group"`
+	Song        string  `json:"song"`
+	Language    string  `json:"language,omitempty"`
+	ReleaseDate *string `json:"release_date,omitempty"`
+	Link        *string `json:"link,omitempty"`
+	Text        *string `json:"text,omitempty"`
 }
 
 // AddSong godoc
 // @Summary Add a new song
-// @Description Adds a new song to the database based on the provided song details.
+// @Description Adds a new song to the database based on the provided song details. The song belongs to the artist given by artist_id or found by the group name among artist names and aliases, an unknown group becomes a new artist. Featured artists after "feat." or "ft." in group and song names and several primary artists joined by "&" become song credits when every one of them is a known artist. Commas never split names. Group and song names are compared after Unicode, quote and case normalization, so a song that differs only in those is rejected as a duplicate. Release date, link and text supplied by the client are merged with the enrichment providers according to the configured precedence.
 // @Tags         songs
 // @Accept       json
 // @Produce      json
 // @Param        song  body  newSongRequest  true  "New song details"
-// @Success      201  {string}  string  "Successfully added song with its ID"
-// @Failure      400  {string}  string "Invalid request method"
+// @Success      201  {string}  string  "Successfully added song with its ID, enrichment warnings are sent in Warning headers"
+// @Failure      400  {string}  string "Invalid song details"
+// @Failure      409  {string}  string  "Song with the same group and name already exists"
 // @Failure      500  {string}  string  "Internal server error"
 // @Router       /songs/add [post]
 func (h *Handler) AddSong(w http.ResponseWriter, r *http.Request) {
-	if err := handleRequestMethod(w, http.MethodPost, r.Method); err != nil {
-		logrus.Error(err)
-		return
-	}
 	var songRequest newSongRequest
 	err := json.NewDecoder(r.Body).Decode(&songRequest)
 	if err != nil {
@@ -140,7 +176,11 @@ func (h *Handler) AddSong(w http.ResponseWriter, r *http.Request) {
 		"group": songRequest.Group,
 	}).Info("decoded request body")
 
-	songId, err := h.service.Song.AddSong(model.Song{Name: songRequest.Song, Group: songRequest.Group})
+	songId, warnings, err := h.service.Song.AddSong(model.Song{ArtistId: songRequest.ArtistId, Name: songRequest.Song, Group: songRequest.Group, Language: songRequest.Language}, service.ClientSongData{
+		ReleaseDate: songRequest.ReleaseDate,
+		Link:        songRequest.Link,
+		Text:        songRequest.Text,
+	})
 	if err != nil {
 		handleError(w, err)
 		return
@@ -148,6 +188,11 @@ func (h *Handler) AddSong(w http.ResponseWriter, r *http.Request) {
 
 	logrus.WithField("songId", songId).Info("song successfully added")
 
+	for _, warning := range warnings {
+		w.Header().Add("Warning", fmt.Sprintf("199 - %q", warning))
+		logrus.WithField("songId", songId).Warn(warning)
+	}
+
 	w.WriteHeader(http.StatusCreated)
 	_, err = fmt.Fprint(w, fmt.Sprintf("%d", songId))
 	if err != nil {
@@ -159,20 +204,16 @@ func (h *Handler) AddSong(w http.ResponseWriter, r *http.Request) {
 
 // DeleteSong godoc
 // @Summary      Delete a song
-// @Description  Deletes a song from the database using its ID.
+// @Description  Deletes a song from the database using its ID. The song is also removed from albums and playlists.
 // @Tags         songs
 // @Accept       json
 // @Produce      json
 // @Param        id  query  int  true  "Song ID"
 // @Success      200  {string}  string  "Successfully deleted song"
-// @Failure      400  {string}  string "Invalid query parameters or request method"
+// @Failure      400  {string}  string "Invalid query parameters"
 // @Failure      500  {string}  string  "Internal server error"
 // @Router       /songs/delete [delete]
 func (h *Handler) DeleteSong(w http.ResponseWriter, r *http.Request) {
-	if err := handleRequestMethod(w, http.MethodDelete, r.Method); err != nil {
-		logrus.Error(err)
-		return
-	}
 	id, err := strconv.Atoi(r.URL.Query().Get("id"))
 	logrus.WithFields(logrus.Fields{
 		"id": id,
@@ -195,33 +236,32 @@ func (h *Handler) DeleteSong(w http.ResponseWriter, r *http.Request) {
 }
 
 type songUpdate struct {
-	Id          int64     `json:"id"`
-	Group       string    `json:"group"`
-	Name        string    `json:"name"`
-	ReleaseDate time.Time `json:"release_date"`
-	Text        string    `json:"text"`
-	Link        string    `json:"link"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+	Id                   int64     `json:"id"`
+	ArtistId             int64     `json:"artist_id,omitempty"`
+	Group                string    `json:"group"`
+	Name                 string    `json:"name"`
+	ReleaseDate          time.Time `json:"release_date"`
+	ReleaseDatePrecision string    `json:"release_date_precision"`
+	Language             string    `json:"language"`
+	Text                 string    `json:"text"`
+	Link                 string    `json:"link"`
+	CreatedAt            time.Time `json:"created_at"`
+	UpdatedAt            time.Time `json:"updated_at"`
 }
 
 // UpdateSong godoc
 // @Summary      Update a song
-// @Description  Updates the details of a song in the database using the provided data.
+// @Description  Updates the details of a song in the database using the provided data. The artist and credits are resolved from artist_id, the group name and the song name as when adding a song. Primary and featured credits are replaced by the parsed ones, credits with other roles are kept.
 // @Tags         songs
 // @Accept       json
 // @Produce      json
 // @Param        song  body  songUpdate  true  "Song update details"
 // @Success      200  {string}  string "Song successfully updated"
 // @Failure      400  {string}  string "Invalid request body"
+// @Failure      409  {string}  string "Another song with the same group and name already exists"
 // @Failure      500  {string}  string "Internal server error"
 // @Router       /songs/update [put]
 func (h *Handler) UpdateSong(w http.ResponseWriter, r *http.Request) {
-
-	if err := handleRequestMethod(w, http.MethodPut, r.Method); err != nil {
-		logrus.Error(err)
-		return
-	}
 	var song songUpdate
 	err := json.NewDecoder(r.Body).Decode(&song)
 	if err != nil {
@@ -239,13 +279,16 @@ func (h *Handler) UpdateSong(w http.ResponseWriter, r *http.Request) {
 	}).Info("decoded request body for song update")
 
 	err = h.service.Song.UpdateSong(model.Song{
-		Id:          song.Id,
-		Group:       song.Group,
-		Name:        song.Name,
-		ReleaseDate: song.ReleaseDate,
-		Link:        song.Link,
-		CreatedAt:   song.CreatedAt,
-		UpdatedAt:   song.UpdatedAt,
+		Id:                   song.Id,
+		ArtistId:             song.ArtistId,
+		Group:                song.Group,
+		Name:                 song.Name,
+		ReleaseDate:          song.ReleaseDate,
+		ReleaseDatePrecision: model.DatePrecision(song.ReleaseDatePrecision),
+		Language:             song.Language,
+		Link:                 song.Link,
+		CreatedAt:            song.CreatedAt,
+		UpdatedAt:            song.UpdatedAt,
 	}, song.Text)
 
 	if err != nil {
@@ -259,30 +302,56 @@ func (h *Handler) UpdateSong(w http.ResponseWriter, r *http.Request) {
 
 // GetSongVerses godoc
 // @Summary      Get song verses
-// @Description  Retrieves verses of a song based on the song ID with optional pagination.
+// @Description  Retrieves verses of a song based on the song ID with optional pagination. Verses that followed a section header such as [Chorus] carry its section type and label.
+// @Description  By default repeated verses are expanded in performance order and verse_number is the position. In compact mode every distinct verse is returned once with the positions it is performed at.
+// @Description  The lyric version is selected by the lang parameter or the Accept-Language header among the original and its translations, the chosen language is returned in Content-Language.
 // @Tags         songs
 // @Accept       json
 // @Produce      json
 // @Param        id     query  int     true   "Song ID"
 // @Param        page   query  int     false  "Page number"
 // @Param        limit  query  int     false  "Number of verses per page"
-// @Success      200    {object}  model.Verse  "List of song verses"
+// @Param        compact  query  bool  false  "Return distinct verses with their positions"
+// @Param        lang     query  string  false  "BCP 47 language tag of the lyric version"
+// @Param        Accept-Language  header  string  false  "Preferred lyric languages"
+// @Success      200    {array}   model.Verse  "List of song verses"
 // @Failure      400    {object}  string  "Invalid query parameters"
 // @Failure      500    {object}  string  "Internal server error"
 // @Router       /songs/verses [get]
 func (h *Handler) GetSongVerses(w http.ResponseWriter, r *http.Request) {
-	if err := handleRequestMethod(w, http.MethodGet, r.Method); err != nil {
-		logrus.Error(err)
-		return
-	}
 	id, err := strconv.Atoi(r.URL.Query().Get("id"))
 	page := r.URL.Query().Get("page")
 	limit := r.URL.Query().Get("limit")
 
+	compact := false
+	if rawCompact := r.URL.Query().Get("compact"); rawCompact != "" {
+		compact, err = strconv.ParseBool(rawCompact)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			logrus.Error(err)
+			return
+		}
+	}
+
+	var preferred []language.Tag
+	if rawLanguage := r.URL.Query().Get("lang"); rawLanguage != "" {
+		tag, err := language.Parse(rawLanguage)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			logrus.Error(err)
+			return
+		}
+		preferred = []language.Tag{tag}
+	} else if acceptLanguage := r.Header.Get("Accept-Language"); acceptLanguage != "" {
+		preferred, _, _ = language.ParseAcceptLanguage(acceptLanguage)
+	}
+
 	logrus.WithFields(logrus.Fields{
-		"id":    id,
-		"page":  page,
-		"limit": limit,
+		"id":        id,
+		"page":      page,
+		"limit":     limit,
+		"compact":   compact,
+		"languages": preferred,
 	}).Debug("parsed query parameters")
 
 	pageNum, limitNum, err := parsePagingData(page, limit)
@@ -292,12 +361,17 @@ func (h *Handler) GetSongVerses(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	verses, err := h.service.Song.GetSongVerses(int64(id), pageNum, limitNum)
+	verses, lang, err := h.service.Song.GetSongVerses(int64(id), pageNum, limitNum, compact, preferred)
 	if err != nil {
 		handleError(w, err)
 		return
 	}
 
+	w.Header().Set("Vary", "Accept-Language")
+	if lang != "" {
+		w.Header().Set("Content-Language", lang)
+	}
+
 	logrus.WithFields(logrus.Fields{
 		"id":    id,
 		"page":  pageNum,
@@ -316,13 +390,249 @@ func (h *Handler) GetSongVerses(w http.ResponseWriter, r *http.Request) {
 	}).Info("response successfully sent")
 }
 
-func handleRequestMethod(w http.ResponseWriter, requiredMethod, currentMethod string) error {
-	if currentMethod != requiredMethod {
-		errText := fmt.Sprintf("method %s required!", requiredMethod)
-		http.Error(w, errText, http.StatusBadRequest)
-		return errors.New(errText)
+// GetVerseLines godoc
+// @Summary      Get verse lines
+// @Description  Retrieves lines of a song verse with optional pagination. The verse is addressed by its position in the expanded lyrics, joining all its lines with "\n" gives the verse text exactly.
+// @Tags         songs
+// @Accept       json
+// @Produce      json
+// @Param        id     query  int     true   "Song ID"
+// @Param        verse  query  int     true   "Verse position"
+// @Param        page   query  int     false  "Page number"
+// @Param        limit  query  int     false  "Number of lines per page"
+// @Success      200    {array}   model.VerseLine  "List of verse lines"
+// @Failure      400    {object}  string  "Invalid query parameters"
+// @Failure      500    {object}  string  "Internal server error"
+// @Router       /songs/verses/lines [get]
+func (h *Handler) GetVerseLines(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.URL.Query().Get("id"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		logrus.Error(err)
+		return
+	}
+	verseNumber, err := strconv.Atoi(r.URL.Query().Get("verse"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		logrus.Error(err)
+		return
+	}
+	page := r.URL.Query().Get("page")
+	limit := r.URL.Query().Get("limit")
+
+	logrus.WithFields(logrus.Fields{
+		"id":    id,
+		"verse": verseNumber,
+		"page":  page,
+		"limit": limit,
+	}).Debug("parsed query parameters")
+
+	pageNum, limitNum, err := parsePagingData(page, limit)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		logrus.Error(err)
+		return
+	}
+
+	lines, err := h.service.Song.GetVerseLines(int64(id), verseNumber, pageNum, limitNum)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	err = json.NewEncoder(w).Encode(lines)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"id":    id,
+		"verse": verseNumber,
+		"lines": len(lines),
+	}).Info("response successfully sent")
+}
+
+// GetSongSources godoc
+// @Summary      Get song sources
+// @Description  Retrieves the history of enrichment providers that supplied the fields of a song.
+// @Tags         songs
+// @Accept       json
+// @Produce      json
+// @Param        id     query  int     true   "Song ID"
+// @Success      200    {array}   model.SongSource  "List of song field sources"
+// @Failure      400    {object}  string  "Invalid query parameters"
+// @Failure      500    {object}  string  "Internal server error"
+// @Router       /songs/sources [get]
+func (h *Handler) GetSongSources(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.URL.Query().Get("id"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		logrus.Error(err)
+		return
+	}
+
+	sources, err := h.service.Song.GetSongSources(int64(id))
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	err = json.NewEncoder(w).Encode(sources)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"id":      id,
+		"sources": len(sources),
+	}).Info("response successfully sent")
+}
+
+type fieldChangeResponse struct {
+	Field    string `json:"field"`
+	Old      string `json:"old"`
+	New      string `json:"new"`
+	Provider string `json:"provider"`
+}
+
+type enrichResponse struct {
+	SongId   int64                 `json:"song_id"`
+	DryRun   bool                  `json:"dry_run"`
+	Applied  bool                  `json:"applied"`
+	Changes  []fieldChangeResponse `json:"changes"`
+	Warnings []string              `json:"warnings"`
+}
+
+// EnrichSong godoc
+// @Summary      Re-enrich a song
+// @Description  Fetches release date, link and lyrics of a stored song from the enrichment providers again. Stored values are merged with the provider values according to the configured precedence, the same way as client values when a song is added. In dry-run mode only the field-by-field diff is returned and nothing is changed.
+// @Tags         songs
+// @Accept       json
+// @Produce      json
+// @Param        id       path   int   true   "Song ID"
+// @Param        dry_run  query  bool  false  "Only show the diff without applying it"
+// @Success      200  {object}  enrichResponse  "Field-by-field diff"
+// @Failure      400  {string}  string  "Invalid parameters"
+// @Failure      404  {string}  string  "Song not found"
+// @Failure      500  {string}  string  "Internal server error"
+// @Router       /songs/{id}/enrich [post]
+func (h *Handler) EnrichSong(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		logrus.Error(err)
+		return
+	}
+
+	dryRun := false
+	if rawDryRun := r.URL.Query().Get("dry_run"); rawDryRun != "" {
+		dryRun, err = strconv.ParseBool(rawDryRun)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			logrus.Error(err)
+			return
+		}
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"id":      id,
+		"dry_run": dryRun,
+	}).Debug("received enrichment request")
+
+	diff, err := h.service.Song.EnrichSong(int64(id), dryRun)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	response := enrichResponse{
+		SongId:   diff.SongId,
+		DryRun:   dryRun,
+		Applied:  diff.Applied,
+		Changes:  make([]fieldChangeResponse, 0, len(diff.Changes)),
+		Warnings: diff.Warnings,
+	}
+	for _, change := range diff.Changes {
+		response.Changes = append(response.Changes, fieldChangeResponse{
+			Field:    string(change.Field),
+			Old:      change.Old,
+			New:      change.New,
+			Provider: change.Provider,
+		})
+	}
+
+	err = json.NewEncoder(w).Encode(response)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"id":      id,
+		"changes": len(response.Changes),
+		"applied": response.Applied,
+	}).Info("response successfully sent")
+}
+
+// parseSongFilter Фильтр списка песен из параметров group, song, language, explicit, album_id, artist_id, genre_id, tag,
+// released_from и released_to
+func parseSongFilter(query url.Values) (model.SongFilter, error) {
+	filter := model.SongFilter{
+		Group:    query.Get("group"),
+		Name:     query.Get("song"),
+		Language: query.Get("language"),
+	}
+	if rawExplicit := query.Get("explicit"); rawExplicit != "" {
+		explicit, err := strconv.ParseBool(rawExplicit)
+		if err != nil {
+			return model.SongFilter{}, err
+		}
+		filter.Explicit = &explicit
+	}
+	if rawAlbumId := query.Get("album_id"); rawAlbumId != "" {
+		albumId, err := strconv.ParseInt(rawAlbumId, 10, 64)
+		if err != nil {
+			return model.SongFilter{}, err
+		}
+		filter.AlbumId = albumId
+	}
+	if rawArtistId := query.Get("artist_id"); rawArtistId != "" {
+		artistId, err := strconv.ParseInt(rawArtistId, 10, 64)
+		if err != nil {
+			return model.SongFilter{}, err
+		}
+		filter.ArtistId = artistId
+	}
+	if rawGenreId := query.Get("genre_id"); rawGenreId != "" {
+		genreId, err := strconv.ParseInt(rawGenreId, 10, 64)
+		if err != nil {
+			return model.SongFilter{}, err
+		}
+		filter.GenreId = genreId
+	}
+	filter.Tag = query.Get("tag")
+	var err error
+	if filter.ReleasedFrom, err = parseOptionalDate(query.Get("released_from")); err != nil {
+		return model.SongFilter{}, err
+	}
+	if filter.ReleasedTo, err = parseOptionalDate(query.Get("released_to")); err != nil {
+		return model.SongFilter{}, err
+	}
+	return filter, nil
+}
+
+// parseOptionalDate Дата в формате YYYY-MM-DD, пустая строка дает nil
+func parseOptionalDate(raw string) (*time.Time, error) {
+	if raw == "" {
+		return nil, nil
+	}
+	date, err := time.Parse(time.DateOnly, raw)
+	if err != nil {
+		return nil, err
 	}
-	return nil
+	return &date, nil
 }
 
 func parsePagingData(page, limit string) (pageNum, limitNum int, err error) {
@@ -0,0 +1,41 @@
+package handler
+
+import (
+	"encoding/json"
+	"github.com/sirupsen/logrus"
+	"net/http"
+	"strconv"
+)
+
+// GetSongStats godoc
+// @Summary      Get lyrics statistics
+// @Description  Returns verse, line and word counts, the unique word ratio, the most frequent words without stop words, an estimated reading time and a guessed rhyme scheme of every verse. Verses are counted in performance order. Statistics are cached until the song is updated.
+// @Tags         songs
+// @Produce      json
+// @Param        id   path  int  true  "Song ID"
+// @Success      200  {object}  model.SongStats  "Lyrics statistics"
+// @Failure      400  {string}  string  "Invalid song ID"
+// @Failure      404  {string}  string  "Song not found"
+// @Failure      500  {string}  string  "Internal server error"
+// @Router       /songs/{id}/stats [get]
+func (h *Handler) GetSongStats(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		logrus.Error(err)
+		return
+	}
+
+	stats, err := h.service.Song.GetSongStats(int64(id))
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	if err = json.NewEncoder(w).Encode(stats); err != nil {
+		handleError(w, err)
+		return
+	}
+
+	logrus.WithField("id", id).Info("song stats successfully sent")
+}
@@ -0,0 +1,187 @@
+package handler
+
+import (
+	"encoding/json"
+	"github.com/sirupsen/logrus"
+	"net/http"
+	"strconv"
+)
+
+type songTagRequest struct {
+	Tag string `json:"tag"`
+}
+
+// GetSongTags godoc
+// @Summary      Get song tags
+// @Description  Returns the free-form tags of the song alphabetically.
+// @Tags         tags
+// @Produce      json
+// @Param        id  path  int  true  "Song ID"
+// @Success      200  {array}   string  "Song tags"
+// @Failure      400  {string}  string  "Invalid song ID"
+// @Failure      404  {string}  string  "Song not found"
+// @Failure      500  {string}  string  "Internal server error"
+// @Router       /songs/{id}/tags [get]
+func (h *Handler) GetSongTags(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		logrus.Error(err)
+		return
+	}
+
+	tags, err := h.service.Tag.GetSongTags(int64(id))
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+	if err = json.NewEncoder(w).Encode(tags); err != nil {
+		handleError(w, err)
+		return
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"id":    id,
+		"count": len(tags),
+	}).Info("song tags successfully sent")
+}
+
+// AddSongTag godoc
+// @Summary      Tag a song
+// @Description  Adds a free-form tag to the song. Tags are stored in lower case, tags that differ only in case, Unicode form, quotes or script are the same tag. A new tag is created on first use.
+// @Tags         tags
+// @Accept       json
+// @Produce      json
+// @Param        id   path  int             true  "Song ID"
+// @Param        tag  body  songTagRequest  true  "Tag"
+// @Success      200  {string}  string  "Tag successfully added"
+// @Failure      400  {string}  string  "Invalid song ID, empty or too long tag"
+// @Failure      404  {string}  string  "Song not found"
+// @Failure      500  {string}  string  "Internal server error"
+// @Router       /songs/{id}/tags [post]
+func (h *Handler) AddSongTag(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		logrus.Error(err)
+		return
+	}
+
+	var request songTagRequest
+	if err = json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		logrus.Error(err)
+		return
+	}
+
+	if err = h.service.Tag.AddSongTag(int64(id), request.Tag); err != nil {
+		handleError(w, err)
+		return
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"id":  id,
+		"tag": request.Tag,
+	}).Info("song tag successfully added")
+	w.WriteHeader(http.StatusOK)
+}
+
+// DeleteSongTag godoc
+// @Summary      Untag a song
+// @Description  Removes a tag from the song. A tag that no song has any more disappears from autocomplete and counts.
+// @Tags         tags
+// @Param        id   path  int     true  "Song ID"
+// @Param        tag  path  string  true  "Tag"
+// @Success      200  {string}  string  "Tag successfully removed"
+// @Failure      400  {string}  string  "Invalid song ID"
+// @Failure      404  {string}  string  "Song does not have the tag"
+// @Failure      500  {string}  string  "Internal server error"
+// @Router       /songs/{id}/tags/{tag} [delete]
+func (h *Handler) DeleteSongTag(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		logrus.Error(err)
+		return
+	}
+	tag := r.PathValue("tag")
+
+	if err = h.service.Tag.DeleteSongTag(int64(id), tag); err != nil {
+		handleError(w, err)
+		return
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"id":  id,
+		"tag": tag,
+	}).Info("song tag successfully removed")
+	w.WriteHeader(http.StatusOK)
+}
+
+// SuggestTags godoc
+// @Summary      Autocomplete tags
+// @Description  Returns tags in use that start with the prefix in either Cyrillic or Latin script, most used first.
+// @Tags         tags
+// @Produce      json
+// @Param        prefix  query  string  false  "Tag prefix"
+// @Param        limit   query  int     false  "Maximum number of tags, 10 by default and at most 100"
+// @Success      200  {array}   string  "Matching tags"
+// @Failure      400  {string}  string  "Invalid query parameters"
+// @Failure      500  {string}  string  "Internal server error"
+// @Router       /tags/autocomplete [get]
+func (h *Handler) SuggestTags(w http.ResponseWriter, r *http.Request) {
+	prefix := r.URL.Query().Get("prefix")
+	_, limit, err := parsePagingData("", r.URL.Query().Get("limit"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		logrus.Error(err)
+		return
+	}
+
+	tags, err := h.service.Tag.SuggestTags(prefix, limit)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+	if err = json.NewEncoder(w).Encode(tags); err != nil {
+		handleError(w, err)
+		return
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"prefix": prefix,
+		"count":  len(tags),
+	}).Info("tag suggestions successfully sent")
+}
+
+// GetTagCounts godoc
+// @Summary      Get tag counts
+// @Description  Returns every tag in use with the number of songs that have it, most used first.
+// @Tags         tags
+// @Produce      json
+// @Param        page   query  int  false  "Page number for pagination"
+// @Param        limit  query  int  false  "Limit the number of tags per page"
+// @Success      200  {array}   model.TagCount  "Tag counts"
+// @Failure      400  {string}  string  "Invalid query parameters"
+// @Failure      500  {string}  string  "Internal server error"
+// @Router       /tags [get]
+func (h *Handler) GetTagCounts(w http.ResponseWriter, r *http.Request) {
+	page, limit, err := parsePagingData(r.URL.Query().Get("page"), r.URL.Query().Get("limit"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		logrus.Error(err)
+		return
+	}
+
+	counts, err := h.service.Tag.GetTagCounts(page, limit)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+	if err = json.NewEncoder(w).Encode(counts); err != nil {
+		handleError(w, err)
+		return
+	}
+
+	logrus.WithField("count", len(counts)).Info("tag counts successfully sent")
+}
@@ -0,0 +1,170 @@
+//go:build integration
+
+package integration
+
+import (
+	"BestMusicLibrary/internal/client"
+	"BestMusicLibrary/internal/handler"
+	"BestMusicLibrary/internal/mockinfo"
+	"BestMusicLibrary/internal/profanity"
+	"BestMusicLibrary/internal/repository"
+	"BestMusicLibrary/internal/service"
+	"BestMusicLibrary/migrations"
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strconv"
+	"testing"
+)
+
+func getEnv(key, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
+
+func newTestDb(t *testing.T) *sqlx.DB {
+	db, err := repository.NewPostgresDb(repository.Config{
+		Host:     getEnv("DB_HOST", "localhost"),
+		Port:     getEnv("DB_PORT", "5432"),
+		UserName: getEnv("DB_USER", "root"),
+		Password: getEnv("DB_PASSWORD", "root"),
+		DbName:   getEnv("DB_NAME", "song_library"),
+		SSLMode:  getEnv("DB_SSL_MODE", "disable"),
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = db.Close()
+	})
+
+	require.NoError(t, migrations.NewDbMigrator(db, "../../migrations").Migrate())
+	return db
+}
+
+func newTestHandler(t *testing.T, db *sqlx.DB, options mockinfo.Options) *handler.Handler {
+	fixtures, err := mockinfo.LoadFixtures("../../fixtures/mockinfo")
+	require.NoError(t, err)
+
+	upstream := httptest.NewServer(mockinfo.NewServer(fixtures, options))
+	t.Cleanup(upstream.Close)
+
+	apiClient, err := client.NewExternalSongApiClient(client.ExternalSongApiClientConfig{BaseUrl: upstream.URL})
+	require.NoError(t, err)
+	chain, err := service.NewProviderChain([]service.SongDataProvider{{Name: "mockinfo", Fetcher: apiClient}}, nil)
+	require.NoError(t, err)
+	precedence, err := service.ParsePrecedence("", "")
+	require.NoError(t, err)
+
+	return handler.NewHandler(service.NewService(repository.NewRepository(db), chain, precedence, profanity.Default()))
+}
+
+func addSong(t *testing.T, db *sqlx.DB, h *handler.Handler, request map[string]string) (int64, *httptest.ResponseRecorder) {
+	body, err := json.Marshal(request)
+	require.NoError(t, err)
+
+	recorder := httptest.NewRecorder()
+	h.AddSong(recorder, httptest.NewRequest(http.MethodPost, "/songs/add", bytes.NewReader(body)))
+	if recorder.Code != http.StatusCreated {
+		return 0, recorder
+	}
+
+	songId, err := strconv.ParseInt(recorder.Body.String(), 10, 64)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_, _ = db.Exec(`DELETE FROM songs WHERE id = $1`, songId)
+	})
+	return songId, recorder
+}
+
+func TestAddSongEnrichesFromUpstream(t *testing.T) {
+	db := newTestDb(t)
+	h := newTestHandler(t, db, mockinfo.Options{})
+
+	songId, recorder := addSong(t, db, h, map[string]string{"group": "Muse", "song": "Supermassive Black Hole"})
+	require.Equal(t, http.StatusCreated, recorder.Code, recorder.Body.String())
+
+	var group, title, releaseDate, precision, link string
+	err := db.QueryRow(`SELECT group_name, song_title, release_date::text, release_date_precision, link FROM songs WHERE id = $1`, songId).
+		Scan(&group, &title, &releaseDate, &precision, &link)
+	require.NoError(t, err)
+	assert.Equal(t, "Muse", group)
+	assert.Equal(t, "Supermassive Black Hole", title)
+	assert.Equal(t, "2006-07-16", releaseDate)
+	assert.Equal(t, "day", precision)
+	assert.Equal(t, "https://www.youtube.com/watch?v=Xsp3_a-PMTw", link)
+
+	var verses, sources int
+	require.NoError(t, db.QueryRow(`SELECT COUNT(*) FROM verses WHERE song_id = $1`, songId).Scan(&verses))
+	require.NoError(t, db.QueryRow(`SELECT COUNT(*) FROM song_sources WHERE song_id = $1 AND provider = 'mockinfo'`, songId).Scan(&sources))
+	assert.Equal(t, 2, verses)
+	assert.Equal(t, 3, sources)
+}
+
+func TestAddSongEscapesGroupName(t *testing.T) {
+	db := newTestDb(t)
+	h := newTestHandler(t, db, mockinfo.Options{})
+
+	for group, song := range map[string]string{"AC/DC": "Back In Black", "Simon & Garfunkel": "The Boxer"} {
+		songId, recorder := addSong(t, db, h, map[string]string{"group": group, "song": song})
+		require.Equal(t, http.StatusCreated, recorder.Code, recorder.Body.String())
+
+		var link string
+		require.NoError(t, db.QueryRow(`SELECT link FROM songs WHERE id = $1`, songId).Scan(&link))
+		assert.NotEmpty(t, link, group)
+	}
+}
+
+func TestAddSongUnparseableReleaseDate(t *testing.T) {
+	db := newTestDb(t)
+	h := newTestHandler(t, db, mockinfo.Options{})
+
+	songId, recorder := addSong(t, db, h, map[string]string{"group": "Broken", "song": "Unknown Date"})
+	require.Equal(t, http.StatusCreated, recorder.Code, recorder.Body.String())
+	assert.NotEmpty(t, recorder.Header().Values("Warning"))
+
+	var releaseDate sql.NullTime
+	require.NoError(t, db.QueryRow(`SELECT release_date FROM songs WHERE id = $1`, songId).Scan(&releaseDate))
+	assert.False(t, releaseDate.Valid)
+}
+
+func TestAddSongKeepsClientFields(t *testing.T) {
+	db := newTestDb(t)
+	h := newTestHandler(t, db, mockinfo.Options{})
+
+	songId, recorder := addSong(t, db, h, map[string]string{"group": "Muse", "song": "Uprising", "link": "https://example.com/client-link"})
+	require.Equal(t, http.StatusCreated, recorder.Code, recorder.Body.String())
+
+	var link, provider string
+	require.NoError(t, db.QueryRow(`SELECT link FROM songs WHERE id = $1`, songId).Scan(&link))
+	require.NoError(t, db.QueryRow(`SELECT provider FROM song_sources WHERE song_id = $1 AND field = 'link'`, songId).Scan(&provider))
+	assert.Equal(t, "https://example.com/client-link", link)
+	assert.Equal(t, "client", provider)
+}
+
+func TestAddSongUpstreamFailure(t *testing.T) {
+	db := newTestDb(t)
+
+	_, recorder := addSong(t, db, newTestHandler(t, db, mockinfo.Options{}), map[string]string{"group": "Broken", "song": "Unavailable"})
+	assert.Equal(t, http.StatusInternalServerError, recorder.Code)
+
+	_, recorder = addSong(t, db, newTestHandler(t, db, mockinfo.Options{ErrorRate: 1}), map[string]string{"group": "Muse", "song": "Uprising"})
+	assert.Equal(t, http.StatusInternalServerError, recorder.Code)
+}
+
+func TestAddSongRejectsDuplicate(t *testing.T) {
+	db := newTestDb(t)
+	h := newTestHandler(t, db, mockinfo.Options{})
+
+	_, recorder := addSong(t, db, h, map[string]string{"group": "Muse", "song": "Uprising"})
+	require.Equal(t, http.StatusCreated, recorder.Code, recorder.Body.String())
+
+	_, recorder = addSong(t, db, h, map[string]string{"group": "MUSE", "song": "  uprising "})
+	assert.Equal(t, http.StatusConflict, recorder.Code)
+}
@@ -0,0 +1,72 @@
+//go:build integration
+
+package integration
+
+import (
+	"BestMusicLibrary/internal/model"
+	"BestMusicLibrary/internal/repository"
+	"BestMusicLibrary/internal/search"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func addTestAlbum(t *testing.T, db *sqlx.DB, repos *repository.Repository) int64 {
+	albumId, err := repos.Album.AddAlbum(model.Album{Title: t.Name(), TitleSearchKey: search.Key(t.Name()), Type: model.AlbumTypeLP})
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_, _ = db.Exec(`DELETE FROM albums WHERE id = $1`, albumId)
+	})
+	return albumId
+}
+
+// albumTrackNumbers Номера треков альбома по песням
+func albumTrackNumbers(t *testing.T, repos *repository.Repository, albumId int64) map[int64]int {
+	album, err := repos.Album.GetAlbum(albumId)
+	require.NoError(t, err)
+
+	numbers := make(map[int64]int, len(album.Tracks))
+	for _, track := range album.Tracks {
+		numbers[track.SongId] = track.TrackNumber
+	}
+	return numbers
+}
+
+func TestAlbumTracksSwap(t *testing.T) {
+	db := newTestDb(t)
+	repos := repository.NewRepository(db)
+	songs := addTestSongs(t, db, repos, 2)
+	albumId := addTestAlbum(t, db, repos)
+
+	require.NoError(t, repos.Album.ReplaceAlbumTracks(albumId, []model.AlbumTrack{
+		{SongId: songs[0], DiscNumber: 1, TrackNumber: 1},
+		{SongId: songs[1], DiscNumber: 1, TrackNumber: 2},
+	}))
+	require.NoError(t, repos.Album.ReplaceAlbumTracks(albumId, []model.AlbumTrack{
+		{SongId: songs[0], DiscNumber: 1, TrackNumber: 2},
+		{SongId: songs[1], DiscNumber: 1, TrackNumber: 1},
+	}))
+
+	assert.Equal(t, map[int64]int{songs[0]: 2, songs[1]: 1}, albumTrackNumbers(t, repos, albumId))
+}
+
+func TestAlbumTrackPositionConflict(t *testing.T) {
+	db := newTestDb(t)
+	repos := repository.NewRepository(db)
+	songs := addTestSongs(t, db, repos, 3)
+	albumId := addTestAlbum(t, db, repos)
+
+	require.NoError(t, repos.Album.AddAlbumTrack(albumId, model.AlbumTrack{SongId: songs[0], DiscNumber: 1, TrackNumber: 1}))
+	require.NoError(t, repos.Album.AddAlbumTrack(albumId, model.AlbumTrack{SongId: songs[1], DiscNumber: 1}))
+
+	err := repos.Album.AddAlbumTrack(albumId, model.AlbumTrack{SongId: songs[2], DiscNumber: 1, TrackNumber: 2})
+	assert.ErrorIs(t, err, model.ErrConflict)
+	err = repos.Album.ReplaceAlbumTracks(albumId, []model.AlbumTrack{
+		{SongId: songs[0], DiscNumber: 1, TrackNumber: 1},
+		{SongId: songs[2], DiscNumber: 1, TrackNumber: 1},
+	})
+	assert.ErrorIs(t, err, model.ErrConflict)
+
+	assert.Equal(t, map[int64]int{songs[0]: 1, songs[1]: 2}, albumTrackNumbers(t, repos, albumId))
+}
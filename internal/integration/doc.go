@@ -0,0 +1,3 @@
+// Package integration Сквозные тесты сервиса с Postgres и локальной заменой внешнего API из cmd/mockinfo.
+// Запуск: go test -tags integration ./internal/integration/
+package integration
@@ -0,0 +1,174 @@
+//go:build integration
+
+package integration
+
+import (
+	"BestMusicLibrary/internal/model"
+	"BestMusicLibrary/internal/repository"
+	"BestMusicLibrary/internal/search"
+	"fmt"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"sync"
+	"testing"
+	"time"
+)
+
+// addTestSongs Песни для плейлистов и альбомов с уникальными названиями, удаляются после теста
+func addTestSongs(t *testing.T, db *sqlx.DB, repos *repository.Repository, count int) []int64 {
+	songIds := make([]int64, 0, count)
+	for index := 0; index < count; index++ {
+		title := fmt.Sprintf("%s %d %d", t.Name(), time.Now().UnixNano(), index)
+		songId, err := repos.Song.AddSong(model.Song{
+			Group:          "Integration",
+			GroupSearchKey: "integration",
+			Name:           title,
+			NameSearchKey:  search.Key(title),
+		})
+		require.NoError(t, err)
+		t.Cleanup(func() {
+			_, _ = db.Exec(`DELETE FROM songs WHERE id = $1`, songId)
+		})
+		songIds = append(songIds, songId)
+	}
+	return songIds
+}
+
+func addTestPlaylist(t *testing.T, db *sqlx.DB, repos *repository.Repository, owner string) int64 {
+	playlistId, err := repos.Playlist.AddPlaylist(model.Playlist{Name: t.Name(), Owner: owner})
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_, _ = db.Exec(`DELETE FROM playlists WHERE id = $1`, playlistId)
+	})
+	return playlistId
+}
+
+// playlistOrder Песни плейлиста в порядке записей и позиции записей
+func playlistOrder(t *testing.T, repos *repository.Repository, playlistId int64) ([]int64, []int64) {
+	playlist, err := repos.Playlist.GetPlaylist(playlistId)
+	require.NoError(t, err)
+
+	songIds := make([]int64, 0, len(playlist.Entries))
+	positions := make([]int64, 0, len(playlist.Entries))
+	for _, entry := range playlist.Entries {
+		songIds = append(songIds, entry.SongId)
+		positions = append(positions, entry.Position)
+	}
+	return songIds, positions
+}
+
+func TestPlaylistEntryPositions(t *testing.T) {
+	db := newTestDb(t)
+	repos := repository.NewRepository(db)
+	songs := addTestSongs(t, db, repos, 4)
+	playlistId := addTestPlaylist(t, db, repos, "anna")
+	start := int64(0)
+
+	first, err := repos.Playlist.AddPlaylistEntry(playlistId, "anna", songs[0], nil)
+	require.NoError(t, err)
+	_, err = repos.Playlist.AddPlaylistEntry(playlistId, "anna", songs[1], nil)
+	require.NoError(t, err)
+	_, err = repos.Playlist.AddPlaylistEntry(playlistId, "anna", songs[2], &start)
+	require.NoError(t, err)
+	_, err = repos.Playlist.AddPlaylistEntry(playlistId, "anna", songs[3], &first)
+	require.NoError(t, err)
+
+	order, positions := playlistOrder(t, repos, playlistId)
+	assert.Equal(t, []int64{songs[2], songs[0], songs[3], songs[1]}, order)
+	assert.Equal(t, []int64{512, 1024, 1536, 2048}, positions)
+}
+
+func TestPlaylistEntryPositionsRenumberExhaustedGap(t *testing.T) {
+	db := newTestDb(t)
+	repos := repository.NewRepository(db)
+	songs := addTestSongs(t, db, repos, 12)
+	playlistId := addTestPlaylist(t, db, repos, "anna")
+	start := int64(0)
+
+	// Каждая запись в начало делит промежуток перед первой записью пополам: 1024, 512, ..., 1.
+	// Для двенадцатой записи промежутка не остается, позиции пересчитываются с шагом 1024,
+	// и запись получает середину нового промежутка
+	for index := len(songs) - 1; index >= 0; index-- {
+		_, err := repos.Playlist.AddPlaylistEntry(playlistId, "anna", songs[index], &start)
+		require.NoError(t, err)
+	}
+
+	order, positions := playlistOrder(t, repos, playlistId)
+	assert.Equal(t, songs, order)
+	assert.Equal(t, int64(512), positions[0])
+	for index := 1; index < len(positions); index++ {
+		assert.Equal(t, int64(index)*1024, positions[index])
+	}
+}
+
+func TestPlaylistConcurrentMoves(t *testing.T) {
+	db := newTestDb(t)
+	repos := repository.NewRepository(db)
+	songs := addTestSongs(t, db, repos, 6)
+	playlistId := addTestPlaylist(t, db, repos, "anna")
+
+	entries := make([]int64, 0, len(songs))
+	for _, songId := range songs {
+		entryId, err := repos.Playlist.AddPlaylistEntry(playlistId, "anna", songId, nil)
+		require.NoError(t, err)
+		entries = append(entries, entryId)
+	}
+
+	// Две записи одновременно переносятся в один и тот же промежуток после первой записи
+	var wait sync.WaitGroup
+	errs := make([]error, 2)
+	for index, entryId := range []int64{entries[4], entries[5]} {
+		wait.Add(1)
+		go func(index int, entryId int64) {
+			defer wait.Done()
+			errs[index] = repos.Playlist.MovePlaylistEntry(playlistId, "anna", entryId, &entries[0])
+		}(index, entryId)
+	}
+	wait.Wait()
+	require.NoError(t, errs[0])
+	require.NoError(t, errs[1])
+
+	order, positions := playlistOrder(t, repos, playlistId)
+	require.Len(t, order, len(songs))
+	assert.Equal(t, songs[0], order[0])
+	assert.ElementsMatch(t, []int64{songs[4], songs[5]}, order[1:3])
+	assert.Equal(t, songs[1:4], order[3:])
+	for index := 1; index < len(positions); index++ {
+		assert.Less(t, positions[index-1], positions[index])
+	}
+}
+
+func TestPlaylistWritesRequireOwner(t *testing.T) {
+	db := newTestDb(t)
+	repos := repository.NewRepository(db)
+	songs := addTestSongs(t, db, repos, 2)
+	playlistId := addTestPlaylist(t, db, repos, "anna")
+	entryId, err := repos.Playlist.AddPlaylistEntry(playlistId, "anna", songs[0], nil)
+	require.NoError(t, err)
+
+	for _, caller := range []string{"boris", ""} {
+		err = repos.Playlist.UpdatePlaylist(model.Playlist{Id: playlistId, Name: "Чужой", Owner: caller, Public: true}, caller)
+		assert.ErrorIs(t, err, model.ErrNotFound)
+		_, err = repos.Playlist.AddPlaylistEntry(playlistId, caller, songs[1], nil)
+		assert.ErrorIs(t, err, model.ErrNotFound)
+		err = repos.Playlist.MovePlaylistEntry(playlistId, caller, entryId, nil)
+		assert.ErrorIs(t, err, model.ErrNotFound)
+		err = repos.Playlist.DeletePlaylistEntry(playlistId, caller, entryId)
+		assert.ErrorIs(t, err, model.ErrNotFound)
+		err = repos.Playlist.DeletePlaylist(playlistId, caller)
+		assert.ErrorIs(t, err, model.ErrNotFound)
+	}
+
+	playlist, err := repos.Playlist.GetPlaylist(playlistId)
+	require.NoError(t, err)
+	assert.Equal(t, t.Name(), playlist.Name)
+	assert.Equal(t, "anna", playlist.Owner)
+	assert.False(t, playlist.Public)
+	require.Len(t, playlist.Entries, 1)
+	assert.Equal(t, entryId, playlist.Entries[0].Id)
+
+	require.NoError(t, repos.Playlist.DeletePlaylist(playlistId, "anna"))
+	_, err = repos.Playlist.GetPlaylist(playlistId)
+	assert.ErrorIs(t, err, model.ErrNotFound)
+}
@@ -0,0 +1,40 @@
+//go:build integration
+
+package integration
+
+import (
+	"BestMusicLibrary/internal/model"
+	"BestMusicLibrary/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestSmartPlaylistWritesRequireOwner(t *testing.T) {
+	db := newTestDb(t)
+	repos := repository.NewRepository(db)
+	playlist := model.SmartPlaylist{Name: t.Name(), Owner: "anna", Sort: model.SongSortAdded, Limit: 10}
+	playlistId, err := repos.SmartPlaylist.AddSmartPlaylist(playlist)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_, _ = db.Exec(`DELETE FROM smart_playlists WHERE id = $1`, playlistId)
+	})
+
+	for _, caller := range []string{"boris", ""} {
+		changed := playlist
+		changed.Id, changed.Owner, changed.Public = playlistId, caller, true
+		changed.Rule = model.SmartRule{Group: "Чужая группа"}
+		assert.ErrorIs(t, repos.SmartPlaylist.UpdateSmartPlaylist(changed, caller), model.ErrNotFound)
+		assert.ErrorIs(t, repos.SmartPlaylist.DeleteSmartPlaylist(playlistId, caller), model.ErrNotFound)
+	}
+
+	stored, err := repos.SmartPlaylist.GetSmartPlaylist(playlistId)
+	require.NoError(t, err)
+	assert.Equal(t, "anna", stored.Owner)
+	assert.False(t, stored.Public)
+	assert.Equal(t, model.SmartRule{}, stored.Rule)
+
+	require.NoError(t, repos.SmartPlaylist.DeleteSmartPlaylist(playlistId, "anna"))
+	_, err = repos.SmartPlaylist.GetSmartPlaylist(playlistId)
+	assert.ErrorIs(t, err, model.ErrNotFound)
+}
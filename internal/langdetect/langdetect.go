@@ -0,0 +1,154 @@
+// Package langdetect Офлайн-определение языка текста песни. Сначала по буквам определяется письменность,
+// затем среди языков этой письменности выбирается наиболее вероятный по модели символьных триграмм,
+// обученной на встроенных текстах из каталога corpus
+package langdetect
+
+import (
+	"embed"
+	"math"
+	"path"
+	"strings"
+	"unicode"
+)
+
+//go:embed corpus/*.txt
+var corpusFiles embed.FS
+
+// minLetters Минимальное число букв, при котором язык определяется
+const minLetters = 12
+
+// minMargin Минимальный средний на триграмму отрыв лучшего языка от следующего
+const minMargin = 0.05
+
+type script int
+
+const (
+	scriptUnknown script = iota
+	scriptCyrillic
+	scriptLatin
+)
+
+type profile struct {
+	language string
+	script   script
+	counts   map[string]int
+	total    int
+}
+
+// languages Поддерживаемые языки и их письменность
+var languages = []struct {
+	language string
+	script   script
+}{
+	{"ru", scriptCyrillic},
+	{"uk", scriptCyrillic},
+	{"en", scriptLatin},
+	{"es", scriptLatin},
+	{"de", scriptLatin},
+}
+
+var (
+	profiles   []profile
+	vocabulary int
+)
+
+func init() {
+	seen := make(map[string]bool)
+	for _, supported := range languages {
+		data, err := corpusFiles.ReadFile(path.Join("corpus", supported.language+".txt"))
+		if err != nil {
+			panic(err)
+		}
+
+		p := profile{language: supported.language, script: supported.script, counts: make(map[string]int)}
+		for _, trigram := range trigrams(string(data)) {
+			p.counts[trigram]++
+			p.total++
+			seen[trigram] = true
+		}
+		profiles = append(profiles, p)
+	}
+	vocabulary = len(seen)
+}
+
+// Detect Тег BCP 47 языка текста или пустая строка, если текст слишком короткий,
+// написан на неподдерживаемой письменности или языки неразличимы
+func Detect(text string) string {
+	textScript, letters := dominantScript(text)
+	if textScript == scriptUnknown || letters < minLetters {
+		return ""
+	}
+
+	grams := trigrams(text)
+	if len(grams) == 0 {
+		return ""
+	}
+
+	best, second := "", math.Inf(-1)
+	bestScore := math.Inf(-1)
+	for _, p := range profiles {
+		if p.script != textScript {
+			continue
+		}
+		score := p.score(grams)
+		switch {
+		case score > bestScore:
+			second = bestScore
+			best, bestScore = p.language, score
+		case score > second:
+			second = score
+		}
+	}
+
+	if !math.IsInf(second, -1) && (bestScore-second)/float64(len(grams)) < minMargin {
+		return ""
+	}
+	return best
+}
+
+// score Логарифм правдоподобия триграмм со сглаживанием Лапласа
+func (p profile) score(grams []string) float64 {
+	denominator := math.Log(float64(p.total + vocabulary))
+	score := 0.0
+	for _, gram := range grams {
+		score += math.Log(float64(p.counts[gram]+1)) - denominator
+	}
+	return score
+}
+
+// dominantScript Письменность большинства букв текста и число букв этой письменности
+func dominantScript(text string) (script, int) {
+	cyrillic, latin := 0, 0
+	for _, r := range text {
+		switch {
+		case unicode.Is(unicode.Cyrillic, r):
+			cyrillic++
+		case unicode.Is(unicode.Latin, r):
+			latin++
+		}
+	}
+
+	switch {
+	case cyrillic == 0 && latin == 0:
+		return scriptUnknown, 0
+	case cyrillic >= latin:
+		return scriptCyrillic, cyrillic
+	}
+	return scriptLatin, latin
+}
+
+// trigrams Триграммы слов текста в нижнем регистре, границы слов обозначаются пробелами
+func trigrams(text string) []string {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && r != '\'' && r != 'ʼ'
+	})
+
+	grams := make([]string, 0, len(text))
+	for _, word := range words {
+		runes := []rune(" " + word + " ")
+		for index := 0; index+3 <= len(runes); index++ {
+			grams = append(grams, string(runes[index:index+3]))
+		}
+	}
+	return grams
+}
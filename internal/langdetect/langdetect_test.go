@@ -0,0 +1,30 @@
+package langdetect
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestDetect(t *testing.T) {
+	cases := map[string]string{
+		"Группа крови на рукаве, мой порядковый номер на рукаве. Пожелай мне удачи в бою":             "ru",
+		"Кино в кинотеатре, и мы сидим вдвоем, а за окном идет снег и горят фонари":                   "ru",
+		"Червона рута, не шукай вечорами, ти у мене єдина, тільки ти, повір":                          "uk",
+		"Я піду в далекі гори, на широкі полонини, де цвітуть смереки":                                "uk",
+		"Ooh baby, do you know what that's worth? Ooh heaven is a place on earth":                     "en",
+		"Hello darkness, my old friend, I've come to talk with you again":                             "en",
+		"Despacito, quiero respirar tu cuello despacito, deja que te diga cosas al oído":              "es",
+		"Bésame, bésame mucho, como si fuera esta noche la última vez":                                "es",
+		"Du hast mich gefragt und ich hab nichts gesagt. Willst du bis der Tod euch scheidet":         "de",
+		"Ich will, dass ihr mir vertraut, ich will, dass ihr mir glaubt, ich will eure Blicke spüren": "de",
+	}
+	for text, expected := range cases {
+		assert.Equal(t, expected, Detect(text), text)
+	}
+}
+
+func TestDetectUndetermined(t *testing.T) {
+	for _, text := range []string{"", "ла-ла", "1234 5678", "♪ ♪ ♪", "東京の夜は長い、そして明るい"} {
+		assert.Empty(t, Detect(text), text)
+	}
+}
@@ -0,0 +1,214 @@
+// Package lyricstats Статистика текста песни: число куплетов, строк и слов, доля уникальных слов,
+// самые частые слова без служебных, время чтения и предполагаемая схема рифмовки куплетов
+package lyricstats
+
+import (
+	"BestMusicLibrary/internal/model"
+	"BestMusicLibrary/internal/normalize"
+	"bufio"
+	"embed"
+	"math"
+	"path"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+//go:embed stopwords/*.txt
+var stopWordFiles embed.FS
+
+// Version Версия алгоритма статистики. Сохраненная статистика другой версии считается устаревшей, поэтому
+// версию нужно увеличивать при любом изменении разбиения на слова, служебных слов или определения рифм
+const Version = 1
+
+// topWordsLimit Число самых частых слов в статистике
+const topWordsLimit = 10
+
+// wordsPerMinute Скорость чтения для оценки времени чтения
+const wordsPerMinute = 180
+
+// vowels Гласные русского и английского алфавитов, по которым выделяется рифмующееся окончание строки
+const vowels = "аеиоуыэюяaeiouy"
+
+var stopWords = loadStopWords()
+
+func loadStopWords() map[string]bool {
+	names, err := stopWordFiles.ReadDir("stopwords")
+	if err != nil {
+		panic(err)
+	}
+
+	words := make(map[string]bool)
+	for _, entry := range names {
+		file, err := stopWordFiles.Open(path.Join("stopwords", entry.Name()))
+		if err != nil {
+			panic(err)
+		}
+		scanner := bufio.NewScanner(file)
+		for scanner.Scan() {
+			word := strings.TrimSpace(scanner.Text())
+			if word == "" || strings.HasPrefix(word, "#") {
+				continue
+			}
+			words[normalizeWord(word)] = true
+		}
+		_ = file.Close()
+		if err = scanner.Err(); err != nil {
+			panic(err)
+		}
+	}
+	return words
+}
+
+// Compute Статистика куплетов в порядке исполнения. Число уникальных куплетов заполняет вызывающий
+func Compute(verses []model.Verse) model.SongStats {
+	stats := model.SongStats{
+		VerseCount:   len(verses),
+		TopWords:     make([]model.WordFrequency, 0),
+		RhymeSchemes: make([]model.VerseRhymeScheme, 0, len(verses)),
+	}
+
+	counts := make(map[string]int)
+	for _, verse := range verses {
+		lines := verseLines(verse.Text)
+		stats.LineCount += len(lines)
+		for _, line := range lines {
+			for _, word := range words(line) {
+				stats.WordCount++
+				counts[word]++
+			}
+		}
+		stats.RhymeSchemes = append(stats.RhymeSchemes, model.VerseRhymeScheme{
+			VerseNumber: verse.VerseNumber,
+			Scheme:      rhymeScheme(lines),
+		})
+	}
+
+	stats.UniqueWordCount = len(counts)
+	if stats.WordCount > 0 {
+		stats.UniqueWordRatio = math.Round(float64(stats.UniqueWordCount)/float64(stats.WordCount)*1000) / 1000
+	}
+	stats.ReadingTimeSeconds = int(math.Ceil(float64(stats.WordCount) * 60 / wordsPerMinute))
+	stats.TopWords = topWords(counts)
+
+	return stats
+}
+
+// TermCounts Число употреблений слов текста без служебных и однобуквенных слов, термы для поиска похожих песен
+func TermCounts(verses []model.Verse) map[string]int {
+	counts := make(map[string]int)
+	for _, verse := range verses {
+		for _, word := range words(verse.Text) {
+			if !stopWords[word] && utf8.RuneCountInString(word) > 1 {
+				counts[word]++
+			}
+		}
+	}
+	return counts
+}
+
+// topWords Самые частые слова без служебных, при равенстве по алфавиту
+func topWords(counts map[string]int) []model.WordFrequency {
+	frequencies := make([]model.WordFrequency, 0, len(counts))
+	for word, count := range counts {
+		if !stopWords[word] {
+			frequencies = append(frequencies, model.WordFrequency{Word: word, Count: count})
+		}
+	}
+	sort.Slice(frequencies, func(i, j int) bool {
+		if frequencies[i].Count != frequencies[j].Count {
+			return frequencies[i].Count > frequencies[j].Count
+		}
+		return frequencies[i].Word < frequencies[j].Word
+	})
+	if len(frequencies) > topWordsLimit {
+		frequencies = frequencies[:topWordsLimit]
+	}
+	return frequencies
+}
+
+// rhymeScheme Схема рифмовки: строки с одинаковым окончанием последнего слова получают одну букву,
+// строки без пары обозначаются X
+func rhymeScheme(lines []string) string {
+	endings := make([]string, len(lines))
+	counts := make(map[string]int)
+	for index, line := range lines {
+		lineWords := words(line)
+		if len(lineWords) == 0 {
+			continue
+		}
+		endings[index] = rhymeEnding(lineWords[len(lineWords)-1])
+		counts[endings[index]]++
+	}
+
+	letters := make(map[string]rune)
+	next := 'A'
+	var scheme strings.Builder
+	for _, ending := range endings {
+		if ending == "" || counts[ending] < 2 {
+			scheme.WriteRune('X')
+			continue
+		}
+		letter, ok := letters[ending]
+		if !ok {
+			letter = next
+			letters[ending] = letter
+			if next < 'W' {
+				next++
+			}
+		}
+		scheme.WriteRune(letter)
+	}
+	return scheme.String()
+}
+
+// rhymeEnding Окончание слова от последней гласной. Для слова, которое кончается гласной,
+// в окончание входит и предыдущая буква, чтобы "рука" и "нога" не считались рифмой
+func rhymeEnding(word string) string {
+	runes := []rune(word)
+	last := -1
+	for index := len(runes) - 1; index >= 0; index-- {
+		if strings.ContainsRune(vowels, runes[index]) {
+			last = index
+			break
+		}
+	}
+	switch {
+	case last < 0:
+		return word
+	case last == len(runes)-1 && last > 0:
+		return string(runes[last-1:])
+	}
+	return string(runes[last:])
+}
+
+func verseLines(text string) []string {
+	lines := make([]string, 0)
+	for _, line := range strings.Split(text, "\n") {
+		if strings.TrimSpace(line) != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+// words Слова строки в нижнем регистре. Апостроф и дефис внутри слова его не разделяют.
+// Отчет о длине текстов считает слова тем же правилом в SQL (lyricsWordPattern в репозитории)
+func words(text string) []string {
+	fields := strings.FieldsFunc(normalizeWord(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '\'' && r != '-'
+	})
+
+	result := make([]string, 0, len(fields))
+	for _, field := range fields {
+		if word := strings.Trim(field, "'-"); word != "" {
+			result = append(result, word)
+		}
+	}
+	return result
+}
+
+func normalizeWord(s string) string {
+	return strings.ToLower(normalize.Text(s))
+}
@@ -0,0 +1,47 @@
+package lyricstats
+
+import (
+	"BestMusicLibrary/internal/model"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestCompute(t *testing.T) {
+	verses := []model.Verse{
+		{VerseNumber: 1, Text: "Ночь, улица, фонарь, аптека,\nБессмысленный и тусклый свет.\nЖиви еще хоть четверть века —\nВсё будет так. Исхода нет."},
+		{VerseNumber: 2, Text: "Ночь, ночь, ночь\nИ нет"},
+	}
+
+	stats := Compute(verses)
+	assert.Equal(t, 2, stats.VerseCount)
+	assert.Equal(t, 6, stats.LineCount)
+	assert.Equal(t, 23, stats.WordCount)
+	assert.Equal(t, 18, stats.UniqueWordCount)
+	assert.Equal(t, 0.783, stats.UniqueWordRatio)
+	assert.Equal(t, 8, stats.ReadingTimeSeconds)
+	assert.Equal(t, model.WordFrequency{Word: "ночь", Count: 4}, stats.TopWords[0])
+	assert.Equal(t, model.WordFrequency{Word: "аптека", Count: 1}, stats.TopWords[1])
+	for _, word := range stats.TopWords {
+		assert.NotContains(t, []string{"и", "нет"}, word.Word)
+	}
+	assert.Equal(t, []model.VerseRhymeScheme{{VerseNumber: 1, Scheme: "ABAB"}, {VerseNumber: 2, Scheme: "XX"}}, stats.RhymeSchemes)
+}
+
+func TestComputeEmpty(t *testing.T) {
+	stats := Compute(nil)
+	assert.Zero(t, stats.WordCount)
+	assert.Zero(t, stats.UniqueWordRatio)
+	assert.Empty(t, stats.TopWords)
+	assert.Empty(t, stats.RhymeSchemes)
+}
+
+func TestRhymeScheme(t *testing.T) {
+	assert.Equal(t, "AABB", rhymeScheme([]string{"I see the light", "Shining so bright", "Over the hill", "Standing so still"}))
+	assert.Equal(t, "AXAX", rhymeScheme([]string{"Моя рука", "Твоя нога", "Течет река", "Стоит зима"}))
+	assert.Equal(t, "ABAB", rhymeScheme([]string{"Любовь", "Глаза", "Кровь", "Слеза"}))
+}
+
+func TestTermCounts(t *testing.T) {
+	counts := TermCounts([]model.Verse{{Text: "Я люблю тебя, и ты любишь меня"}, {Text: "Люблю, люблю!"}})
+	assert.Equal(t, map[string]int{"люблю": 3, "любишь": 1}, counts)
+}
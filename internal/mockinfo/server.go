@@ -0,0 +1,158 @@
+package mockinfo
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/sirupsen/logrus"
+	"gopkg.in/yaml.v3"
+	"math/rand/v2"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Fixture Ответ внешнего API для пары группа-песня. Ненулевой Status заменяет ответ указанным кодом
+type Fixture struct {
+	Group       string `json:"group" yaml:"group"`
+	Song        string `json:"song" yaml:"song"`
+	ReleaseDate string `json:"release_date" yaml:"release_date"`
+	Text        string `json:"text" yaml:"text"`
+	Link        string `json:"link" yaml:"link"`
+	Status      int    `json:"status,omitempty" yaml:"status,omitempty"`
+}
+
+// Options Искусственные задержки и ошибки. С вероятностью ErrorRate запрос завершается кодом ErrorStatus
+type Options struct {
+	Latency     time.Duration
+	Jitter      time.Duration
+	ErrorRate   float64
+	ErrorStatus int
+}
+
+type songInfoResponse struct {
+	ReleaseDate string `json:"release_date"`
+	Text        string `json:"text"`
+	Link        string `json:"link"`
+}
+
+// Server Реализация контракта /info?group=&song= внешнего API поверх фикстур
+type Server struct {
+	fixtures map[string]Fixture
+	options  Options
+}
+
+func NewServer(fixtures []Fixture, options Options) *Server {
+	if options.ErrorStatus == 0 {
+		options.ErrorStatus = http.StatusInternalServerError
+	}
+
+	byKey := make(map[string]Fixture, len(fixtures))
+	for _, fixture := range fixtures {
+		byKey[fixtureKey(fixture.Group, fixture.Song)] = fixture
+	}
+	return &Server{fixtures: byKey, options: options}
+}
+
+// LoadFixtures Чтение фикстур из *.json, *.yaml и *.yml файлов каталога. Файл содержит одну фикстуру или их список
+func LoadFixtures(dir string) ([]Fixture, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	fixtures := make([]Fixture, 0)
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+
+		path := filepath.Join(dir, entry.Name())
+		var unmarshal func([]byte, any) error
+		switch strings.ToLower(filepath.Ext(entry.Name())) {
+		case ".json":
+			unmarshal = json.Unmarshal
+		case ".yaml", ".yml":
+			unmarshal = yaml.Unmarshal
+		default:
+			continue
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+
+		var list []Fixture
+		if err = unmarshal(data, &list); err != nil {
+			var single Fixture
+			if err = unmarshal(data, &single); err != nil {
+				return nil, fmt.Errorf("reading fixture %s: %w", path, err)
+			}
+			list = []Fixture{single}
+		}
+		fixtures = append(fixtures, list...)
+	}
+
+	return fixtures, nil
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/info" {
+		http.NotFound(w, r)
+		return
+	}
+	if r.Method != http.MethodGet {
+		http.Error(w, "method GET required!", http.StatusMethodNotAllowed)
+		return
+	}
+
+	group := r.URL.Query().Get("group")
+	song := r.URL.Query().Get("song")
+	logrus.WithFields(logrus.Fields{
+		"group": group,
+		"song":  song,
+	}).Info("received song info request")
+
+	s.delay()
+
+	if group == "" || song == "" {
+		http.Error(w, "group and song are required", http.StatusBadRequest)
+		return
+	}
+	if s.options.ErrorRate > 0 && rand.Float64() < s.options.ErrorRate {
+		http.Error(w, "injected error", s.options.ErrorStatus)
+		return
+	}
+
+	fixture, ok := s.fixtures[fixtureKey(group, song)]
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	if fixture.Status != 0 && fixture.Status != http.StatusOK {
+		http.Error(w, http.StatusText(fixture.Status), fixture.Status)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	err := json.NewEncoder(w).Encode(songInfoResponse{ReleaseDate: fixture.ReleaseDate, Text: fixture.Text, Link: fixture.Link})
+	if err != nil {
+		logrus.Error(err)
+	}
+}
+
+func (s *Server) delay() {
+	latency := s.options.Latency
+	if s.options.Jitter > 0 {
+		latency += rand.N(s.options.Jitter)
+	}
+	if latency > 0 {
+		time.Sleep(latency)
+	}
+}
+
+func fixtureKey(group, song string) string {
+	return strings.ToLower(strings.TrimSpace(group)) + "\x00" + strings.ToLower(strings.TrimSpace(song))
+}
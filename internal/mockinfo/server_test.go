@@ -0,0 +1,37 @@
+package mockinfo
+
+import (
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func serve(server *Server, group, song string) *httptest.ResponseRecorder {
+	recorder := httptest.NewRecorder()
+	query := url.Values{"group": {group}, "song": {song}}
+	server.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/info?"+query.Encode(), nil))
+	return recorder
+}
+
+func TestLoadFixtures(t *testing.T) {
+	fixtures, err := LoadFixtures("../../fixtures/mockinfo")
+	assert.NoError(t, err)
+
+	server := NewServer(fixtures, Options{})
+	response := serve(server, "muse", "Supermassive Black Hole")
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.JSONEq(t, `{"release_date": "16.07.2006", "text": "First line of the first verse\nSecond line of the first verse\n\nFirst line of the chorus\nSecond line of the chorus", "link": "https://www.youtube.com/watch?v=Xsp3_a-PMTw"}`, response.Body.String())
+
+	assert.Equal(t, http.StatusOK, serve(server, "Кино", "Группа крови").Code)
+	assert.Equal(t, http.StatusOK, serve(server, "AC/DC", "Back In Black").Code)
+	assert.Equal(t, http.StatusServiceUnavailable, serve(server, "Broken", "Unavailable").Code)
+	assert.Equal(t, http.StatusNotFound, serve(server, "Muse", "Unknown").Code)
+	assert.Equal(t, http.StatusBadRequest, serve(server, "Muse", "").Code)
+}
+
+func TestInjectedErrors(t *testing.T) {
+	server := NewServer([]Fixture{{Group: "Muse", Song: "Uprising"}}, Options{ErrorRate: 1, ErrorStatus: http.StatusTooManyRequests})
+	assert.Equal(t, http.StatusTooManyRequests, serve(server, "Muse", "Uprising").Code)
+}
@@ -0,0 +1,45 @@
+package model
+
+import "time"
+
+// AlbumType Тип релиза альбома
+type AlbumType string
+
+const (
+	AlbumTypeLP          AlbumType = "lp"
+	AlbumTypeEP          AlbumType = "ep"
+	AlbumTypeSingle      AlbumType = "single"
+	AlbumTypeCompilation AlbumType = "compilation"
+)
+
+// Album Альбом исполнителя. У сборника исполнителя может не быть, тогда ArtistId равен нулю.
+// Artist повторяет имя исполнителя для ответов, TitleSearchKey заполняется сервисом для поиска.
+// Tracks заполняется только при получении одного альбома, песня может входить в несколько альбомов
+type Album struct {
+	Id             int64        `json:"id"`
+	ArtistId       int64        `json:"artist_id,omitempty"`
+	Artist         string       `json:"artist,omitempty"`
+	Title          string       `json:"title"`
+	TitleSearchKey string       `json:"-"`
+	ReleaseDate    *time.Time   `json:"release_date,omitempty"`
+	Type           AlbumType    `json:"type"`
+	Tracks         []AlbumTrack `json:"tracks,omitempty"`
+	CreatedAt      time.Time    `json:"created_at"`
+	UpdatedAt      time.Time    `json:"updated_at"`
+}
+
+// AlbumTrack Песня в трек-листе альбома. Номера дисков и треков начинаются с единицы,
+// Group и Name повторяют группу и название песни для ответов
+type AlbumTrack struct {
+	SongId      int64  `json:"song_id"`
+	DiscNumber  int    `json:"disc_number"`
+	TrackNumber int    `json:"track_number"`
+	Group       string `json:"group,omitempty"`
+	Name        string `json:"name,omitempty"`
+}
+
+// AlbumFilter Фильтр альбомов по исполнителю и вхождению ключа поиска в название
+type AlbumFilter struct {
+	ArtistId int64
+	Title    string
+}
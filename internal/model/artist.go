@@ -0,0 +1,25 @@
+package model
+
+import "time"
+
+// Artist Исполнитель (группа). Песни ссылаются на исполнителя, название группы в песне повторяет каноническое имя.
+// Aliases содержит другие написания имени, по которым исполнитель находится при добавлении песен и в фильтрах,
+// AliasSearchKeys их ключи поиска в том же порядке.
+// Country задается кодом ISO 3166-1 alpha-2, неизвестные годы основания и распада равны nil
+type Artist struct {
+	Id              int64     `json:"id"`
+	Name            string    `json:"name"`
+	SearchKey       string    `json:"-"`
+	Aliases         []string  `json:"aliases"`
+	AliasSearchKeys []string  `json:"-"`
+	Country         string    `json:"country,omitempty"`
+	FormedYear      *int      `json:"formed_year,omitempty"`
+	DisbandedYear   *int      `json:"disbanded_year,omitempty"`
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+// ArtistFilter Фильтр исполнителей по вхождению ключа поиска в имя или одно из написаний
+type ArtistFilter struct {
+	Name string
+}
@@ -0,0 +1,28 @@
+package model
+
+// CreditRole Роль исполнителя в песне
+type CreditRole string
+
+const (
+	CreditRolePrimary    CreditRole = "primary"
+	CreditRoleFeatured   CreditRole = "featured"
+	CreditRoleRemixer    CreditRole = "remixer"
+	CreditRoleProducer   CreditRole = "producer"
+	CreditRoleSongwriter CreditRole = "songwriter"
+	CreditRoleComposer   CreditRole = "composer"
+)
+
+// CreditRoles Все роли в порядке вывода
+var CreditRoles = []CreditRole{
+	CreditRolePrimary, CreditRoleFeatured, CreditRoleRemixer, CreditRoleProducer, CreditRoleSongwriter, CreditRoleComposer,
+}
+
+// SongCredit Участие исполнителя в песне. Один исполнитель может участвовать в песне в нескольких ролях.
+// Artist повторяет имя исполнителя для ответов и для еще не созданных исполнителей, у которых ArtistId равен нулю.
+// SearchKey заполняется сервисом для еще не созданных исполнителей, они создаются при записи песни
+type SongCredit struct {
+	ArtistId  int64      `json:"artist_id"`
+	Artist    string     `json:"artist"`
+	Role      CreditRole `json:"role"`
+	SearchKey string     `json:"-"`
+}
@@ -0,0 +1,17 @@
+package model
+
+// Genre Жанр в иерархии жанров. У корневого жанра ParentId равен нулю.
+// Children заполняется только в дереве жанров, SearchKey используется для проверки уникальности названия
+type Genre struct {
+	Id        int64   `json:"id"`
+	ParentId  int64   `json:"parent_id,omitempty"`
+	Name      string  `json:"name"`
+	SearchKey string  `json:"-"`
+	Children  []Genre `json:"children,omitempty"`
+}
+
+// TagCount Тег и число песен с ним
+type TagCount struct {
+	Name      string `json:"name"`
+	SongCount int    `json:"song_count"`
+}
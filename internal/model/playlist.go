@@ -0,0 +1,87 @@
+package model
+
+import "time"
+
+// Playlist Подборка песен пользователя. Закрытые плейлисты видны только владельцу.
+// Entries заполняется только при получении одного плейлиста
+type Playlist struct {
+	Id          int64           `json:"id"`
+	Name        string          `json:"name"`
+	Description string          `json:"description"`
+	Owner       string          `json:"owner"`
+	Public      bool            `json:"public"`
+	Entries     []PlaylistEntry `json:"entries,omitempty"`
+	CreatedAt   time.Time       `json:"created_at"`
+	UpdatedAt   time.Time       `json:"updated_at"`
+}
+
+// PlaylistEntry Песня в плейлисте. Одна песня может входить в плейлист несколько раз, поэтому у записи свой id.
+// Позиции идут по возрастанию с промежутками, чтобы вставка и перемещение не сдвигали соседние записи.
+// Group и Name повторяют группу и название песни для ответов
+type PlaylistEntry struct {
+	Id       int64     `json:"id"`
+	SongId   int64     `json:"song_id"`
+	Position int64     `json:"position"`
+	Group    string    `json:"group,omitempty"`
+	Name     string    `json:"name,omitempty"`
+	AddedAt  time.Time `json:"added_at"`
+}
+
+// PlaylistFilter Фильтр плейлистов по владельцу. Caller — пользователь, который запрашивает список:
+// кроме открытых плейлистов в список попадают только его собственные
+type PlaylistFilter struct {
+	Owner  string
+	Caller string
+}
+
+// SongSort Порядок песен умного плейлиста
+type SongSort string
+
+const (
+	SongSortAdded       SongSort = "added"
+	SongSortTitle       SongSort = "title"
+	SongSortGroup       SongSort = "group"
+	SongSortReleaseDate SongSort = "release_date"
+	SongSortRandom      SongSort = "random"
+)
+
+// SmartPlaylist Плейлист, песни которого каждый раз подбираются по правилу Rule, не больше Limit песен
+// в порядке Sort. Descending меняет порядок на обратный, для случайного порядка не учитывается
+type SmartPlaylist struct {
+	Id          int64     `json:"id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	Owner       string    `json:"owner"`
+	Public      bool      `json:"public"`
+	Rule        SmartRule `json:"rule"`
+	Sort        SongSort  `json:"sort"`
+	Descending  bool      `json:"descending"`
+	Limit       int       `json:"limit"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// SmartRule Правило умного плейлиста, хранится в JSON. Пустые поля не учитываются, остальные условия
+// должны выполняться одновременно. Даты релиза ограничивают диапазон включительно
+type SmartRule struct {
+	Group        string     `json:"group,omitempty"`
+	GenreId      int64      `json:"genre_id,omitempty"`
+	ReleasedFrom *time.Time `json:"released_from,omitempty"`
+	ReleasedTo   *time.Time `json:"released_to,omitempty"`
+	Language     string     `json:"language,omitempty"`
+	Explicit     *bool      `json:"explicit,omitempty"`
+	Tag          string     `json:"tag,omitempty"`
+}
+
+// SongFilter Фильтр списка песен с условиями правила
+func (r SmartRule) SongFilter() SongFilter {
+	return SongFilter{
+		Group:        r.Group,
+		Language:     r.Language,
+		Explicit:     r.Explicit,
+		GenreId:      r.GenreId,
+		Tag:          r.Tag,
+		ReleasedFrom: r.ReleasedFrom,
+		ReleasedTo:   r.ReleasedTo,
+	}
+}
@@ -0,0 +1,38 @@
+package model
+
+// ReleasePeriod Период группировки песен по дате релиза
+type ReleasePeriod string
+
+const (
+	ReleasePeriodYear   ReleasePeriod = "year"
+	ReleasePeriodDecade ReleasePeriod = "decade"
+)
+
+// GroupSongCount Число песен группы. Написания группы, совпадающие по ключу поиска, считаются одной группой
+type GroupSongCount struct {
+	Group     string `json:"group"`
+	SongCount int    `json:"song_count"`
+}
+
+// ReleasePeriodCount Число песен, выпущенных в год или десятилетие, начинающееся с Year
+type ReleasePeriodCount struct {
+	Year      int `json:"year"`
+	SongCount int `json:"song_count"`
+}
+
+// LyricsLengthReport Средняя длина текста песен, у которых текст есть. Куплеты считаются в порядке исполнения
+type LyricsLengthReport struct {
+	SongCount         int     `json:"song_count"`
+	AverageVerses     float64 `json:"average_verses"`
+	AverageLines      float64 `json:"average_lines"`
+	AverageWords      float64 `json:"average_words"`
+	AverageCharacters float64 `json:"average_characters"`
+}
+
+// EnrichmentCoverage Число песен и песен без даты релиза, ссылки или текста
+type EnrichmentCoverage struct {
+	SongCount          int `json:"song_count"`
+	MissingReleaseDate int `json:"missing_release_date"`
+	MissingLink        int `json:"missing_link"`
+	MissingLyrics      int `json:"missing_lyrics"`
+}
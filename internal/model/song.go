@@ -2,18 +2,132 @@ package model
 
 import "time"
 
+// Song Песня. Verses содержит уникальные куплеты, Arrangement задает порядок их исполнения номерами куплетов.
+// Language хранит тег BCP 47 языка оригинала, пустой если язык неизвестен.
+// ArtistId ссылается на основного исполнителя, Group повторяет его каноническое имя.
+// Credits перечисляет всех участников песни с ролями, основной исполнитель в нем первый.
+// При записи песни Credits содержит разобранных из названий участников кроме основного исполнителя,
+// nil оставляет сохраненных участников без изменений.
+// Terms хранит число употреблений термов текста для поиска похожих песен, заполняется сервисом перед записью,
+// nil оставляет сохраненные термы без изменений.
+// GroupSearchKey и NameSearchKey заполняются сервисом при записи и используются для поиска и пересчета ключей.
+// ExplicitDetected хранит результат определения нецензурной лексики, nil если песня еще не проверялась,
+// ExplicitOverride решение редактора, если оно есть
 type Song struct {
-	Id          int64
-	Group       string
-	Name        string
-	ReleaseDate time.Time
-	Verses      []Verse
-	Link        string
-	CreatedAt   time.Time
-	UpdatedAt   time.Time
+	Id                   int64
+	ArtistId             int64
+	Group                string
+	Name                 string
+	GroupSearchKey       string
+	NameSearchKey        string
+	ReleaseDate          time.Time
+	ReleaseDatePrecision DatePrecision
+	Language             string
+	ExplicitDetected     *bool
+	ExplicitOverride     *bool
+	Verses               []Verse
+	Arrangement          []int
+	Link                 string
+	Sources              []SongSource
+	Credits              []SongCredit
+	Terms                map[string]int
+	CreatedAt            time.Time
+	UpdatedAt            time.Time
 }
 
+// IsExplicit Откровенная ли песня: решение редактора важнее результата определения.
+// nil если песня еще не проверялась и редактор ее не отмечал
+func (s Song) IsExplicit() *bool {
+	if s.ExplicitOverride != nil {
+		return s.ExplicitOverride
+	}
+	return s.ExplicitDetected
+}
+
+// DatePrecision Точность даты релиза: известен только год, год и месяц или полная дата
+type DatePrecision string
+
+const (
+	DatePrecisionYear  DatePrecision = "year"
+	DatePrecisionMonth DatePrecision = "month"
+	DatePrecisionDay   DatePrecision = "day"
+)
+
+// Verse Куплет песни. Повторяющиеся куплеты хранятся один раз, в сжатом виде Positions содержит
+// их позиции в порядке исполнения, а в развернутом VerseNumber равен позиции
 type Verse struct {
+	VerseNumber  int    `json:"verse_number"`
+	Text         string `json:"text"`
+	SectionType  string `json:"section_type,omitempty"`
+	SectionLabel string `json:"section_label,omitempty"`
+	Positions    []int  `json:"positions,omitempty"`
+}
+
+// VerseLine Строка куплета. Строки куплета, соединенные через "\n", в точности дают текст куплета
+type VerseLine struct {
 	VerseNumber int    `json:"verse_number"`
+	LineNumber  int    `json:"line_number"`
 	Text        string `json:"text"`
 }
+
+// TimedLine Строка текста с временем начала и конца в миллисекундах. EndMs равен нулю, если конец неизвестен
+type TimedLine struct {
+	VerseNumber int         `json:"verse_number"`
+	LineNumber  int         `json:"line_number"`
+	Text        string      `json:"text"`
+	StartMs     int64       `json:"start_ms"`
+	EndMs       int64       `json:"end_ms,omitempty"`
+	Words       []TimedWord `json:"words,omitempty"`
+}
+
+// TimedWord Слово строки с временем начала. Слово с пустым текстом в конце строки задает конец последнего слова
+type TimedWord struct {
+	StartMs int64  `json:"start_ms"`
+	Text    string `json:"text"`
+}
+
+// Типы разделов песни, задаваемые заголовками вида [Chorus] или "Припев:"
+const (
+	SectionVerse      = "verse"
+	SectionPreChorus  = "pre-chorus"
+	SectionChorus     = "chorus"
+	SectionPostChorus = "post-chorus"
+	SectionBridge     = "bridge"
+	SectionIntro      = "intro"
+	SectionOutro      = "outro"
+	SectionHook       = "hook"
+	SectionInterlude  = "interlude"
+	SectionOther      = "other"
+)
+
+// SongSource Провайдер, предоставивший поле песни при обогащении
+type SongSource struct {
+	Field     string    `json:"field"`
+	Provider  string    `json:"provider"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// SongFilter Фильтр песен: группа и название ищутся по вхождению ключа поиска, пустые поля не учитываются.
+// Язык задается тегом BCP 47, тег без региона подходит и под региональные варианты.
+// Explicit отбирает только откровенные или только остальные проверенные песни с учетом решения редактора,
+// ненулевой AlbumId только песни из трек-листа альбома, ненулевой ArtistId песни с участием исполнителя в любой роли.
+// GenreId отбирает песни жанра и всех его поджанров, Tag песни с тегом, совпадающим по ключу поиска.
+// ReleasedFrom и ReleasedTo ограничивают дату релиза включительно, песни без даты под них не подходят
+type SongFilter struct {
+	Group        string
+	Name         string
+	Language     string
+	Explicit     *bool
+	AlbumId      int64
+	ArtistId     int64
+	GenreId      int64
+	Tag          string
+	ReleasedFrom *time.Time
+	ReleasedTo   *time.Time
+}
+
+// SimilarSong Песня и косинусная близость ее текста к тексту исходной песни от 0 до 1
+type SimilarSong struct {
+	Song       Song
+	Similarity float64
+}
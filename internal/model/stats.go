@@ -0,0 +1,27 @@
+package model
+
+// SongStats Статистика текста песни. Куплеты и строки считаются в порядке исполнения, поэтому повторы учитываются
+type SongStats struct {
+	VerseCount         int                `json:"verse_count"`
+	UniqueVerseCount   int                `json:"unique_verse_count"`
+	LineCount          int                `json:"line_count"`
+	WordCount          int                `json:"word_count"`
+	UniqueWordCount    int                `json:"unique_word_count"`
+	UniqueWordRatio    float64            `json:"unique_word_ratio"`
+	TopWords           []WordFrequency    `json:"top_words"`
+	ReadingTimeSeconds int                `json:"reading_time_seconds"`
+	RhymeSchemes       []VerseRhymeScheme `json:"rhyme_schemes"`
+}
+
+// WordFrequency Слово и число его употреблений
+type WordFrequency struct {
+	Word  string `json:"word"`
+	Count int    `json:"count"`
+}
+
+// VerseRhymeScheme Предполагаемая схема рифмовки куплета, например ABAB. Строки, которые ни с чем не рифмуются,
+// обозначаются X
+type VerseRhymeScheme struct {
+	VerseNumber int    `json:"verse_number"`
+	Scheme      string `json:"scheme"`
+}
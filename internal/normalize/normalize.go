@@ -0,0 +1,23 @@
+// Package normalize Приведение названий и текстов, скопированных из разных источников, к единому виду для сравнения.
+// Результат используется только в ключах поиска и сравнения, отображаемый текст не меняется
+package normalize
+
+import (
+	"golang.org/x/text/unicode/norm"
+	"strings"
+)
+
+// punctuationReplacer Замена типографских кавычек, апострофов и тире их простыми вариантами,
+// а буквы ё буквой е
+var punctuationReplacer = strings.NewReplacer(
+	"“", `"`, "”", `"`, "„", `"`, "«", `"`, "»", `"`, "‟", `"`, "″", `"`,
+	"‘", "'", "’", "'", "‚", "'", "‛", "'", "′", "'", "ʼ", "'", "`", "'",
+	"‐", "-", "‑", "-", "‒", "-", "–", "-", "—", "-", "―", "-",
+	"ё", "е", "Ё", "Е",
+)
+
+// Text Приведение к NFC, замена кавычек, тире и буквы ё и схлопывание пробельных символов в один пробел
+func Text(s string) string {
+	s = punctuationReplacer.Replace(norm.NFC.String(s))
+	return strings.Join(strings.Fields(s), " ")
+}
@@ -0,0 +1,22 @@
+package normalize
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestText(t *testing.T) {
+	cases := map[string]string{
+		"Ёлка":                     "Елка",
+		"Мой рок\u2011н\u2011ролл": "Мой рок-н-ролл",
+		"«Кукушка»":                `"Кукушка"`,
+		"Don’t Stop Me Now":        "Don't Stop Me Now",
+		"Beyonce\u0301":            "Beyoncé",
+		"Чёрныи\u0306 кот":         "Черный кот",
+		"Нау — Прогулки":           "Нау - Прогулки",
+		"  Сплин \t\n Романс  ":    "Сплин Романс",
+	}
+	for value, expected := range cases {
+		assert.Equal(t, expected, Text(value), value)
+	}
+}
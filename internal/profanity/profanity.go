@@ -0,0 +1,161 @@
+// Package profanity Офлайн-определение нецензурной лексики в текстах песен по спискам слов.
+// Встроенные списки для русского и английского языков лежат в каталоге wordlists, дополнительные
+// списки в том же формате загружаются из каталога конфигурации
+package profanity
+
+import (
+	"BestMusicLibrary/internal/normalize"
+	"bufio"
+	"embed"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"strings"
+	"unicode"
+)
+
+//go:embed wordlists/*.txt
+var wordListFiles embed.FS
+
+// Detector Набор слов и их частей, по которым текст считается откровенным
+type Detector struct {
+	words      map[string]bool
+	prefixes   []string
+	suffixes   []string
+	substrings []string
+}
+
+var defaultDetector = mustLoadDefault()
+
+// Default Детектор со встроенными списками слов
+func Default() *Detector {
+	return defaultDetector
+}
+
+// NewDetector Детектор со встроенными списками и всеми файлами *.txt из каталога dir. Пустой dir означает только встроенные списки
+func NewDetector(dir string) (*Detector, error) {
+	detector := newEmptyDetector()
+	if err := detector.loadFS(wordListFiles, "wordlists"); err != nil {
+		return nil, err
+	}
+	if dir == "" {
+		return detector, nil
+	}
+
+	info, err := os.Stat(dir)
+	if err != nil {
+		return nil, fmt.Errorf("explicit word lists: %w", err)
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("explicit word lists: %s is not a directory", dir)
+	}
+	if err = detector.loadFS(os.DirFS(dir), "."); err != nil {
+		return nil, err
+	}
+	return detector, nil
+}
+
+func mustLoadDefault() *Detector {
+	detector, err := NewDetector("")
+	if err != nil {
+		panic(err)
+	}
+	return detector
+}
+
+func newEmptyDetector() *Detector {
+	return &Detector{words: make(map[string]bool)}
+}
+
+// loadFS Загрузка списков слов из файлов *.txt каталога
+func (d *Detector) loadFS(fileSystem fs.FS, dir string) error {
+	names, err := fs.Glob(fileSystem, path.Join(dir, "*.txt"))
+	if err != nil {
+		return err
+	}
+	for _, name := range names {
+		file, err := fileSystem.Open(name)
+		if err != nil {
+			return err
+		}
+		err = d.load(file)
+		_ = file.Close()
+		if err != nil {
+			return fmt.Errorf("explicit word list %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// load Разбор списка: одна запись в строке, строки с # пропускаются. Звездочка в начале или в конце записи
+// означает любое окончание или начало слова
+func (d *Detector) load(file fs.File) error {
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		entry := strings.TrimSpace(scanner.Text())
+		if entry == "" || strings.HasPrefix(entry, "#") {
+			continue
+		}
+
+		anyStart, anyEnd := strings.HasPrefix(entry, "*"), strings.HasSuffix(entry, "*")
+		entry = normalizeWord(strings.Trim(entry, "*"))
+		if entry == "" {
+			continue
+		}
+		switch {
+		case anyStart && anyEnd:
+			d.substrings = append(d.substrings, entry)
+		case anyStart:
+			d.suffixes = append(d.suffixes, entry)
+		case anyEnd:
+			d.prefixes = append(d.prefixes, entry)
+		default:
+			d.words[entry] = true
+		}
+	}
+	return scanner.Err()
+}
+
+// Detect Есть ли в тексте слово из списков. Регистр, форма Unicode и буква ё не учитываются
+func (d *Detector) Detect(text string) bool {
+	for _, word := range words(text) {
+		if d.matches(word) {
+			return true
+		}
+	}
+	return false
+}
+
+func (d *Detector) matches(word string) bool {
+	if d.words[word] {
+		return true
+	}
+	for _, prefix := range d.prefixes {
+		if strings.HasPrefix(word, prefix) {
+			return true
+		}
+	}
+	for _, suffix := range d.suffixes {
+		if strings.HasSuffix(word, suffix) {
+			return true
+		}
+	}
+	for _, substring := range d.substrings {
+		if strings.Contains(word, substring) {
+			return true
+		}
+	}
+	return false
+}
+
+// words Слова текста в нижнем регистре после нормализации
+func words(text string) []string {
+	return strings.FieldsFunc(normalizeWord(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+func normalizeWord(s string) string {
+	return strings.ToLower(normalize.Text(s))
+}
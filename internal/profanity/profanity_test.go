@@ -0,0 +1,55 @@
+package profanity
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDetect(t *testing.T) {
+	detector := Default()
+
+	explicit := []string{
+		"Да пошло оно всё НАХУЙ",
+		"Ну и заебал ты меня",
+		"Ёбаный стыд",
+		"What the fuck is this",
+		"Motherfuckers everywhere",
+		"This is bullshit",
+	}
+	for _, text := range explicit {
+		assert.True(t, detector.Detect(text), text)
+	}
+
+	clean := []string{
+		"Группа крови на рукаве",
+		"Ты застрахуешь меня от беды",
+		"Я ем мандарин",
+		"Сук на дереве",
+		"Supermassive black hole",
+		"Scunthorpe United",
+		"",
+	}
+	for _, text := range clean {
+		assert.False(t, detector.Detect(text), text)
+	}
+}
+
+func TestNewDetectorLoadsDirectory(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "custom.txt"), []byte("# Дополнительный список\nкрокодил\nбегемот*\n"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "notes.md"), []byte("жираф\n"), 0o644))
+
+	detector, err := NewDetector(dir)
+	require.NoError(t, err)
+	assert.True(t, detector.Detect("Зеленый Крокодил"))
+	assert.True(t, detector.Detect("бегемотики"))
+	assert.False(t, detector.Detect("жираф"))
+	assert.True(t, detector.Detect("fuck"))
+	assert.False(t, Default().Detect("крокодил"))
+
+	_, err = NewDetector(filepath.Join(dir, "missing"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,249 @@
+package repository
+
+import (
+	"BestMusicLibrary/internal/model"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+type AlbumPostgresRepository struct {
+	db *sqlx.DB
+}
+
+const albumSelect = `SELECT a.id, COALESCE(a.artist_id, 0), COALESCE(ar.name, ''), a.title, a.title_search_key,
+	a.release_date, a.album_type, a.created_at, a.updated_at
+	FROM albums a LEFT JOIN artists ar ON ar.id = a.artist_id`
+
+// GetAlbums Альбомы по фильтру, новые релизы первыми. Трек-листы не заполняются
+func (s *AlbumPostgresRepository) GetAlbums(filter model.AlbumFilter, page, limit int) ([]model.Album, error) {
+	args := make(queryArgs, 0)
+	condition := "TRUE"
+	if filter.ArtistId != 0 {
+		condition += " AND a.artist_id = " + args.add(filter.ArtistId)
+	}
+	if filter.Title != "" {
+		condition += " AND a.title_search_key LIKE '%' || " + args.add(filter.Title) + " || '%'"
+	}
+
+	rows, err := s.db.Query(albumSelect+` WHERE `+condition+
+		` ORDER BY a.release_date DESC NULLS LAST, a.id LIMIT `+args.add(limit)+` OFFSET `+args.add(page*limit), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer closeRows(rows)
+
+	albums := make([]model.Album, 0)
+	for rows.Next() {
+		album, err := scanAlbum(rows)
+		if err != nil {
+			return nil, err
+		}
+		albums = append(albums, album)
+	}
+	return albums, rows.Err()
+}
+
+// GetAlbum Альбом с трек-листом в порядке дисков и треков
+func (s *AlbumPostgresRepository) GetAlbum(id int64) (model.Album, error) {
+	album, err := scanAlbum(s.db.QueryRow(albumSelect+` WHERE a.id = $1`, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return model.Album{}, fmt.Errorf("album %d: %w", id, model.ErrNotFound)
+	}
+	if err != nil {
+		return model.Album{}, err
+	}
+
+	rows, err := s.db.Query(`
+		SELECT t.song_id, t.disc_number, t.track_number, s.group_name, s.song_title
+		FROM album_tracks t
+		JOIN songs s ON s.id = t.song_id
+		WHERE t.album_id = $1
+		ORDER BY t.disc_number, t.track_number`, id)
+	if err != nil {
+		return model.Album{}, err
+	}
+	defer closeRows(rows)
+
+	album.Tracks = make([]model.AlbumTrack, 0)
+	for rows.Next() {
+		var track model.AlbumTrack
+		if err = rows.Scan(&track.SongId, &track.DiscNumber, &track.TrackNumber, &track.Group, &track.Name); err != nil {
+			return model.Album{}, err
+		}
+		album.Tracks = append(album.Tracks, track)
+	}
+	return album, rows.Err()
+}
+
+func (s *AlbumPostgresRepository) AddAlbum(album model.Album) (int64, error) {
+	var albumId int64
+	err := s.db.QueryRow(`
+		INSERT INTO albums(artist_id, title, title_search_key, release_date, album_type)
+		VALUES(NULLIF($1, 0), $2, $3, $4, $5) RETURNING id`,
+		album.ArtistId, album.Title, album.TitleSearchKey, album.ReleaseDate, album.Type).Scan(&albumId)
+	return albumId, err
+}
+
+func (s *AlbumPostgresRepository) UpdateAlbum(album model.Album) error {
+	result, err := s.db.Exec(`
+		UPDATE albums
+		SET artist_id = NULLIF($1, 0), title = $2, title_search_key = $3, release_date = $4, album_type = $5, updated_at = NOW()
+		WHERE id = $6`,
+		album.ArtistId, album.Title, album.TitleSearchKey, album.ReleaseDate, album.Type, album.Id)
+	if err != nil {
+		return err
+	}
+	return albumAffected(result, album.Id)
+}
+
+// DeleteAlbum Удаление альбома вместе с трек-листом, песни остаются
+func (s *AlbumPostgresRepository) DeleteAlbum(id int64) error {
+	result, err := s.db.Exec(`DELETE FROM albums WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	return albumAffected(result, id)
+}
+
+// ReplaceAlbumTracks Замена трек-листа альбома. Уникальность позиций проверяется при фиксации транзакции,
+// поэтому треки можно менять местами
+func (s *AlbumPostgresRepository) ReplaceAlbumTracks(albumId int64, tracks []model.AlbumTrack) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+
+	if err = lockAlbum(tx, albumId); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	if _, err = tx.Exec(`DELETE FROM album_tracks WHERE album_id = $1`, albumId); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	songIds := make([]int64, 0, len(tracks))
+	discNumbers := make([]int64, 0, len(tracks))
+	trackNumbers := make([]int64, 0, len(tracks))
+	for _, track := range tracks {
+		songIds = append(songIds, track.SongId)
+		discNumbers = append(discNumbers, int64(track.DiscNumber))
+		trackNumbers = append(trackNumbers, int64(track.TrackNumber))
+	}
+	_, err = tx.Exec(`
+		INSERT INTO album_tracks(album_id, song_id, disc_number, track_number)
+		SELECT $1, t.song_id, t.disc_number, t.track_number
+		FROM UNNEST($2::INT[], $3::INT[], $4::INT[]) AS t(song_id, disc_number, track_number)`,
+		albumId, pq.Array(songIds), pq.Array(discNumbers), pq.Array(trackNumbers))
+	if err != nil {
+		_ = tx.Rollback()
+		return trackViolationToError(err)
+	}
+
+	if err = touchAlbum(tx, albumId); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	return trackViolationToError(tx.Commit())
+}
+
+// AddAlbumTrack Добавление песни в альбом. Нулевой номер трека означает следующий после последнего трека диска
+func (s *AlbumPostgresRepository) AddAlbumTrack(albumId int64, track model.AlbumTrack) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+
+	if err = lockAlbum(tx, albumId); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	_, err = tx.Exec(`
+		INSERT INTO album_tracks(album_id, song_id, disc_number, track_number)
+		SELECT $1, $2, $3, COALESCE(NULLIF($4, 0), MAX(track_number) + 1, 1)
+		FROM album_tracks
+		WHERE album_id = $1 AND disc_number = $3`,
+		albumId, track.SongId, track.DiscNumber, track.TrackNumber)
+	if err != nil {
+		_ = tx.Rollback()
+		return trackViolationToError(err)
+	}
+
+	if err = touchAlbum(tx, albumId); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	return trackViolationToError(tx.Commit())
+}
+
+// DeleteAlbumTrack Удаление песни из трек-листа, номера остальных треков не меняются
+func (s *AlbumPostgresRepository) DeleteAlbumTrack(albumId, songId int64) error {
+	result, err := s.db.Exec(`DELETE FROM album_tracks WHERE album_id = $1 AND song_id = $2`, albumId, songId)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return fmt.Errorf("song %d on album %d: %w", songId, albumId, model.ErrNotFound)
+	}
+	_, err = s.db.Exec(`UPDATE albums SET updated_at = NOW() WHERE id = $1`, albumId)
+	return err
+}
+
+// lockAlbum Блокировка строки альбома, чтобы изменения трек-листа одного альбома выполнялись по очереди
+func lockAlbum(tx *sql.Tx, albumId int64) error {
+	var id int64
+	err := tx.QueryRow(`SELECT id FROM albums WHERE id = $1 FOR UPDATE`, albumId).Scan(&id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("album %d: %w", albumId, model.ErrNotFound)
+	}
+	return err
+}
+
+func touchAlbum(tx *sql.Tx, albumId int64) error {
+	_, err := tx.Exec(`UPDATE albums SET updated_at = NOW() WHERE id = $1`, albumId)
+	return err
+}
+
+func albumAffected(result sql.Result, id int64) error {
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return fmt.Errorf("album %d: %w", id, model.ErrNotFound)
+	}
+	return nil
+}
+
+// trackViolationToError Несуществующая песня в трек-листе означает неверный запрос,
+// повтор песни или позиции в альбоме означает конфликт
+func trackViolationToError(err error) error {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == "23503" {
+		return fmt.Errorf("%w: unknown song in tracklist", model.ErrInvalidInput)
+	}
+	return uniqueViolationToConflict(err)
+}
+
+func scanAlbum(row rowScanner) (model.Album, error) {
+	var album model.Album
+	var releaseDate sql.NullTime
+	err := row.Scan(&album.Id, &album.ArtistId, &album.Artist, &album.Title, &album.TitleSearchKey,
+		&releaseDate, &album.Type, &album.CreatedAt, &album.UpdatedAt)
+	if err != nil {
+		return model.Album{}, err
+	}
+	if releaseDate.Valid {
+		album.ReleaseDate = &releaseDate.Time
+	}
+	return album, nil
+}
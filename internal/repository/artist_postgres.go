@@ -0,0 +1,202 @@
+package repository
+
+import (
+	"BestMusicLibrary/internal/model"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+type ArtistPostgresRepository struct {
+	db *sqlx.DB
+}
+
+const artistColumns = `id, name, search_key, country, formed_year, disbanded_year, created_at, updated_at,
+	ARRAY(SELECT alias FROM artist_aliases WHERE artist_id = artists.id ORDER BY alias)`
+
+// GetArtists Исполнители по фильтру в порядке имени
+func (s *ArtistPostgresRepository) GetArtists(filter model.ArtistFilter, page, limit int) ([]model.Artist, error) {
+	args := make(queryArgs, 0)
+	condition := "TRUE"
+	if filter.Name != "" {
+		name := args.add(filter.Name)
+		condition = `(search_key LIKE '%' || ` + name + ` || '%' OR EXISTS (
+			SELECT 1 FROM artist_aliases WHERE artist_id = artists.id AND search_key LIKE '%' || ` + name + ` || '%'))`
+	}
+
+	rows, err := s.db.Query(`SELECT `+artistColumns+` FROM artists WHERE `+condition+
+		` ORDER BY name, id LIMIT `+args.add(limit)+` OFFSET `+args.add(page*limit), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer closeRows(rows)
+
+	artists := make([]model.Artist, 0)
+	for rows.Next() {
+		artist, err := scanArtist(rows)
+		if err != nil {
+			return nil, err
+		}
+		artists = append(artists, artist)
+	}
+	return artists, rows.Err()
+}
+
+func (s *ArtistPostgresRepository) GetArtist(id int64) (model.Artist, error) {
+	artist, err := scanArtist(s.db.QueryRow(`SELECT `+artistColumns+` FROM artists WHERE id = $1`, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return model.Artist{}, fmt.Errorf("artist %d: %w", id, model.ErrNotFound)
+	}
+	return artist, err
+}
+
+// FindArtistBySearchKey Исполнитель, ключ поиска имени или одного из написаний которого равен searchKey
+func (s *ArtistPostgresRepository) FindArtistBySearchKey(searchKey string) (model.Artist, error) {
+	artist, err := scanArtist(s.db.QueryRow(`
+		SELECT `+artistColumns+`
+		FROM artists
+		WHERE search_key = $1 OR id IN (SELECT artist_id FROM artist_aliases WHERE search_key = $1)
+		ORDER BY search_key = $1 DESC, id
+		LIMIT 1`, searchKey))
+	if errors.Is(err, sql.ErrNoRows) {
+		return model.Artist{}, fmt.Errorf("artist %q: %w", searchKey, model.ErrNotFound)
+	}
+	return artist, err
+}
+
+func (s *ArtistPostgresRepository) AddArtist(artist model.Artist) (int64, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return 0, err
+	}
+
+	var artistId int64
+	err = tx.QueryRow(`
+		INSERT INTO artists(name, search_key, country, formed_year, disbanded_year)
+		VALUES($1, $2, $3, $4, $5) RETURNING id`,
+		artist.Name, artist.SearchKey, artist.Country, artist.FormedYear, artist.DisbandedYear).Scan(&artistId)
+	if err != nil {
+		_ = tx.Rollback()
+		return 0, uniqueViolationToConflict(err)
+	}
+
+	if err = insertArtistAliases(tx, artistId, artist); err != nil {
+		_ = tx.Rollback()
+		return 0, err
+	}
+
+	return artistId, tx.Commit()
+}
+
+// UpdateArtist Изменение исполнителя. Новое имя сразу записывается во все его песни
+func (s *ArtistPostgresRepository) UpdateArtist(artist model.Artist) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+
+	result, err := tx.Exec(`
+		UPDATE artists
+		SET name = $1, search_key = $2, country = $3, formed_year = $4, disbanded_year = $5, updated_at = NOW()
+		WHERE id = $6`,
+		artist.Name, artist.SearchKey, artist.Country, artist.FormedYear, artist.DisbandedYear, artist.Id)
+	if err != nil {
+		_ = tx.Rollback()
+		return uniqueViolationToConflict(err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	if affected == 0 {
+		_ = tx.Rollback()
+		return fmt.Errorf("artist %d: %w", artist.Id, model.ErrNotFound)
+	}
+
+	if _, err = tx.Exec(`DELETE FROM artist_aliases WHERE artist_id = $1`, artist.Id); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	if err = insertArtistAliases(tx, artist.Id, artist); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	_, err = tx.Exec(`
+		UPDATE songs SET group_name = $1, group_search_key = $2, updated_at = NOW()
+		WHERE artist_id = $3 AND (group_name <> $1 OR group_search_key <> $2)`,
+		artist.Name, artist.SearchKey, artist.Id)
+	if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// DeleteArtist Удаление исполнителя, у которого нет песен и альбомов
+func (s *ArtistPostgresRepository) DeleteArtist(id int64) error {
+	result, err := s.db.Exec(`DELETE FROM artists WHERE id = $1`, id)
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == "23503" {
+		return fmt.Errorf("%w: artist %d still has songs or albums", model.ErrConflict, id)
+	}
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return fmt.Errorf("artist %d: %w", id, model.ErrNotFound)
+	}
+	return nil
+}
+
+func insertArtistAliases(tx *sql.Tx, artistId int64, artist model.Artist) error {
+	for index, alias := range artist.Aliases {
+		_, err := tx.Exec(`INSERT INTO artist_aliases(artist_id, alias, search_key) VALUES($1, $2, $3) ON CONFLICT DO NOTHING`,
+			artistId, alias, artist.AliasSearchKeys[index])
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// uniqueViolationToConflict Нарушение любого ограничения уникальности означает, что такая запись уже есть.
+// Подробности Postgres с именами столбцов и значениями ключа в ответ не попадают
+func uniqueViolationToConflict(err error) error {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+		return fmt.Errorf("%w: such a record already exists", model.ErrConflict)
+	}
+	return err
+}
+
+func scanArtist(row rowScanner) (model.Artist, error) {
+	var artist model.Artist
+	var formedYear, disbandedYear sql.NullInt64
+	err := row.Scan(&artist.Id, &artist.Name, &artist.SearchKey, &artist.Country, &formedYear, &disbandedYear,
+		&artist.CreatedAt, &artist.UpdatedAt, pq.Array(&artist.Aliases))
+	if err != nil {
+		return model.Artist{}, err
+	}
+
+	if formedYear.Valid {
+		year := int(formedYear.Int64)
+		artist.FormedYear = &year
+	}
+	if disbandedYear.Valid {
+		year := int(disbandedYear.Int64)
+		artist.DisbandedYear = &year
+	}
+	if artist.Aliases == nil {
+		artist.Aliases = make([]string, 0)
+	}
+	return artist, nil
+}
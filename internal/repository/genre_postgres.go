@@ -0,0 +1,161 @@
+package repository
+
+import (
+	"BestMusicLibrary/internal/model"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+type GenrePostgresRepository struct {
+	db *sqlx.DB
+}
+
+const genreColumns = `id, COALESCE(parent_id, 0), name, search_key`
+
+// GetGenres Все жанры по алфавиту без вложенности
+func (s *GenrePostgresRepository) GetGenres() ([]model.Genre, error) {
+	return s.queryGenres(`SELECT ` + genreColumns + ` FROM genres ORDER BY name, id`)
+}
+
+func (s *GenrePostgresRepository) GetGenre(id int64) (model.Genre, error) {
+	var genre model.Genre
+	err := s.db.QueryRow(`SELECT `+genreColumns+` FROM genres WHERE id = $1`, id).
+		Scan(&genre.Id, &genre.ParentId, &genre.Name, &genre.SearchKey)
+	if errors.Is(err, sql.ErrNoRows) {
+		return model.Genre{}, fmt.Errorf("genre %d: %w", id, model.ErrNotFound)
+	}
+	return genre, err
+}
+
+func (s *GenrePostgresRepository) AddGenre(genre model.Genre) (int64, error) {
+	var genreId int64
+	err := s.db.QueryRow(`INSERT INTO genres(parent_id, name, search_key) VALUES(NULLIF($1, 0), $2, $3) RETURNING id`,
+		genre.ParentId, genre.Name, genre.SearchKey).Scan(&genreId)
+	return genreId, uniqueViolationToConflict(err)
+}
+
+// UpdateGenre Изменение жанра. Перенос жанра под самого себя или свой поджанр отклоняется.
+// Проверка выполняется под блокировкой всех жанров, поэтому два встречных переноса не образуют цикл
+func (s *GenrePostgresRepository) UpdateGenre(genre model.Genre) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+
+	if _, err = tx.Exec(`SELECT id FROM genres ORDER BY id FOR UPDATE`); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	if genre.ParentId != 0 {
+		var cycle bool
+		err = tx.QueryRow(`
+			WITH RECURSIVE ancestors AS (
+				SELECT id, parent_id FROM genres WHERE id = $1
+				UNION
+				SELECT g.id, g.parent_id FROM genres g JOIN ancestors a ON g.id = a.parent_id
+			)
+			SELECT EXISTS(SELECT 1 FROM ancestors WHERE id = $2)`, genre.ParentId, genre.Id).Scan(&cycle)
+		if err != nil {
+			_ = tx.Rollback()
+			return err
+		}
+		if cycle {
+			_ = tx.Rollback()
+			return fmt.Errorf("%w: genre %d cannot be moved under its own subgenre", model.ErrInvalidInput, genre.Id)
+		}
+	}
+
+	result, err := tx.Exec(`UPDATE genres SET parent_id = NULLIF($1, 0), name = $2, search_key = $3 WHERE id = $4`,
+		genre.ParentId, genre.Name, genre.SearchKey, genre.Id)
+	if err != nil {
+		_ = tx.Rollback()
+		return uniqueViolationToConflict(err)
+	}
+	if err = genreAffected(result, genre.Id); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// DeleteGenre Удаление жанра без поджанров, на который не ссылаются правила умных плейлистов.
+// Песни жанра теряют только этот жанр
+func (s *GenrePostgresRepository) DeleteGenre(id int64) error {
+	result, err := s.db.Exec(`DELETE FROM genres WHERE id = $1`, id)
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == "23503" {
+		if pqErr.Constraint == smartPlaylistGenreConstraint {
+			return fmt.Errorf("%w: genre %d is used by smart playlist rules", model.ErrConflict, id)
+		}
+		return fmt.Errorf("%w: genre %d has subgenres", model.ErrConflict, id)
+	}
+	if err != nil {
+		return err
+	}
+	return genreAffected(result, id)
+}
+
+// GetSongGenres Жанры песни по алфавиту
+func (s *GenrePostgresRepository) GetSongGenres(songId int64) ([]model.Genre, error) {
+	return s.queryGenres(`
+		SELECT g.id, COALESCE(g.parent_id, 0), g.name, g.search_key
+		FROM song_genres sg
+		JOIN genres g ON g.id = sg.genre_id
+		WHERE sg.song_id = $1
+		ORDER BY g.name, g.id`, songId)
+}
+
+// AddSongGenre Назначение жанра песне. Повторное назначение ничего не меняет
+func (s *GenrePostgresRepository) AddSongGenre(songId, genreId int64) error {
+	_, err := s.db.Exec(`INSERT INTO song_genres(song_id, genre_id) VALUES($1, $2) ON CONFLICT DO NOTHING`, songId, genreId)
+	return err
+}
+
+func (s *GenrePostgresRepository) DeleteSongGenre(songId, genreId int64) error {
+	result, err := s.db.Exec(`DELETE FROM song_genres WHERE song_id = $1 AND genre_id = $2`, songId, genreId)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return fmt.Errorf("genre %d of song %d: %w", genreId, songId, model.ErrNotFound)
+	}
+	return nil
+}
+
+func (s *GenrePostgresRepository) queryGenres(query string, args ...any) ([]model.Genre, error) {
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer closeRows(rows)
+
+	genres := make([]model.Genre, 0)
+	for rows.Next() {
+		var genre model.Genre
+		if err = rows.Scan(&genre.Id, &genre.ParentId, &genre.Name, &genre.SearchKey); err != nil {
+			return nil, err
+		}
+		genres = append(genres, genre)
+	}
+	return genres, rows.Err()
+}
+
+func genreAffected(result sql.Result, id int64) error {
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return fmt.Errorf("genre %d: %w", id, model.ErrNotFound)
+	}
+	return nil
+}
@@ -0,0 +1,92 @@
+package repository
+
+import (
+	"BestMusicLibrary/internal/model"
+	"database/sql"
+	"encoding/json"
+	"github.com/sirupsen/logrus"
+)
+
+func (s *SongPostgresRepository) GetLyricTimings(id int64) ([]model.TimedLine, error) {
+	rows, err := s.db.Query(`
+		SELECT position, line_number, text, start_ms, end_ms, words
+		FROM lyric_timings
+		WHERE song_id = $1
+		ORDER BY start_ms`, id)
+	if err != nil {
+		return nil, err
+	}
+	defer func(rows *sql.Rows) {
+		err := rows.Close()
+		if err != nil {
+			logrus.Error(err)
+		}
+	}(rows)
+
+	lines := make([]model.TimedLine, 0)
+	for rows.Next() {
+		var line model.TimedLine
+		var endMs sql.NullInt64
+		var words []byte
+		if err = rows.Scan(&line.VerseNumber, &line.LineNumber, &line.Text, &line.StartMs, &endMs, &words); err != nil {
+			return nil, err
+		}
+		line.EndMs = endMs.Int64
+		if err = json.Unmarshal(words, &line.Words); err != nil {
+			return nil, err
+		}
+		lines = append(lines, line)
+	}
+	return lines, rows.Err()
+}
+
+func (s *SongPostgresRepository) ReplaceLyricTimings(id int64, lines []model.TimedLine) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+
+	if _, err = tx.Exec(`DELETE FROM lyric_timings WHERE song_id = $1`, id); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	for _, line := range lines {
+		words, err := json.Marshal(line.Words)
+		if err != nil {
+			_ = tx.Rollback()
+			return err
+		}
+		var endMs any
+		if line.EndMs != 0 {
+			endMs = line.EndMs
+		}
+
+		_, err = tx.Exec(`
+		INSERT
+		INTO
+		lyric_timings(song_id, position, line_number, text, start_ms, end_ms, words)
+		VALUES($1, $2, $3, $4, $5, $6, $7)`,
+			id, line.VerseNumber, line.LineNumber, line.Text, line.StartMs, endMs, words)
+		if err != nil {
+			_ = tx.Rollback()
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// deleteStaleLyricTimings Удаление меток времени строк, текст которых изменился или исчез после обновления песни
+func deleteStaleLyricTimings(tx *sql.Tx, songId int64) error {
+	_, err := tx.Exec(`
+		DELETE FROM lyric_timings t
+		WHERE t.song_id = $1
+		AND NOT EXISTS (
+			SELECT 1
+			FROM verse_arrangement a
+			JOIN verse_lines l ON l.verse_id = a.verse_id
+			WHERE a.song_id = t.song_id AND a.position = t.position AND l.line_number = t.line_number AND l.text = t.text
+		)`, songId)
+	return err
+}
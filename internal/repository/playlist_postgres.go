@@ -0,0 +1,323 @@
+package repository
+
+import (
+	"BestMusicLibrary/internal/model"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+// playlistPositionGap Шаг между позициями соседних записей плейлиста. Новая запись между соседями получает
+// середину промежутка, и только когда промежуток исчерпан, позиции плейлиста пересчитываются заново
+const playlistPositionGap = 1024
+
+type PlaylistPostgresRepository struct {
+	db *sqlx.DB
+}
+
+const playlistSelect = `SELECT id, name, description, owner, is_public, created_at, updated_at FROM playlists`
+
+// GetPlaylists Открытые плейлисты и плейлисты пользователя filter.Caller, новые первыми.
+// С владельцем в список попадают только его плейлисты. Записи не заполняются
+func (s *PlaylistPostgresRepository) GetPlaylists(filter model.PlaylistFilter, page, limit int) ([]model.Playlist, error) {
+	args := make(queryArgs, 0)
+	condition := "(is_public OR owner = " + args.add(filter.Caller) + ")"
+	if filter.Owner != "" {
+		condition += " AND owner = " + args.add(filter.Owner)
+	}
+
+	rows, err := s.db.Query(playlistSelect+` WHERE `+condition+
+		` ORDER BY created_at DESC, id DESC LIMIT `+args.add(limit)+` OFFSET `+args.add(page*limit), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer closeRows(rows)
+
+	playlists := make([]model.Playlist, 0)
+	for rows.Next() {
+		playlist, err := scanPlaylist(rows)
+		if err != nil {
+			return nil, err
+		}
+		playlists = append(playlists, playlist)
+	}
+	return playlists, rows.Err()
+}
+
+// GetPlaylist Плейлист с записями в порядке позиций
+func (s *PlaylistPostgresRepository) GetPlaylist(id int64) (model.Playlist, error) {
+	playlist, err := scanPlaylist(s.db.QueryRow(playlistSelect+` WHERE id = $1`, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return model.Playlist{}, fmt.Errorf("playlist %d: %w", id, model.ErrNotFound)
+	}
+	if err != nil {
+		return model.Playlist{}, err
+	}
+
+	rows, err := s.db.Query(`
+		SELECT e.id, e.song_id, e.position, s.group_name, s.song_title, e.added_at
+		FROM playlist_entries e
+		JOIN songs s ON s.id = e.song_id
+		WHERE e.playlist_id = $1
+		ORDER BY e.position`, id)
+	if err != nil {
+		return model.Playlist{}, err
+	}
+	defer closeRows(rows)
+
+	playlist.Entries = make([]model.PlaylistEntry, 0)
+	for rows.Next() {
+		var entry model.PlaylistEntry
+		if err = rows.Scan(&entry.Id, &entry.SongId, &entry.Position, &entry.Group, &entry.Name, &entry.AddedAt); err != nil {
+			return model.Playlist{}, err
+		}
+		playlist.Entries = append(playlist.Entries, entry)
+	}
+	return playlist, rows.Err()
+}
+
+func (s *PlaylistPostgresRepository) AddPlaylist(playlist model.Playlist) (int64, error) {
+	var playlistId int64
+	err := s.db.QueryRow(`
+		INSERT INTO playlists(name, description, owner, is_public)
+		VALUES($1, $2, $3, $4) RETURNING id`,
+		playlist.Name, playlist.Description, playlist.Owner, playlist.Public).Scan(&playlistId)
+	return playlistId, err
+}
+
+// UpdatePlaylist Изменение плейлиста владельцем caller, в том числе передача плейлиста другому владельцу
+func (s *PlaylistPostgresRepository) UpdatePlaylist(playlist model.Playlist, caller string) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+
+	if err = lockPlaylist(tx, playlist.Id, caller); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	_, err = tx.Exec(`
+		UPDATE playlists
+		SET name = $1, description = $2, owner = $3, is_public = $4, updated_at = NOW()
+		WHERE id = $5`,
+		playlist.Name, playlist.Description, playlist.Owner, playlist.Public, playlist.Id)
+	if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// DeletePlaylist Удаление плейлиста владельцем caller вместе с записями, песни остаются
+func (s *PlaylistPostgresRepository) DeletePlaylist(id int64, caller string) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+
+	if err = lockPlaylist(tx, id, caller); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	if _, err = tx.Exec(`DELETE FROM playlists WHERE id = $1`, id); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// AddPlaylistEntry Добавление песни в плейлист владельца caller после записи afterEntryId. Нулевой afterEntryId
+// означает начало плейлиста, nil — конец
+func (s *PlaylistPostgresRepository) AddPlaylistEntry(playlistId int64, caller string, songId int64, afterEntryId *int64) (int64, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return 0, err
+	}
+
+	if err = lockPlaylist(tx, playlistId, caller); err != nil {
+		_ = tx.Rollback()
+		return 0, err
+	}
+
+	position, err := entryPosition(tx, playlistId, 0, afterEntryId)
+	if err != nil {
+		_ = tx.Rollback()
+		return 0, err
+	}
+
+	var entryId int64
+	err = tx.QueryRow(`
+		INSERT INTO playlist_entries(playlist_id, song_id, position)
+		VALUES($1, $2, $3) RETURNING id`, playlistId, songId, position).Scan(&entryId)
+	if err != nil {
+		_ = tx.Rollback()
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23503" {
+			return 0, fmt.Errorf("%w: unknown song %d", model.ErrInvalidInput, songId)
+		}
+		return 0, err
+	}
+
+	if err = touchPlaylist(tx, playlistId); err != nil {
+		_ = tx.Rollback()
+		return 0, err
+	}
+	return entryId, tx.Commit()
+}
+
+// MovePlaylistEntry Перемещение записи плейлиста после записи afterEntryId по тем же правилам, что и при добавлении.
+// Позиции остальных записей меняются только при пересчете исчерпанных промежутков
+func (s *PlaylistPostgresRepository) MovePlaylistEntry(playlistId int64, caller string, entryId int64, afterEntryId *int64) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+
+	if err = lockPlaylist(tx, playlistId, caller); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	position, err := entryPosition(tx, playlistId, entryId, afterEntryId)
+	if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	result, err := tx.Exec(`UPDATE playlist_entries SET position = $1 WHERE id = $2 AND playlist_id = $3`,
+		position, entryId, playlistId)
+	if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	if err = playlistEntryAffected(result, playlistId, entryId); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	if err = touchPlaylist(tx, playlistId); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// DeletePlaylistEntry Удаление записи из плейлиста владельца caller, позиции остальных записей не меняются
+func (s *PlaylistPostgresRepository) DeletePlaylistEntry(playlistId int64, caller string, entryId int64) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+
+	if err = lockPlaylist(tx, playlistId, caller); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	result, err := tx.Exec(`DELETE FROM playlist_entries WHERE id = $1 AND playlist_id = $2`, entryId, playlistId)
+	if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	if err = playlistEntryAffected(result, playlistId, entryId); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	if err = touchPlaylist(tx, playlistId); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// entryPosition Позиция записи после afterEntryId: середина промежутка до следующей записи или шаг после последней.
+// Перемещаемая запись movingEntryId не считается соседом. Если промежуток исчерпан, позиции всех записей
+// плейлиста пересчитываются с шагом playlistPositionGap, уникальность проверяется при фиксации транзакции
+func entryPosition(tx *sql.Tx, playlistId, movingEntryId int64, afterEntryId *int64) (int64, error) {
+	if afterEntryId == nil {
+		var last int64
+		err := tx.QueryRow(`
+			SELECT COALESCE(MAX(position), 0) FROM playlist_entries
+			WHERE playlist_id = $1 AND id <> $2`, playlistId, movingEntryId).Scan(&last)
+		return last + playlistPositionGap, err
+	}
+
+	for renumbered := false; ; renumbered = true {
+		var previous int64
+		if *afterEntryId != 0 {
+			err := tx.QueryRow(`SELECT position FROM playlist_entries WHERE id = $1 AND playlist_id = $2`,
+				*afterEntryId, playlistId).Scan(&previous)
+			if errors.Is(err, sql.ErrNoRows) {
+				return 0, fmt.Errorf("%w: entry %d is not in playlist %d", model.ErrInvalidInput, *afterEntryId, playlistId)
+			}
+			if err != nil {
+				return 0, err
+			}
+		}
+
+		var next sql.NullInt64
+		err := tx.QueryRow(`
+			SELECT MIN(position) FROM playlist_entries
+			WHERE playlist_id = $1 AND position > $2 AND id <> $3`, playlistId, previous, movingEntryId).Scan(&next)
+		if err != nil {
+			return 0, err
+		}
+		if !next.Valid {
+			return previous + playlistPositionGap, nil
+		}
+		if next.Int64-previous > 1 || renumbered {
+			return previous + (next.Int64-previous)/2, nil
+		}
+
+		_, err = tx.Exec(`
+			UPDATE playlist_entries e SET position = r.number * $2
+			FROM (
+				SELECT id, ROW_NUMBER() OVER (ORDER BY position) AS number
+				FROM playlist_entries WHERE playlist_id = $1
+			) r
+			WHERE e.id = r.id`, playlistId, playlistPositionGap)
+		if err != nil {
+			return 0, err
+		}
+	}
+}
+
+// lockPlaylist Блокировка строки плейлиста, чтобы изменения одного плейлиста выполнялись по очереди.
+// Изменять плейлист может только владелец, для остальных пользователей плейлист не найден,
+// как и при чтении закрытого плейлиста
+func lockPlaylist(tx *sql.Tx, playlistId int64, caller string) error {
+	var owner string
+	err := tx.QueryRow(`SELECT owner FROM playlists WHERE id = $1 FOR UPDATE`, playlistId).Scan(&owner)
+	if errors.Is(err, sql.ErrNoRows) || err == nil && owner != caller {
+		return fmt.Errorf("playlist %d: %w", playlistId, model.ErrNotFound)
+	}
+	return err
+}
+
+func touchPlaylist(tx *sql.Tx, playlistId int64) error {
+	_, err := tx.Exec(`UPDATE playlists SET updated_at = NOW() WHERE id = $1`, playlistId)
+	return err
+}
+
+func playlistEntryAffected(result sql.Result, playlistId, entryId int64) error {
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return fmt.Errorf("entry %d in playlist %d: %w", entryId, playlistId, model.ErrNotFound)
+	}
+	return nil
+}
+
+func scanPlaylist(row rowScanner) (model.Playlist, error) {
+	var playlist model.Playlist
+	err := row.Scan(&playlist.Id, &playlist.Name, &playlist.Description, &playlist.Owner, &playlist.Public,
+		&playlist.CreatedAt, &playlist.UpdatedAt)
+	return playlist, err
+}
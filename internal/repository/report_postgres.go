@@ -0,0 +1,135 @@
+package repository
+
+import (
+	"BestMusicLibrary/internal/model"
+	"database/sql"
+	"github.com/jmoiron/sqlx"
+	"github.com/sirupsen/logrus"
+)
+
+type ReportPostgresRepository struct {
+	db *sqlx.DB
+}
+
+// SongsPerGroup Число песен каждой группы по убыванию. Нулевой limit возвращает все группы
+func (s *ReportPostgresRepository) SongsPerGroup(filter model.SongFilter, limit int) ([]model.GroupSongCount, error) {
+	args := make(queryArgs, 0)
+	condition := songFilterCondition(filter, &args)
+	query := `
+		SELECT MIN(group_name), COUNT(*)
+		FROM songs
+		WHERE ` + condition + `
+		GROUP BY group_search_key
+		ORDER BY COUNT(*) DESC, MIN(group_name)`
+	if limit > 0 {
+		query += ` LIMIT ` + args.add(limit)
+	}
+
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer closeRows(rows)
+
+	counts := make([]model.GroupSongCount, 0)
+	for rows.Next() {
+		var count model.GroupSongCount
+		if err = rows.Scan(&count.Group, &count.SongCount); err != nil {
+			return nil, err
+		}
+		counts = append(counts, count)
+	}
+	return counts, rows.Err()
+}
+
+// SongsPerReleasePeriod Число песен с известной датой релиза по периодам длиной years лет
+func (s *ReportPostgresRepository) SongsPerReleasePeriod(filter model.SongFilter, years int) ([]model.ReleasePeriodCount, error) {
+	args := make(queryArgs, 0)
+	condition := songFilterCondition(filter, &args)
+	period := args.add(years)
+
+	rows, err := s.db.Query(`
+		SELECT EXTRACT(YEAR FROM release_date)::INT / `+period+` * `+period+` AS period, COUNT(*)
+		FROM songs
+		WHERE release_date IS NOT NULL AND `+condition+`
+		GROUP BY period
+		ORDER BY period`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer closeRows(rows)
+
+	counts := make([]model.ReleasePeriodCount, 0)
+	for rows.Next() {
+		var count model.ReleasePeriodCount
+		if err = rows.Scan(&count.Year, &count.SongCount); err != nil {
+			return nil, err
+		}
+		counts = append(counts, count)
+	}
+	return counts, rows.Err()
+}
+
+// lyricsWordChars Символы слова для подсчета слов в SQL: буквы и цифры, апострофы и дефисы вместе с типографскими
+// вариантами, которые lyricstats заменяет перед разбиением текста
+const lyricsWordChars = "[:alnum:]'‘’‚‛′ʼ`‐‑‒–—―-"
+
+// lyricsWordPattern Слово так же, как его выделяет lyricstats: непрерывная последовательность символов слова
+// хотя бы с одной буквой или цифрой. Апострофы и дефисы по краям слова не отделяют его от соседей в подсчете
+const lyricsWordPattern = "[" + lyricsWordChars + "]*[[:alnum:]][" + lyricsWordChars + "]*"
+
+// lyricsCollation Правило сортировки для регулярных выражений по тексту: с ICU классы символов вроде [:alnum:]
+// и \S определяются по Unicode, а не по локали базы, в которой под C кириллица не считается буквами
+const lyricsCollation = `"und-x-icu"`
+
+// LyricsLength Средняя длина текста песен в куплетах, строках, словах и символах. Строки и слова считаются
+// так же, как в статистике текста песни: пустые строки не учитываются, а куплет без строк дает ноль строк
+func (s *ReportPostgresRepository) LyricsLength(filter model.SongFilter) (model.LyricsLengthReport, error) {
+	args := make(queryArgs, 0)
+	condition := songFilterCondition(filter, &args)
+	wordPattern := args.add(lyricsWordPattern)
+
+	var report model.LyricsLengthReport
+	err := s.db.QueryRow(`
+		WITH song_lyrics AS (
+			SELECT a.song_id,
+				COUNT(*) AS verses,
+				SUM(COALESCE(l.line_count, 0)) AS lines,
+				SUM((SELECT COUNT(*) FROM REGEXP_MATCHES(v.text COLLATE `+lyricsCollation+`, `+wordPattern+`, 'g'))) AS words,
+				SUM(LENGTH(v.text)) AS characters
+			FROM (SELECT id FROM songs WHERE `+condition+`) s
+			JOIN verse_arrangement a ON a.song_id = s.id
+			JOIN verses v ON v.id = a.verse_id
+			LEFT JOIN (
+				SELECT verse_id, COUNT(*) AS line_count FROM verse_lines WHERE text COLLATE `+lyricsCollation+` ~ '\S' GROUP BY verse_id
+			) l ON l.verse_id = v.id
+			GROUP BY a.song_id
+		)
+		SELECT COUNT(*), COALESCE(AVG(verses), 0), COALESCE(AVG(lines), 0), COALESCE(AVG(words), 0), COALESCE(AVG(characters), 0)
+		FROM song_lyrics`, args...).
+		Scan(&report.SongCount, &report.AverageVerses, &report.AverageLines, &report.AverageWords, &report.AverageCharacters)
+	return report, err
+}
+
+// EnrichmentCoverage Число песен, которым не хватает даты релиза, ссылки или текста
+func (s *ReportPostgresRepository) EnrichmentCoverage(filter model.SongFilter) (model.EnrichmentCoverage, error) {
+	args := make(queryArgs, 0)
+	condition := songFilterCondition(filter, &args)
+
+	var coverage model.EnrichmentCoverage
+	err := s.db.QueryRow(`
+		SELECT COUNT(*),
+			COUNT(*) FILTER (WHERE release_date IS NULL),
+			COUNT(*) FILTER (WHERE link IS NULL OR link = ''),
+			COUNT(*) FILTER (WHERE NOT EXISTS (SELECT 1 FROM verse_arrangement a WHERE a.song_id = songs.id))
+		FROM songs
+		WHERE `+condition, args...).
+		Scan(&coverage.SongCount, &coverage.MissingReleaseDate, &coverage.MissingLink, &coverage.MissingLyrics)
+	return coverage, err
+}
+
+func closeRows(rows *sql.Rows) {
+	if err := rows.Close(); err != nil {
+		logrus.Error(err)
+	}
+}
@@ -3,20 +3,123 @@ package repository
 import (
 	"BestMusicLibrary/internal/model"
 	"github.com/jmoiron/sqlx"
+	"time"
 )
 
 type Song interface {
-	GetSongs(group, song string, page, limit int) ([]model.Song, error)
-	GetSongVerses(id int64, page, limit int) ([]model.Verse, error)
+	GetSongs(filter model.SongFilter, page, limit int) ([]model.Song, error)
+	GetSong(id int64) (model.Song, error)
+	SongExists(id int64) (bool, error)
+	FindSongBySearchKeys(groupKey, titleKey string) (int64, error)
+	GetSongIds(filter model.SongFilter, afterId int64, limit int) ([]int64, error)
+	CountSongs(filter model.SongFilter) (int, error)
+	GetSongVerses(id int64, page, limit int, compact bool, language string) ([]model.Verse, error)
+	GetSongLanguages(id int64) (string, []string, error)
+	ReplaceTranslation(id int64, language string, verses []model.Verse) error
+	GetVerseLines(id int64, verseNumber, page, limit int) ([]model.VerseLine, error)
+	GetSongStats(id int64, version int) (model.SongStats, error)
+	SaveSongStats(id int64, songUpdatedAt time.Time, version int, stats model.SongStats) error
+	ReplaceSongTerms(id int64, terms map[string]int) error
+	GetSimilarSongs(id int64, threshold float64, limit int) ([]model.SimilarSong, error)
+	GetLyricTimings(id int64) ([]model.TimedLine, error)
+	ReplaceLyricTimings(id int64, lines []model.TimedLine) error
 	DeleteSong(id int64) error
 	UpdateSong(song model.Song) error
+	UpdateSongDerived(song model.Song) error
+	SetExplicitOverride(id int64, explicit *bool) error
 	AddSong(song model.Song) (int64, error)
+	GetSongSources(id int64) ([]model.SongSource, error)
+	GetSongCredits(id int64) ([]model.SongCredit, error)
+	ReplaceSongCredits(id int64, credits []model.SongCredit) error
+}
+
+// Report Сводные отчеты по библиотеке с фильтрами списка песен
+type Report interface {
+	SongsPerGroup(filter model.SongFilter, limit int) ([]model.GroupSongCount, error)
+	SongsPerReleasePeriod(filter model.SongFilter, years int) ([]model.ReleasePeriodCount, error)
+	LyricsLength(filter model.SongFilter) (model.LyricsLengthReport, error)
+	EnrichmentCoverage(filter model.SongFilter) (model.EnrichmentCoverage, error)
+}
+
+type Artist interface {
+	GetArtists(filter model.ArtistFilter, page, limit int) ([]model.Artist, error)
+	GetArtist(id int64) (model.Artist, error)
+	FindArtistBySearchKey(searchKey string) (model.Artist, error)
+	AddArtist(artist model.Artist) (int64, error)
+	UpdateArtist(artist model.Artist) error
+	DeleteArtist(id int64) error
+}
+
+type Album interface {
+	GetAlbums(filter model.AlbumFilter, page, limit int) ([]model.Album, error)
+	GetAlbum(id int64) (model.Album, error)
+	AddAlbum(album model.Album) (int64, error)
+	UpdateAlbum(album model.Album) error
+	DeleteAlbum(id int64) error
+	ReplaceAlbumTracks(albumId int64, tracks []model.AlbumTrack) error
+	AddAlbumTrack(albumId int64, track model.AlbumTrack) error
+	DeleteAlbumTrack(albumId, songId int64) error
+}
+
+type Genre interface {
+	GetGenres() ([]model.Genre, error)
+	GetGenre(id int64) (model.Genre, error)
+	AddGenre(genre model.Genre) (int64, error)
+	UpdateGenre(genre model.Genre) error
+	DeleteGenre(id int64) error
+	GetSongGenres(songId int64) ([]model.Genre, error)
+	AddSongGenre(songId, genreId int64) error
+	DeleteSongGenre(songId, genreId int64) error
+}
+
+type Tag interface {
+	GetSongTags(songId int64) ([]string, error)
+	AddSongTag(songId int64, name, searchKey string) error
+	DeleteSongTag(songId int64, searchKey string) error
+	SuggestTags(prefix string, limit int) ([]string, error)
+	GetTagCounts(page, limit int) ([]model.TagCount, error)
+}
+
+type Playlist interface {
+	GetPlaylists(filter model.PlaylistFilter, page, limit int) ([]model.Playlist, error)
+	GetPlaylist(id int64) (model.Playlist, error)
+	AddPlaylist(playlist model.Playlist) (int64, error)
+	UpdatePlaylist(playlist model.Playlist, caller string) error
+	DeletePlaylist(id int64, caller string) error
+	AddPlaylistEntry(playlistId int64, caller string, songId int64, afterEntryId *int64) (int64, error)
+	MovePlaylistEntry(playlistId int64, caller string, entryId int64, afterEntryId *int64) error
+	DeletePlaylistEntry(playlistId int64, caller string, entryId int64) error
+}
+
+type SmartPlaylist interface {
+	GetSmartPlaylists(filter model.PlaylistFilter, page, limit int) ([]model.SmartPlaylist, error)
+	GetSmartPlaylist(id int64) (model.SmartPlaylist, error)
+	AddSmartPlaylist(playlist model.SmartPlaylist) (int64, error)
+	UpdateSmartPlaylist(playlist model.SmartPlaylist, caller string) error
+	DeleteSmartPlaylist(id int64, caller string) error
+	MatchSongs(filter model.SongFilter, sort model.SongSort, descending bool, limit int) ([]model.Song, error)
 }
 
 type Repository struct {
-	Song Song
+	Song          Song
+	Artist        Artist
+	Album         Album
+	Genre         Genre
+	Tag           Tag
+	Playlist      Playlist
+	SmartPlaylist SmartPlaylist
+	Report        Report
 }
 
 func NewRepository(db *sqlx.DB) *Repository {
-	return &Repository{Song: &SongPostgresRepository{db: db}}
+	return &Repository{
+		Song:          &SongPostgresRepository{db: db},
+		Artist:        &ArtistPostgresRepository{db: db},
+		Album:         &AlbumPostgresRepository{db: db},
+		Genre:         &GenrePostgresRepository{db: db},
+		Tag:           &TagPostgresRepository{db: db},
+		Playlist:      &PlaylistPostgresRepository{db: db},
+		SmartPlaylist: &SmartPlaylistPostgresRepository{db: db},
+		Report:        &ReportPostgresRepository{db: db},
+	}
 }
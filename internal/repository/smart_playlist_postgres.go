@@ -0,0 +1,180 @@
+package repository
+
+import (
+	"BestMusicLibrary/internal/model"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+type SmartPlaylistPostgresRepository struct {
+	db *sqlx.DB
+}
+
+// smartPlaylistGenreConstraint Внешний ключ вычисляемого из правила столбца genre_id: жанр правила
+// должен существовать, пока на него ссылается умный плейлист
+const smartPlaylistGenreConstraint = "smart_playlists_genre_id_fkey"
+
+const smartPlaylistSelect = `SELECT id, name, description, owner, is_public, rule, sort, descending, song_limit,
+	created_at, updated_at FROM smart_playlists`
+
+// GetSmartPlaylists Открытые умные плейлисты и плейлисты пользователя filter.Caller, новые первыми.
+// С владельцем в список попадают только его плейлисты
+func (s *SmartPlaylistPostgresRepository) GetSmartPlaylists(filter model.PlaylistFilter, page, limit int) ([]model.SmartPlaylist, error) {
+	args := make(queryArgs, 0)
+	condition := "(is_public OR owner = " + args.add(filter.Caller) + ")"
+	if filter.Owner != "" {
+		condition += " AND owner = " + args.add(filter.Owner)
+	}
+
+	rows, err := s.db.Query(smartPlaylistSelect+` WHERE `+condition+
+		` ORDER BY created_at DESC, id DESC LIMIT `+args.add(limit)+` OFFSET `+args.add(page*limit), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer closeRows(rows)
+
+	playlists := make([]model.SmartPlaylist, 0)
+	for rows.Next() {
+		playlist, err := scanSmartPlaylist(rows)
+		if err != nil {
+			return nil, err
+		}
+		playlists = append(playlists, playlist)
+	}
+	return playlists, rows.Err()
+}
+
+func (s *SmartPlaylistPostgresRepository) GetSmartPlaylist(id int64) (model.SmartPlaylist, error) {
+	playlist, err := scanSmartPlaylist(s.db.QueryRow(smartPlaylistSelect+` WHERE id = $1`, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return model.SmartPlaylist{}, fmt.Errorf("smart playlist %d: %w", id, model.ErrNotFound)
+	}
+	return playlist, err
+}
+
+func (s *SmartPlaylistPostgresRepository) AddSmartPlaylist(playlist model.SmartPlaylist) (int64, error) {
+	rule, err := json.Marshal(playlist.Rule)
+	if err != nil {
+		return 0, err
+	}
+
+	var playlistId int64
+	err = s.db.QueryRow(`
+		INSERT INTO smart_playlists(name, description, owner, is_public, rule, sort, descending, song_limit)
+		VALUES($1, $2, $3, $4, $5, $6, $7, $8) RETURNING id`,
+		playlist.Name, playlist.Description, playlist.Owner, playlist.Public, rule, playlist.Sort, playlist.Descending,
+		playlist.Limit).Scan(&playlistId)
+	return playlistId, ruleViolationToError(err, playlist.Rule)
+}
+
+// UpdateSmartPlaylist Изменение умного плейлиста владельцем caller. Для остальных пользователей плейлист не найден
+func (s *SmartPlaylistPostgresRepository) UpdateSmartPlaylist(playlist model.SmartPlaylist, caller string) error {
+	rule, err := json.Marshal(playlist.Rule)
+	if err != nil {
+		return err
+	}
+
+	result, err := s.db.Exec(`
+		UPDATE smart_playlists
+		SET name = $1, description = $2, owner = $3, is_public = $4, rule = $5, sort = $6, descending = $7,
+			song_limit = $8, updated_at = NOW()
+		WHERE id = $9 AND owner = $10`,
+		playlist.Name, playlist.Description, playlist.Owner, playlist.Public, rule, playlist.Sort, playlist.Descending,
+		playlist.Limit, playlist.Id, caller)
+	if err != nil {
+		return ruleViolationToError(err, playlist.Rule)
+	}
+	return smartPlaylistAffected(result, playlist.Id)
+}
+
+// DeleteSmartPlaylist Удаление умного плейлиста владельцем caller. Для остальных пользователей плейлист не найден
+func (s *SmartPlaylistPostgresRepository) DeleteSmartPlaylist(id int64, caller string) error {
+	result, err := s.db.Exec(`DELETE FROM smart_playlists WHERE id = $1 AND owner = $2`, id, caller)
+	if err != nil {
+		return err
+	}
+	return smartPlaylistAffected(result, id)
+}
+
+// MatchSongs Первые limit песен по фильтру списка песен в заданном порядке. Песни с одинаковым значением
+// сортировки идут в порядке добавления, песни без даты релиза при сортировке по дате идут последними
+func (s *SmartPlaylistPostgresRepository) MatchSongs(filter model.SongFilter, sort model.SongSort, descending bool, limit int) ([]model.Song, error) {
+	args := make(queryArgs, 0)
+	condition := songFilterCondition(filter, &args)
+	rows, err := s.db.Query(`SELECT `+songColumns+` FROM songs WHERE `+condition+
+		` ORDER BY `+songSortOrder(sort, descending)+` LIMIT `+args.add(limit), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer closeRows(rows)
+
+	songs := make([]model.Song, 0)
+	for rows.Next() {
+		song, err := scanSong(rows)
+		if err != nil {
+			return nil, err
+		}
+		songs = append(songs, song)
+	}
+	return songs, rows.Err()
+}
+
+// songSortOrder Выражение ORDER BY для порядка песен, неизвестный порядок означает порядок добавления
+func songSortOrder(sort model.SongSort, descending bool) string {
+	if sort == model.SongSortRandom {
+		return "RANDOM()"
+	}
+
+	direction := ""
+	if descending {
+		direction = " DESC"
+	}
+	switch sort {
+	case model.SongSortTitle:
+		return "title_search_key" + direction + ", id"
+	case model.SongSortGroup:
+		return "group_search_key" + direction + ", id"
+	case model.SongSortReleaseDate:
+		return "release_date" + direction + " NULLS LAST, id"
+	default:
+		return "id" + direction
+	}
+}
+
+// ruleViolationToError Жанр правила, удаленный после проверки в сервисе, считается ошибкой клиента
+func ruleViolationToError(err error, rule model.SmartRule) error {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == "23503" {
+		return fmt.Errorf("%w: unknown genre %d", model.ErrInvalidInput, rule.GenreId)
+	}
+	return err
+}
+
+func smartPlaylistAffected(result sql.Result, id int64) error {
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return fmt.Errorf("smart playlist %d: %w", id, model.ErrNotFound)
+	}
+	return nil
+}
+
+func scanSmartPlaylist(row rowScanner) (model.SmartPlaylist, error) {
+	var playlist model.SmartPlaylist
+	var rule []byte
+	err := row.Scan(&playlist.Id, &playlist.Name, &playlist.Description, &playlist.Owner, &playlist.Public, &rule,
+		&playlist.Sort, &playlist.Descending, &playlist.Limit, &playlist.CreatedAt, &playlist.UpdatedAt)
+	if err != nil {
+		return model.SmartPlaylist{}, err
+	}
+	if err = json.Unmarshal(rule, &playlist.Rule); err != nil {
+		return model.SmartPlaylist{}, err
+	}
+	return playlist, nil
+}
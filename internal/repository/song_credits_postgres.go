@@ -0,0 +1,184 @@
+package repository
+
+import (
+	"BestMusicLibrary/internal/model"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/lib/pq"
+)
+
+// GetSongCredits Участники песни в порядке перечисления
+func (s *SongPostgresRepository) GetSongCredits(id int64) ([]model.SongCredit, error) {
+	rows, err := s.db.Query(`
+		SELECT sa.artist_id, a.name, sa.role
+		FROM song_artists sa
+		JOIN artists a ON a.id = sa.artist_id
+		WHERE sa.song_id = $1
+		ORDER BY sa.position`, id)
+	if err != nil {
+		return nil, err
+	}
+	defer closeRows(rows)
+
+	credits := make([]model.SongCredit, 0)
+	for rows.Next() {
+		var credit model.SongCredit
+		if err = rows.Scan(&credit.ArtistId, &credit.Artist, &credit.Role); err != nil {
+			return nil, err
+		}
+		credits = append(credits, credit)
+	}
+	return credits, rows.Err()
+}
+
+// ReplaceSongCredits Замена участников песни. Первый участник должен быть основным исполнителем:
+// он записывается в песню вместе с именем и ключом поиска группы
+func (s *SongPostgresRepository) ReplaceSongCredits(id int64, credits []model.SongCredit) error {
+	if len(credits) == 0 || credits[0].Role != model.CreditRolePrimary {
+		return fmt.Errorf("%w: song %d needs a primary artist", model.ErrInvalidInput, id)
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+
+	var songId int64
+	err = tx.QueryRow(`SELECT id FROM songs WHERE id = $1 FOR UPDATE`, id).Scan(&songId)
+	if errors.Is(err, sql.ErrNoRows) {
+		_ = tx.Rollback()
+		return fmt.Errorf("song %d: %w", id, model.ErrNotFound)
+	}
+	if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	if _, err = tx.Exec(`DELETE FROM song_artists WHERE song_id = $1`, id); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	if err = insertSongCredits(tx, id, credits); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	_, err = tx.Exec(`
+		UPDATE songs s
+		SET artist_id = a.id, group_name = a.name, group_search_key = a.search_key, updated_at = NOW()
+		FROM artists a
+		WHERE s.id = $1 AND a.id = $2 AND s.artist_id IS DISTINCT FROM a.id`,
+		id, credits[0].ArtistId)
+	if err != nil {
+		_ = tx.Rollback()
+		return uniqueViolationToConflict(err)
+	}
+
+	return tx.Commit()
+}
+
+// derivedCreditRoles Роли участников, которые разбираются из названий группы и песни при каждой их записи
+var derivedCreditRoles = []string{string(model.CreditRolePrimary), string(model.CreditRoleFeatured)}
+
+// replaceDerivedCredits Запись участников в транзакции записи песни: исполнитель песни и разобранные из названий
+// участники заменяют прежних основных и приглашенных, участники в остальных ролях сохраняются после них.
+// Неизвестные исполнители создаются в той же транзакции
+func replaceDerivedCredits(tx *sql.Tx, song model.Song) error {
+	credits := make([]model.SongCredit, 0, len(song.Credits)+1)
+	credits = append(credits, model.SongCredit{ArtistId: song.ArtistId, Role: model.CreditRolePrimary})
+	for _, credit := range song.Credits {
+		if credit.ArtistId == 0 {
+			artistId, _, err := addArtistIfMissing(tx, credit.Artist, credit.SearchKey)
+			if err != nil {
+				return err
+			}
+			credit.ArtistId = artistId
+		}
+		credits = append(credits, credit)
+	}
+
+	kept, err := getUnderivedCredits(tx, song.Id)
+	if err != nil {
+		return err
+	}
+	credits = append(credits, kept...)
+
+	if _, err = tx.Exec(`DELETE FROM song_artists WHERE song_id = $1`, song.Id); err != nil {
+		return err
+	}
+	return insertSongCredits(tx, song.Id, credits)
+}
+
+// getUnderivedCredits Участники песни в ролях, которые не разбираются из названий, в порядке перечисления
+func getUnderivedCredits(tx *sql.Tx, songId int64) ([]model.SongCredit, error) {
+	rows, err := tx.Query(`
+		SELECT artist_id, role
+		FROM song_artists
+		WHERE song_id = $1 AND role <> ALL($2)
+		ORDER BY position`, songId, pq.Array(derivedCreditRoles))
+	if err != nil {
+		return nil, err
+	}
+	defer closeRows(rows)
+
+	credits := make([]model.SongCredit, 0)
+	for rows.Next() {
+		var credit model.SongCredit
+		if err = rows.Scan(&credit.ArtistId, &credit.Role); err != nil {
+			return nil, err
+		}
+		credits = append(credits, credit)
+	}
+	return credits, rows.Err()
+}
+
+// insertSongCredits Запись участников в порядке перечисления, повтор исполнителя в той же роли пропускается
+func insertSongCredits(tx *sql.Tx, songId int64, credits []model.SongCredit) error {
+	artistIds := make([]int64, 0, len(credits))
+	roles := make([]string, 0, len(credits))
+	for _, credit := range credits {
+		artistIds = append(artistIds, credit.ArtistId)
+		roles = append(roles, string(credit.Role))
+	}
+	_, err := tx.Exec(`
+		INSERT INTO song_artists(song_id, artist_id, role, position)
+		SELECT $1, c.artist_id, c.role, c.position
+		FROM UNNEST($2::INT[], $3::TEXT[]) WITH ORDINALITY AS c(artist_id, role, position)
+		ON CONFLICT (song_id, artist_id, role) DO NOTHING`,
+		songId, pq.Array(artistIds), pq.Array(roles))
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == "23503" {
+		return fmt.Errorf("%w: unknown artist in credits", model.ErrInvalidInput)
+	}
+	return err
+}
+
+// addSongArtistIfMissing Создание исполнителя неизвестной группы песни. Если исполнитель с тем же ключом уже есть,
+// песня получает его каноническое имя
+func addSongArtistIfMissing(tx *sql.Tx, song *model.Song) error {
+	if song.ArtistId != 0 {
+		return nil
+	}
+	var err error
+	song.ArtistId, song.Group, err = addArtistIfMissing(tx, song.Group, song.GroupSearchKey)
+	return err
+}
+
+// addArtistIfMissing Идентификатор и имя исполнителя с ключом поиска searchKey. Неизвестный исполнитель создается
+// в транзакции записи песни, поэтому при ее откате не остается. Если того же исполнителя одновременно создает
+// другая запись, используется созданный ею
+func addArtistIfMissing(tx *sql.Tx, name, searchKey string) (int64, string, error) {
+	var artistId int64
+	err := tx.QueryRow(`
+		INSERT INTO artists(name, search_key) VALUES($1, $2)
+		ON CONFLICT (search_key) DO NOTHING
+		RETURNING id`, name, searchKey).Scan(&artistId)
+	if !errors.Is(err, sql.ErrNoRows) {
+		return artistId, name, err
+	}
+
+	err = tx.QueryRow(`SELECT id, name FROM artists WHERE search_key = $1`, searchKey).Scan(&artistId, &name)
+	return artistId, name, err
+}
@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"BestMusicLibrary/internal/model"
+	"strconv"
+	"strings"
+)
+
+type queryArgs []any
+
+func (a *queryArgs) add(value any) string {
+	*a = append(*a, value)
+	return "$" + strconv.Itoa(len(*a))
+}
+
+// songFilterCondition Условие WHERE по таблице songs для фильтра.
+// Группа сравнивается с ключом поиска группы песни и с именами и другими написаниями всех участников песни,
+// название с ключом поиска названия,
+// условия объединяются через OR, как в списке песен; пустой фильтр подходит под все песни.
+// Список песен с пустым фильтром сервис не запрашивает
+func songFilterCondition(filter model.SongFilter, args *queryArgs) string {
+	conditions := make([]string, 0)
+
+	textConditions := make([]string, 0, 2)
+	if filter.Group != "" {
+		group := args.add(filter.Group)
+		textConditions = append(textConditions, "group_search_key LIKE '%' || "+group+" || '%'",
+			`id IN (
+				SELECT sa.song_id FROM song_artists sa JOIN artists a ON a.id = sa.artist_id
+				WHERE a.search_key LIKE '%' || `+group+` || '%'
+					OR sa.artist_id IN (SELECT artist_id FROM artist_aliases WHERE search_key LIKE '%' || `+group+` || '%'))`)
+	}
+	if filter.Name != "" {
+		textConditions = append(textConditions, "title_search_key LIKE '%' || "+args.add(filter.Name)+" || '%'")
+	}
+	if len(textConditions) > 0 {
+		conditions = append(conditions, "("+strings.Join(textConditions, " OR ")+")")
+	}
+
+	if filter.Language != "" {
+		language := args.add(filter.Language)
+		conditions = append(conditions, "(language = "+language+" OR language LIKE "+language+" || '-%')")
+	}
+
+	if filter.Explicit != nil {
+		// Песни, которые еще не проверялись, не подходят ни под один вариант
+		conditions = append(conditions, "COALESCE(explicit_override, explicit) = "+args.add(*filter.Explicit))
+	}
+
+	if filter.AlbumId != 0 {
+		conditions = append(conditions, "id IN (SELECT song_id FROM album_tracks WHERE album_id = "+args.add(filter.AlbumId)+")")
+	}
+
+	if filter.ArtistId != 0 {
+		conditions = append(conditions, "id IN (SELECT song_id FROM song_artists WHERE artist_id = "+args.add(filter.ArtistId)+")")
+	}
+
+	if filter.GenreId != 0 {
+		conditions = append(conditions, `id IN (
+			WITH RECURSIVE subtree AS (
+				SELECT id FROM genres WHERE id = `+args.add(filter.GenreId)+`
+				UNION
+				SELECT g.id FROM genres g JOIN subtree t ON g.parent_id = t.id
+			)
+			SELECT song_id FROM song_genres WHERE genre_id IN (SELECT id FROM subtree))`)
+	}
+
+	if filter.Tag != "" {
+		conditions = append(conditions, `id IN (
+			SELECT st.song_id FROM song_tags st JOIN tags t ON t.id = st.tag_id WHERE t.search_key = `+args.add(filter.Tag)+`)`)
+	}
+
+	if filter.ReleasedFrom != nil {
+		conditions = append(conditions, "release_date >= "+args.add(*filter.ReleasedFrom)+"::DATE")
+	}
+	if filter.ReleasedTo != nil {
+		conditions = append(conditions, "release_date <= "+args.add(*filter.ReleasedTo)+"::DATE")
+	}
+
+	if len(conditions) == 0 {
+		return "TRUE"
+	}
+	return strings.Join(conditions, " AND ")
+}
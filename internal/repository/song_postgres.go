@@ -3,40 +3,115 @@ package repository
 import (
 	"BestMusicLibrary/internal/model"
 	"database/sql"
+	"errors"
+	"fmt"
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 	"github.com/sirupsen/logrus"
+	"strings"
 )
 
 type SongPostgresRepository struct {
 	db *sqlx.DB
 }
 
+const (
+	songColumns  = `id, artist_id, group_name, song_title, group_search_key, title_search_key, release_date, release_date_precision, language, explicit, explicit_override, link, created_at, updated_at`
+	verseColumns = `verse_number, text, section_type, section_label`
+)
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
 func (*SongPostgresRepository) NewSongRepository() *SongPostgresRepository {
 	return &SongPostgresRepository{}
 }
 
-func (s *SongPostgresRepository) GetSongs(group, songName string, page, limit int) ([]model.Song, error) {
+func (s *SongPostgresRepository) GetSongs(filter model.SongFilter, page, limit int) ([]model.Song, error) {
 	offset := page * limit
-	rows, err := s.db.Query(`SELECT * FROM songs WHERE (LENGTH($1) > 0 AND group_name ILIKE '%' || $1 || '%') OR (LENGTH($2) > 0 AND song_title ILIKE '%' || $2 || '%') ORDER BY id LIMIT $3 OFFSET $4`, group, songName, limit, offset)
+	args := make(queryArgs, 0)
+	condition := songFilterCondition(filter, &args)
+	rows, err := s.db.Query(`SELECT `+songColumns+` FROM songs WHERE `+condition+
+		` ORDER BY id LIMIT `+args.add(limit)+` OFFSET `+args.add(offset), args...)
 	if err != nil {
 		return nil, err
 	}
+	defer func(rows *sql.Rows) {
+		err := rows.Close()
+		if err != nil {
+			logrus.Error(err)
+		}
+	}(rows)
 
 	songs := make([]model.Song, 0)
 	for rows.Next() {
-		var song model.Song
-		err = rows.Scan(&song.Id, &song.Group, &song.Name, &song.ReleaseDate, &song.Link, &song.CreatedAt, &song.UpdatedAt)
+		song, err := scanSong(rows)
 		if err != nil {
 			return nil, err
 		}
 		songs = append(songs, song)
 	}
-	return songs, nil
+	return songs, rows.Err()
 }
 
-func (s *SongPostgresRepository) GetSongVerses(id int64, page, limit int) ([]model.Verse, error) {
-	offset := page * limit
-	rows, err := s.db.Query(`SELECT verse_number, text FROM verses WHERE song_id = $1 ORDER BY ID LIMIT $2 OFFSET $3`, id, limit, offset)
+func (s *SongPostgresRepository) GetSong(id int64) (model.Song, error) {
+	song, err := scanSong(s.db.QueryRow(`SELECT `+songColumns+` FROM songs WHERE id = $1`, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return model.Song{}, fmt.Errorf("song %d: %w", id, model.ErrNotFound)
+	}
+	if err != nil {
+		return model.Song{}, err
+	}
+
+	rows, err := s.db.Query(`SELECT `+verseColumns+` FROM verses WHERE song_id = $1 ORDER BY verse_number`, id)
+	if err != nil {
+		return model.Song{}, err
+	}
+
+	song.Verses, err = scanVerses(rows)
+	if err != nil {
+		return model.Song{}, err
+	}
+
+	song.Arrangement = make([]int, 0, len(song.Verses))
+	err = s.db.Select(&song.Arrangement, `
+		SELECT v.verse_number
+		FROM verse_arrangement a
+		JOIN verses v ON v.id = a.verse_id
+		WHERE a.song_id = $1
+		ORDER BY a.position`, id)
+	if err != nil {
+		return model.Song{}, err
+	}
+	return song, nil
+}
+
+func (s *SongPostgresRepository) SongExists(id int64) (bool, error) {
+	var exists bool
+	err := s.db.QueryRow(`SELECT EXISTS(SELECT 1 FROM songs WHERE id = $1)`, id).Scan(&exists)
+	return exists, err
+}
+
+// FindSongBySearchKeys Идентификатор песни с теми же ключами поиска группы и названия, ключи уникальны
+func (s *SongPostgresRepository) FindSongBySearchKeys(groupKey, titleKey string) (int64, error) {
+	var songId int64
+	err := s.db.QueryRow(`SELECT id FROM songs WHERE group_search_key = $1 AND title_search_key = $2`,
+		groupKey, titleKey).Scan(&songId)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, fmt.Errorf("song %q by %q: %w", titleKey, groupKey, model.ErrNotFound)
+	}
+	return songId, err
+}
+
+func (s *SongPostgresRepository) GetSongIds(filter model.SongFilter, afterId int64, limit int) ([]int64, error) {
+	args := make(queryArgs, 0)
+	condition := songFilterCondition(filter, &args)
+	rows, err := s.db.Query(`SELECT id FROM songs WHERE `+condition+` AND id > `+args.add(afterId)+
+		` ORDER BY id LIMIT `+args.add(limit), args...)
+	if err != nil {
+		return nil, err
+	}
 	defer func(rows *sql.Rows) {
 		err := rows.Close()
 		if err != nil {
@@ -44,30 +119,163 @@ func (s *SongPostgresRepository) GetSongVerses(id int64, page, limit int) ([]mod
 		}
 	}(rows)
 
+	ids := make([]int64, 0, limit)
+	for rows.Next() {
+		var id int64
+		if err = rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+func (s *SongPostgresRepository) CountSongs(filter model.SongFilter) (int, error) {
+	args := make(queryArgs, 0)
+	condition := songFilterCondition(filter, &args)
+	var count int
+	err := s.db.QueryRow(`SELECT COUNT(*) FROM songs WHERE `+condition, args...).Scan(&count)
+	return count, err
+}
+
+// GetSongVerses Куплеты песни на указанном языке. Куплеты без перевода и пустой язык дают текст оригинала
+func (s *SongPostgresRepository) GetSongVerses(id int64, page, limit int, compact bool, language string) ([]model.Verse, error) {
+	offset := page * limit
+	if compact {
+		return s.getCompactSongVerses(id, limit, offset, language)
+	}
+
+	rows, err := s.db.Query(`
+		SELECT a.position, COALESCE(t.text, v.text), v.section_type, v.section_label
+		FROM verse_arrangement a
+		JOIN verses v ON v.id = a.verse_id
+		LEFT JOIN verse_translations t ON t.song_id = v.song_id AND t.verse_number = v.verse_number AND t.language = $4
+		WHERE a.song_id = $1
+		ORDER BY a.position LIMIT $2 OFFSET $3`, id, limit, offset, language)
 	if err != nil {
 		return nil, err
 	}
 
+	return scanVerses(rows)
+}
+
+// getCompactSongVerses Уникальные куплеты песни вместе с позициями, на которых они исполняются
+func (s *SongPostgresRepository) getCompactSongVerses(id int64, limit, offset int, language string) ([]model.Verse, error) {
+	rows, err := s.db.Query(`
+		SELECT v.verse_number, COALESCE(t.text, v.text), v.section_type, v.section_label,
+		ARRAY(SELECT a.position FROM verse_arrangement a WHERE a.verse_id = v.id ORDER BY a.position)
+		FROM verses v
+		LEFT JOIN verse_translations t ON t.song_id = v.song_id AND t.verse_number = v.verse_number AND t.language = $4
+		WHERE v.song_id = $1
+		ORDER BY v.verse_number LIMIT $2 OFFSET $3`, id, limit, offset, language)
+	if err != nil {
+		return nil, err
+	}
+	defer func(rows *sql.Rows) {
+		err := rows.Close()
+		if err != nil {
+			logrus.Error(err)
+		}
+	}(rows)
+
 	verses := make([]model.Verse, 0)
 	for rows.Next() {
 		var verse model.Verse
-		err = rows.Scan(&verse.VerseNumber, &verse.Text)
+		var positions []int64
+		err = rows.Scan(&verse.VerseNumber, &verse.Text, &verse.SectionType, &verse.SectionLabel, pq.Array(&positions))
 		if err != nil {
 			return nil, err
 		}
+		verse.Positions = make([]int, 0, len(positions))
+		for _, position := range positions {
+			verse.Positions = append(verse.Positions, int(position))
+		}
 		verses = append(verses, verse)
 	}
+	return verses, rows.Err()
+}
 
-	if rows.Err() != nil {
+func (s *SongPostgresRepository) GetVerseLines(id int64, verseNumber, page, limit int) ([]model.VerseLine, error) {
+	offset := page * limit
+	rows, err := s.db.Query(`
+		SELECT a.position, l.line_number, l.text
+		FROM verse_arrangement a
+		JOIN verse_lines l ON l.verse_id = a.verse_id
+		WHERE a.song_id = $1 AND a.position = $2
+		ORDER BY l.line_number LIMIT $3 OFFSET $4`, id, verseNumber, limit, offset)
+	if err != nil {
 		return nil, err
 	}
+	defer func(rows *sql.Rows) {
+		err := rows.Close()
+		if err != nil {
+			logrus.Error(err)
+		}
+	}(rows)
 
-	return verses, nil
+	lines := make([]model.VerseLine, 0)
+	for rows.Next() {
+		var line model.VerseLine
+		if err = rows.Scan(&line.VerseNumber, &line.LineNumber, &line.Text); err != nil {
+			return nil, err
+		}
+		lines = append(lines, line)
+	}
+	return lines, rows.Err()
 }
 
+// DeleteSong Удаление песни. Термы удаляются явно, чтобы уменьшить число песен с каждым из них
 func (s *SongPostgresRepository) DeleteSong(id int64) error {
-	_, err := s.db.Exec(`DELETE FROM songs WHERE id = $1`, id)
-	return err
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+
+	if err = deleteSongTerms(tx, id); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	if _, err = tx.Exec(`DELETE FROM songs WHERE id = $1`, id); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// SetExplicitOverride Сохранение решения редактора об откровенности песни, nil возвращает автоматическое определение
+func (s *SongPostgresRepository) SetExplicitOverride(id int64, explicit *bool) error {
+	result, err := s.db.Exec(`UPDATE songs SET explicit_override = $1, updated_at = NOW() WHERE id = $2`, explicit, id)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return fmt.Errorf("song %d: %w", id, model.ErrNotFound)
+	}
+	return nil
+}
+
+// UpdateSongDerived Запись ключей поиска, языка и результата определения откровенной песни.
+// Поля вычисляются из сохраненных данных, поэтому время изменения песни не меняется
+func (s *SongPostgresRepository) UpdateSongDerived(song model.Song) error {
+	result, err := s.db.Exec(`UPDATE songs SET group_search_key = $1, title_search_key = $2, language = $3, explicit = $4 WHERE id = $5`,
+		song.GroupSearchKey, song.NameSearchKey, song.Language, song.ExplicitDetected, song.Id)
+	if err != nil {
+		return uniqueViolationToConflict(err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return fmt.Errorf("song %d: %w", song.Id, model.ErrNotFound)
+	}
+	return nil
 }
 
 func (s *SongPostgresRepository) UpdateSong(song model.Song) error {
@@ -76,24 +284,56 @@ func (s *SongPostgresRepository) UpdateSong(song model.Song) error {
 		return err
 	}
 
-	_, err = s.db.Exec(`UPDATE songs SET group_name = $1, song_title = $2, release_date = $3, link = $4, created_at = $5, updated_at = NOW() WHERE id = $6`,
-		song.Group, song.Name, song.ReleaseDate, song.Link, song.CreatedAt, song.Id)
+	if err = addSongArtistIfMissing(tx, &song); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	releaseDate, precision := releaseDateValues(song)
+	_, err = tx.Exec(`UPDATE songs SET artist_id = $1, group_name = $2, song_title = $3, group_search_key = $4, title_search_key = $5, release_date = $6, release_date_precision = $7, language = $8, explicit = $9, link = $10, created_at = $11, updated_at = NOW() WHERE id = $12`,
+		song.ArtistId, song.Group, song.Name, song.GroupSearchKey, song.NameSearchKey, releaseDate, precision, song.Language, song.ExplicitDetected, song.Link, song.CreatedAt, song.Id)
 
 	if err != nil {
 		_ = tx.Rollback()
-		return err
+		return uniqueViolationToConflict(err)
 	}
 
-	_, err = s.db.Exec(`DELETE FROM verses WHERE song_id = $1`, song.Id)
+	_, err = tx.Exec(`DELETE FROM verses WHERE song_id = $1`, song.Id)
 
 	if err != nil {
 		_ = tx.Rollback()
 		return err
 	}
 
-	for index, verse := range song.Verses {
-		_, err = s.db.Exec(`INSERT INTO verses(song_id, verse_number, text) VALUES($1, $2, $3)`, song.Id, index, verse.Text)
-		if err != nil {
+	if err = insertVerses(tx, song.Id, song.Verses, song.Arrangement); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	if err = deleteStaleLyricTimings(tx, song.Id); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	if err = deleteMisalignedTranslations(tx, song.Id); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	if err = insertSongSources(tx, song.Id, song.Sources); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	if song.Credits != nil {
+		if err = replaceDerivedCredits(tx, song); err != nil {
+			_ = tx.Rollback()
+			return err
+		}
+	}
+
+	if song.Terms != nil {
+		if err = replaceSongTerms(tx, song.Id, song.Terms); err != nil {
 			_ = tx.Rollback()
 			return err
 		}
@@ -109,33 +349,180 @@ func (s *SongPostgresRepository) AddSong(song model.Song) (int64, error) {
 		return 0, err
 	}
 
-	err = s.db.QueryRow(`
+	if err = addSongArtistIfMissing(tx, &song); err != nil {
+		_ = tx.Rollback()
+		return 0, err
+	}
+
+	releaseDate, precision := releaseDateValues(song)
+	err = tx.QueryRow(`
 		INSERT
 		INTO
-		songs(group_name, song_title, release_date, link)
-		VALUES($1, $2, $3, $4) RETURNING
+		songs(artist_id, group_name, song_title, group_search_key, title_search_key, release_date, release_date_precision, language, explicit, link)
+		VALUES($1, $2, $3, $4, $5, $6, $7, $8, $9, $10) RETURNING
 		id
 		`,
-		song.Group, song.Name, song.ReleaseDate, song.Link).Scan(&songId)
+		song.ArtistId, song.Group, song.Name, song.GroupSearchKey, song.NameSearchKey, releaseDate, precision, song.Language, song.ExplicitDetected, song.Link).Scan(&songId)
 
 	if err != nil {
 		_ = tx.Rollback()
-		return 0, err
+		return 0, uniqueViolationToConflict(err)
+	}
+
+	if err = insertVerses(tx, songId, song.Verses, song.Arrangement); err != nil {
+		_ = tx.Rollback()
+		return songId, err
+	}
+
+	if err = insertSongSources(tx, songId, song.Sources); err != nil {
+		_ = tx.Rollback()
+		return songId, err
+	}
+
+	song.Id = songId
+	if err = replaceDerivedCredits(tx, song); err != nil {
+		_ = tx.Rollback()
+		return songId, err
+	}
+
+	if err = replaceSongTerms(tx, songId, song.Terms); err != nil {
+		_ = tx.Rollback()
+		return songId, err
+	}
+
+	return songId, tx.Commit()
+}
+
+func (s *SongPostgresRepository) GetSongSources(id int64) ([]model.SongSource, error) {
+	rows, err := s.db.Query(`SELECT field, provider, created_at FROM song_sources WHERE song_id = $1 ORDER BY id`, id)
+	if err != nil {
+		return nil, err
+	}
+	defer func(rows *sql.Rows) {
+		err := rows.Close()
+		if err != nil {
+			logrus.Error(err)
+		}
+	}(rows)
+
+	sources := make([]model.SongSource, 0)
+	for rows.Next() {
+		var source model.SongSource
+		if err = rows.Scan(&source.Field, &source.Provider, &source.CreatedAt); err != nil {
+			return nil, err
+		}
+		sources = append(sources, source)
 	}
 
-	for _, verse := range song.Verses {
-		_, err = s.db.Exec(`
+	return sources, rows.Err()
+}
+
+// insertVerses Сохранение уникальных куплетов, их строк и порядка исполнения. Без порядка каждый куплет исполняется один раз
+func insertVerses(tx *sql.Tx, songId int64, verses []model.Verse, arrangement []int) error {
+	verseIds := make([]int64, 0, len(verses))
+	for index, verse := range verses {
+		var verseId int64
+		err := tx.QueryRow(`
 		INSERT
 		INTO
-		verses(song_id, verse_number, text)
-		VALUES($1, $2, $3)`,
-			songId, verse.VerseNumber, verse.Text)
+		verses(song_id, verse_number, text, section_type, section_label)
+		VALUES($1, $2, $3, $4, $5) RETURNING
+		id`,
+			songId, index, verse.Text, verse.SectionType, verse.SectionLabel).Scan(&verseId)
+		if err != nil {
+			return err
+		}
+		verseIds = append(verseIds, verseId)
 
+		for lineNumber, line := range strings.Split(verse.Text, "\n") {
+			_, err = tx.Exec(`INSERT INTO verse_lines(verse_id, line_number, text) VALUES($1, $2, $3)`, verseId, lineNumber, line)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	if arrangement == nil {
+		arrangement = make([]int, len(verses))
+		for index := range arrangement {
+			arrangement[index] = index
+		}
+	}
+
+	for position, number := range arrangement {
+		if number < 0 || number >= len(verseIds) {
+			return fmt.Errorf("arrangement position %d refers to missing verse %d", position, number)
+		}
+		_, err := tx.Exec(`INSERT INTO verse_arrangement(song_id, position, verse_id) VALUES($1, $2, $3)`,
+			songId, position, verseIds[number])
 		if err != nil {
-			_ = tx.Rollback()
-			return songId, err
+			return err
 		}
 	}
+	return nil
+}
 
-	return songId, tx.Commit()
+func scanVerses(rows *sql.Rows) ([]model.Verse, error) {
+	defer func(rows *sql.Rows) {
+		err := rows.Close()
+		if err != nil {
+			logrus.Error(err)
+		}
+	}(rows)
+
+	verses := make([]model.Verse, 0)
+	for rows.Next() {
+		var verse model.Verse
+		if err := rows.Scan(&verse.VerseNumber, &verse.Text, &verse.SectionType, &verse.SectionLabel); err != nil {
+			return nil, err
+		}
+		verses = append(verses, verse)
+	}
+	return verses, rows.Err()
+}
+
+func insertSongSources(tx *sql.Tx, songId int64, sources []model.SongSource) error {
+	for _, source := range sources {
+		_, err := tx.Exec(`INSERT INTO song_sources(song_id, field, provider) VALUES($1, $2, $3)`, songId, source.Field, source.Provider)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func scanSong(row rowScanner) (model.Song, error) {
+	var song model.Song
+	var releaseDate sql.NullTime
+	var precision, link sql.NullString
+	var explicitDetected, explicitOverride sql.NullBool
+	err := row.Scan(&song.Id, &song.ArtistId, &song.Group, &song.Name, &song.GroupSearchKey, &song.NameSearchKey, &releaseDate, &precision, &song.Language, &explicitDetected, &explicitOverride, &link, &song.CreatedAt, &song.UpdatedAt)
+	if err != nil {
+		return model.Song{}, err
+	}
+
+	if releaseDate.Valid {
+		song.ReleaseDate = releaseDate.Time
+		song.ReleaseDatePrecision = model.DatePrecision(precision.String)
+	}
+	song.Link = link.String
+	if explicitDetected.Valid {
+		song.ExplicitDetected = &explicitDetected.Bool
+	}
+	if explicitOverride.Valid {
+		song.ExplicitOverride = &explicitOverride.Bool
+	}
+
+	return song, nil
+}
+
+// releaseDateValues Неизвестная дата релиза хранится как NULL, дата без точности считается полной
+func releaseDateValues(song model.Song) (releaseDate, precision any) {
+	if song.ReleaseDate.IsZero() {
+		return nil, nil
+	}
+	if song.ReleaseDatePrecision == "" {
+		return song.ReleaseDate, string(model.DatePrecisionDay)
+	}
+	return song.ReleaseDate, string(song.ReleaseDatePrecision)
 }
@@ -0,0 +1,50 @@
+package repository
+
+import (
+	"BestMusicLibrary/internal/model"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// GetSongStats Сохраненная статистика текста песни. Статистика, посчитанная до последнего изменения песни
+// или другой версией алгоритма, считается отсутствующей
+func (s *SongPostgresRepository) GetSongStats(id int64, version int) (model.SongStats, error) {
+	var raw []byte
+	err := s.db.QueryRow(`
+		SELECT st.stats
+		FROM song_stats st
+		JOIN songs s ON s.id = st.song_id AND s.updated_at = st.song_updated_at
+		WHERE st.song_id = $1 AND st.stats_version = $2`, id, version).Scan(&raw)
+	if errors.Is(err, sql.ErrNoRows) {
+		return model.SongStats{}, fmt.Errorf("stats of song %d: %w", id, model.ErrNotFound)
+	}
+	if err != nil {
+		return model.SongStats{}, err
+	}
+
+	var stats model.SongStats
+	if err = json.Unmarshal(raw, &stats); err != nil {
+		return model.SongStats{}, err
+	}
+	return stats, nil
+}
+
+// SaveSongStats Сохранение статистики, посчитанной алгоритмом версии version по версии песни с временем
+// изменения songUpdatedAt. Если песня успела измениться, статистика не сохраняется
+func (s *SongPostgresRepository) SaveSongStats(id int64, songUpdatedAt time.Time, version int, stats model.SongStats) error {
+	raw, err := json.Marshal(stats)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.db.Exec(`
+		INSERT INTO song_stats(song_id, song_updated_at, stats_version, stats)
+		SELECT id, updated_at, $2, $3 FROM songs WHERE id = $1 AND updated_at = $4
+		ON CONFLICT (song_id) DO UPDATE
+		SET song_updated_at = EXCLUDED.song_updated_at, stats_version = EXCLUDED.stats_version, stats = EXCLUDED.stats`,
+		id, version, raw, songUpdatedAt)
+	return err
+}
@@ -0,0 +1,164 @@
+package repository
+
+import (
+	"BestMusicLibrary/internal/model"
+	"database/sql"
+	"github.com/lib/pq"
+)
+
+// ReplaceSongTerms Замена термов песни для поиска похожих песен
+func (s *SongPostgresRepository) ReplaceSongTerms(id int64, terms map[string]int) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+
+	if err = replaceSongTerms(tx, id, terms); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// replaceSongTerms Замена термов песни в транзакции ее записи. Вместе с термами обновляется число песен с каждым
+// термом, поэтому поиск похожих песен не пересчитывает его по всей таблице
+func replaceSongTerms(tx *sql.Tx, songId int64, terms map[string]int) error {
+	words := make([]string, 0, len(terms))
+	counts := make([]int64, 0, len(terms))
+	for term, count := range terms {
+		words = append(words, term)
+		counts = append(counts, int64(count))
+	}
+
+	if err := lockTermFrequencies(tx, songId, words); err != nil {
+		return err
+	}
+	if err := deleteSongTerms(tx, songId); err != nil {
+		return err
+	}
+	if len(terms) == 0 {
+		return nil
+	}
+
+	_, err := tx.Exec(`INSERT INTO song_terms(song_id, term, count) SELECT $1, term, count FROM UNNEST($2::TEXT[], $3::INT[]) AS t(term, count)`,
+		songId, pq.Array(words), pq.Array(counts))
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`
+		INSERT INTO term_frequencies(term, df)
+		SELECT term, 1 FROM UNNEST($1::TEXT[]) AS t(term) ORDER BY term
+		ON CONFLICT (term) DO UPDATE SET df = term_frequencies.df + 1`, pq.Array(words))
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`INSERT INTO song_term_documents(song_id) VALUES($1)`, songId)
+	return err
+}
+
+// lockTermFrequencies Блокировка частот прежних термов песни и термов terms в порядке термов, чтобы транзакции,
+// одновременно меняющие песни с общими термами, ждали друг друга, а не взаимно блокировались
+func lockTermFrequencies(tx *sql.Tx, songId int64, terms []string) error {
+	_, err := tx.Exec(`
+		SELECT term FROM term_frequencies
+		WHERE term IN (SELECT term FROM song_terms WHERE song_id = $1) OR term = ANY($2::TEXT[])
+		ORDER BY term
+		FOR UPDATE`, songId, pq.Array(terms))
+	return err
+}
+
+// deleteSongTerms Удаление термов песни с уменьшением числа песен с каждым из них
+func deleteSongTerms(tx *sql.Tx, songId int64) error {
+	if err := lockTermFrequencies(tx, songId, nil); err != nil {
+		return err
+	}
+
+	_, err := tx.Exec(`
+		DELETE FROM term_frequencies f
+		USING song_terms st
+		WHERE st.song_id = $1 AND st.term = f.term AND f.df = 1`, songId)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`
+		UPDATE term_frequencies f
+		SET df = f.df - 1
+		FROM song_terms st
+		WHERE st.song_id = $1 AND st.term = f.term`, songId)
+	if err != nil {
+		return err
+	}
+
+	if _, err = tx.Exec(`DELETE FROM song_terms WHERE song_id = $1`, songId); err != nil {
+		return err
+	}
+	_, err = tx.Exec(`DELETE FROM song_term_documents WHERE song_id = $1`, songId)
+	return err
+}
+
+// GetSimilarSongs Песни, похожие на песню id по косинусной близости векторов TF-IDF их термов.
+// Вес терма равен (1 + ln tf) * (1 + ln((N + 1) / (df + 1))), где N число песен с текстом, df число песен с термом.
+// Числа песен хранятся и обновляются при записи. Веса и нормы векторов песни и песен с общими термами
+// считаются в запросе по одним и тем же частотам, поэтому близость не превышает 1
+func (s *SongPostgresRepository) GetSimilarSongs(id int64, threshold float64, limit int) ([]model.SimilarSong, error) {
+	rows, err := s.db.Query(`
+		WITH documents AS (
+			SELECT COUNT(*) AS total FROM song_term_documents
+		), candidates AS (
+			SELECT DISTINCT st.song_id
+			FROM song_terms t
+			JOIN song_terms st ON st.term = t.term
+			WHERE t.song_id = $1
+		), weights AS (
+			SELECT st.song_id, st.term, (1 + LN(st.count)) * (1 + LN((d.total + 1.0) / (f.df + 1.0))) AS weight
+			FROM candidates c
+			JOIN song_terms st ON st.song_id = c.song_id
+			JOIN term_frequencies f ON f.term = st.term
+			CROSS JOIN documents d
+		), norms AS (
+			SELECT song_id, SQRT(SUM(weight * weight)) AS norm FROM weights GROUP BY song_id
+		), similarities AS (
+			SELECT w.song_id, SUM(w.weight * t.weight) / (n.norm * tn.norm) AS similarity
+			FROM weights t
+			JOIN weights w ON w.term = t.term AND w.song_id <> $1
+			JOIN norms n ON n.song_id = w.song_id
+			JOIN norms tn ON tn.song_id = $1
+			WHERE t.song_id = $1
+			GROUP BY w.song_id, n.norm, tn.norm
+		)
+		SELECT `+songColumns+`, similarity
+		FROM songs
+		JOIN similarities ON similarities.song_id = songs.id
+		WHERE similarity >= $2
+		ORDER BY similarity DESC, id
+		LIMIT $3`, id, threshold, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer closeRows(rows)
+
+	similar := make([]model.SimilarSong, 0)
+	for rows.Next() {
+		var song model.SimilarSong
+		song.Song, err = scanSong(extraColumnsScanner{row: rows, extra: []any{&song.Similarity}})
+		if err != nil {
+			return nil, err
+		}
+		similar = append(similar, song)
+	}
+	return similar, rows.Err()
+}
+
+// extraColumnsScanner Сканирование строки, в которой после столбцов песни идут дополнительные столбцы
+type extraColumnsScanner struct {
+	row   rowScanner
+	extra []any
+}
+
+func (s extraColumnsScanner) Scan(dest ...any) error {
+	return s.row.Scan(append(dest, s.extra...)...)
+}
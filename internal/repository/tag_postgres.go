@@ -0,0 +1,132 @@
+package repository
+
+import (
+	"BestMusicLibrary/internal/model"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/jmoiron/sqlx"
+)
+
+type TagPostgresRepository struct {
+	db *sqlx.DB
+}
+
+// GetSongTags Теги песни по алфавиту
+func (s *TagPostgresRepository) GetSongTags(songId int64) ([]string, error) {
+	tags := make([]string, 0)
+	err := s.db.Select(&tags, `
+		SELECT t.name FROM song_tags st JOIN tags t ON t.id = st.tag_id
+		WHERE st.song_id = $1
+		ORDER BY t.name`, songId)
+	return tags, err
+}
+
+// AddSongTag Добавление тега песне. Тег создается при первом использовании, написание тега
+// с тем же ключом поиска остается прежним
+func (s *TagPostgresRepository) AddSongTag(songId int64, name, searchKey string) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+
+	var tagId int64
+	err = tx.QueryRow(`
+		INSERT INTO tags(name, search_key) VALUES($1, $2)
+		ON CONFLICT (search_key) DO UPDATE SET search_key = EXCLUDED.search_key
+		RETURNING id`, name, searchKey).Scan(&tagId)
+	if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	if _, err = tx.Exec(`INSERT INTO song_tags(song_id, tag_id) VALUES($1, $2) ON CONFLICT DO NOTHING`, songId, tagId); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// DeleteSongTag Удаление тега у песни. Тег, который больше ни у одной песни нет, удаляется.
+// Строка тега блокируется до удаления, а AddSongTag блокирует ее при вставке,
+// поэтому одновременное назначение тега не остается без удаленного тега
+func (s *TagPostgresRepository) DeleteSongTag(songId int64, searchKey string) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+
+	var tagId int64
+	err = tx.QueryRow(`SELECT id FROM tags WHERE search_key = $1 FOR UPDATE`, searchKey).Scan(&tagId)
+	if errors.Is(err, sql.ErrNoRows) {
+		_ = tx.Rollback()
+		return fmt.Errorf("tag %q of song %d: %w", searchKey, songId, model.ErrNotFound)
+	}
+	if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	result, err := tx.Exec(`DELETE FROM song_tags WHERE song_id = $1 AND tag_id = $2`, songId, tagId)
+	if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	if affected == 0 {
+		_ = tx.Rollback()
+		return fmt.Errorf("tag %q of song %d: %w", searchKey, songId, model.ErrNotFound)
+	}
+
+	_, err = tx.Exec(`
+		DELETE FROM tags t
+		WHERE t.id = $1 AND NOT EXISTS (SELECT 1 FROM song_tags st WHERE st.tag_id = t.id)`, tagId)
+	if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// SuggestTags Теги, ключ поиска которых начинается с prefix, начиная с самых используемых
+func (s *TagPostgresRepository) SuggestTags(prefix string, limit int) ([]string, error) {
+	tags := make([]string, 0)
+	err := s.db.Select(&tags, `
+		SELECT t.name
+		FROM tags t
+		JOIN song_tags st ON st.tag_id = t.id
+		WHERE t.search_key LIKE $1 || '%'
+		GROUP BY t.id
+		ORDER BY COUNT(*) DESC, t.name
+		LIMIT $2`, prefix, limit)
+	return tags, err
+}
+
+// GetTagCounts Число песен каждого тега, начиная с самых используемых
+func (s *TagPostgresRepository) GetTagCounts(page, limit int) ([]model.TagCount, error) {
+	rows, err := s.db.Query(`
+		SELECT t.name, COUNT(*)
+		FROM tags t
+		JOIN song_tags st ON st.tag_id = t.id
+		GROUP BY t.id
+		ORDER BY COUNT(*) DESC, t.name
+		LIMIT $1 OFFSET $2`, limit, page*limit)
+	if err != nil {
+		return nil, err
+	}
+	defer closeRows(rows)
+
+	counts := make([]model.TagCount, 0)
+	for rows.Next() {
+		var count model.TagCount
+		if err = rows.Scan(&count.Name, &count.SongCount); err != nil {
+			return nil, err
+		}
+		counts = append(counts, count)
+	}
+	return counts, rows.Err()
+}
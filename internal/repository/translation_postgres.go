@@ -0,0 +1,62 @@
+package repository
+
+import (
+	"BestMusicLibrary/internal/model"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+// GetSongLanguages Язык оригинала песни и языки ее переводов
+func (s *SongPostgresRepository) GetSongLanguages(id int64) (string, []string, error) {
+	var original string
+	err := s.db.QueryRow(`SELECT language FROM songs WHERE id = $1`, id).Scan(&original)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", nil, fmt.Errorf("song %d: %w", id, model.ErrNotFound)
+	}
+	if err != nil {
+		return "", nil, err
+	}
+
+	translations := make([]string, 0)
+	err = s.db.Select(&translations, `SELECT DISTINCT language FROM verse_translations WHERE song_id = $1 ORDER BY language`, id)
+	if err != nil {
+		return "", nil, err
+	}
+	return original, translations, nil
+}
+
+// ReplaceTranslation Замена перевода песни на указанный язык. Без куплетов перевод удаляется
+func (s *SongPostgresRepository) ReplaceTranslation(id int64, language string, verses []model.Verse) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+
+	if _, err = tx.Exec(`DELETE FROM verse_translations WHERE song_id = $1 AND language = $2`, id, language); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	for _, verse := range verses {
+		_, err = tx.Exec(`INSERT INTO verse_translations(song_id, language, verse_number, text) VALUES($1, $2, $3, $4)`,
+			id, language, verse.VerseNumber, verse.Text)
+		if err != nil {
+			_ = tx.Rollback()
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// deleteMisalignedTranslations Удаление переводов, число куплетов которых перестало совпадать с оригиналом.
+// Переводы песни, у которой изменился только текст куплетов, сохраняются
+func deleteMisalignedTranslations(tx *sql.Tx, songId int64) error {
+	_, err := tx.Exec(`
+		DELETE FROM verse_translations t
+		WHERE t.song_id = $1
+		AND (SELECT COUNT(*) FROM verses v WHERE v.song_id = $1) <>
+			(SELECT COUNT(*) FROM verse_translations o WHERE o.song_id = $1 AND o.language = t.language)`, songId)
+	return err
+}
@@ -0,0 +1,19 @@
+// Package search Ключи поиска песен. Ключ не зависит от регистра и письменности: кириллица транслитерируется
+// латиницей, поэтому запрос "Kino" находит группу "Кино" и наоборот
+package search
+
+import (
+	"BestMusicLibrary/internal/normalize"
+	"BestMusicLibrary/internal/translit"
+	"strings"
+)
+
+// markRemover Удаление апострофов и знаков, которыми транслитерация передает мягкий и твердый знаки
+var markRemover = strings.NewReplacer("`", "", "'", "")
+
+// Key Ключ поиска для названия группы или песни и для поискового запроса.
+// Перед транслитерацией текст нормализуется, поэтому формы NFC и NFD, типографские кавычки и буква ё не различаются
+func Key(s string) string {
+	key := markRemover.Replace(translit.ToLatin(strings.ToLower(normalize.Text(s))))
+	return strings.Join(strings.Fields(key), " ")
+}
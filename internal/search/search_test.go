@@ -0,0 +1,35 @@
+package search
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestKey(t *testing.T) {
+	cases := map[string]string{
+		"Кино":                    "kino",
+		"KINO":                    "kino",
+		"  Мумий   Тролль ":       "mumij troll",
+		"Океан Ельзи":             "okean elzi",
+		"Сплин":                   "splin",
+		"Rock'n'Roll":             "rocknroll",
+		"Би-2":                    "bi-2",
+		"Supermassive Black Hole": "supermassive black hole",
+		"Ёлка":                    "elka",
+		"Don’t Stop Me Now":       "dont stop me now",
+	}
+	for value, expected := range cases {
+		assert.Equal(t, expected, Key(value), value)
+	}
+}
+
+func TestKeyMatchesAcrossScripts(t *testing.T) {
+	assert.Contains(t, Key("Группа Крови"), Key("gruppa"))
+	assert.Contains(t, Key("Kino"), Key("Кино"))
+}
+
+func TestKeyIgnoresUnicodeVariants(t *testing.T) {
+	assert.Equal(t, Key("Чёрный кот"), Key("Черныи\u0306 кот"))
+	assert.Equal(t, Key("«Кукушка»"), Key("\"Кукушка\""))
+	assert.Equal(t, Key("Beyoncé"), Key("Beyonce\u0301"))
+}
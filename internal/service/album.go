@@ -0,0 +1,142 @@
+package service
+
+import (
+	"BestMusicLibrary/internal/model"
+	"BestMusicLibrary/internal/repository"
+	"BestMusicLibrary/internal/search"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+type AlbumService struct {
+	albumRepos  repository.Album
+	artistRepos repository.Artist
+}
+
+func NewAlbumService(repos repository.Album, artistRepos repository.Artist) *AlbumService {
+	return &AlbumService{albumRepos: repos, artistRepos: artistRepos}
+}
+
+// trackPosition Позиция трека в альбоме
+type trackPosition struct {
+	disc  int
+	track int
+}
+
+// GetAlbums Альбомы с фильтром по исполнителю и названию и пагинацией
+func (s *AlbumService) GetAlbums(filter model.AlbumFilter, rawPage, rawLimit int) ([]model.Album, error) {
+	page, limit := handlePagingData(rawPage, rawLimit)
+	filter.Title = search.Key(filter.Title)
+	return s.albumRepos.GetAlbums(filter, page, limit)
+}
+
+// GetAlbum Альбом с трек-листом
+func (s *AlbumService) GetAlbum(id int64) (model.Album, error) {
+	return s.albumRepos.GetAlbum(id)
+}
+
+func (s *AlbumService) AddAlbum(album model.Album) (int64, error) {
+	if err := s.prepareAlbum(&album); err != nil {
+		return 0, err
+	}
+	return s.albumRepos.AddAlbum(album)
+}
+
+// UpdateAlbum Изменение данных альбома без трек-листа
+func (s *AlbumService) UpdateAlbum(album model.Album) error {
+	if err := s.prepareAlbum(&album); err != nil {
+		return err
+	}
+	return s.albumRepos.UpdateAlbum(album)
+}
+
+// DeleteAlbum Удаление альбома, песни альбома остаются в библиотеке
+func (s *AlbumService) DeleteAlbum(id int64) error {
+	return s.albumRepos.DeleteAlbum(id)
+}
+
+// ReplaceAlbumTracks Замена трек-листа, в том числе изменение порядка треков. Песня входит в альбом один раз,
+// позиции треков не повторяются, пропущенный номер диска равен единице
+func (s *AlbumService) ReplaceAlbumTracks(albumId int64, tracks []model.AlbumTrack) error {
+	songs := make(map[int64]bool, len(tracks))
+	positions := make(map[trackPosition]bool, len(tracks))
+	for index := range tracks {
+		track := &tracks[index]
+		if track.DiscNumber == 0 {
+			track.DiscNumber = 1
+		}
+		if err := validateTrack(*track); err != nil {
+			return err
+		}
+		if track.TrackNumber == 0 {
+			return fmt.Errorf("%w: track number of song %d is required", model.ErrInvalidInput, track.SongId)
+		}
+
+		if songs[track.SongId] {
+			return fmt.Errorf("%w: song %d is listed twice", model.ErrInvalidInput, track.SongId)
+		}
+		songs[track.SongId] = true
+
+		position := trackPosition{disc: track.DiscNumber, track: track.TrackNumber}
+		if positions[position] {
+			return fmt.Errorf("%w: disc %d track %d is listed twice", model.ErrInvalidInput, position.disc, position.track)
+		}
+		positions[position] = true
+	}
+	return s.albumRepos.ReplaceAlbumTracks(albumId, tracks)
+}
+
+// AddAlbumTrack Добавление песни в альбом. Без номера трека песня становится последней на диске
+func (s *AlbumService) AddAlbumTrack(albumId int64, track model.AlbumTrack) error {
+	if track.DiscNumber == 0 {
+		track.DiscNumber = 1
+	}
+	if err := validateTrack(track); err != nil {
+		return err
+	}
+	return s.albumRepos.AddAlbumTrack(albumId, track)
+}
+
+func (s *AlbumService) DeleteAlbumTrack(albumId, songId int64) error {
+	return s.albumRepos.DeleteAlbumTrack(albumId, songId)
+}
+
+// prepareAlbum Проверка полей альбома и заполнение ключа поиска. Исполнитель обязателен для всех типов, кроме сборника
+func (s *AlbumService) prepareAlbum(album *model.Album) error {
+	album.Title = strings.TrimSpace(album.Title)
+	album.TitleSearchKey = search.Key(album.Title)
+	if album.TitleSearchKey == "" {
+		return fmt.Errorf("%w: album title is required", model.ErrInvalidInput)
+	}
+
+	switch album.Type {
+	case "":
+		album.Type = model.AlbumTypeLP
+	case model.AlbumTypeLP, model.AlbumTypeEP, model.AlbumTypeSingle, model.AlbumTypeCompilation:
+	default:
+		return fmt.Errorf("%w: unknown album type %q", model.ErrInvalidInput, album.Type)
+	}
+
+	if album.ArtistId == 0 {
+		if album.Type != model.AlbumTypeCompilation {
+			return fmt.Errorf("%w: artist_id is required for %s", model.ErrInvalidInput, album.Type)
+		}
+		return nil
+	}
+	_, err := s.artistRepos.GetArtist(album.ArtistId)
+	if errors.Is(err, model.ErrNotFound) {
+		return fmt.Errorf("%w: unknown artist %d", model.ErrInvalidInput, album.ArtistId)
+	}
+	return err
+}
+
+func validateTrack(track model.AlbumTrack) error {
+	if track.SongId <= 0 {
+		return fmt.Errorf("%w: song_id is required", model.ErrInvalidInput)
+	}
+	if track.DiscNumber < 0 || track.TrackNumber < 0 {
+		return fmt.Errorf("%w: disc and track numbers of song %d must be positive", model.ErrInvalidInput, track.SongId)
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+package service
+
+import (
+	"BestMusicLibrary/internal/model"
+	"BestMusicLibrary/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+// stubAlbumRepository Запоминает последний записанный альбом и трек-лист
+type stubAlbumRepository struct {
+	repository.Album
+	album  model.Album
+	tracks []model.AlbumTrack
+}
+
+func (r *stubAlbumRepository) AddAlbum(album model.Album) (int64, error) {
+	r.album = album
+	return 1, nil
+}
+
+func (r *stubAlbumRepository) ReplaceAlbumTracks(_ int64, tracks []model.AlbumTrack) error {
+	r.tracks = tracks
+	return nil
+}
+
+func (r *stubAlbumRepository) AddAlbumTrack(_ int64, track model.AlbumTrack) error {
+	r.tracks = append(r.tracks, track)
+	return nil
+}
+
+func newAlbumTestService() (*AlbumService, *stubAlbumRepository) {
+	repos := &stubAlbumRepository{}
+	artists := &stubArtistRepository{artists: []model.Artist{{Id: 1, Name: "Кино", SearchKey: "kino"}}}
+	return NewAlbumService(repos, artists), repos
+}
+
+func TestAddAlbumValidatesArtistAndType(t *testing.T) {
+	albumService, repos := newAlbumTestService()
+
+	_, err := albumService.AddAlbum(model.Album{ArtistId: 1, Title: " Группа крови "})
+	require.NoError(t, err)
+	assert.Equal(t, "Группа крови", repos.album.Title)
+	assert.Equal(t, "gruppa krovi", repos.album.TitleSearchKey)
+	assert.Equal(t, model.AlbumTypeLP, repos.album.Type)
+
+	_, err = albumService.AddAlbum(model.Album{Title: "Лучшие песни", Type: model.AlbumTypeCompilation})
+	require.NoError(t, err)
+
+	_, err = albumService.AddAlbum(model.Album{Title: "Звезда по имени Солнце"})
+	assert.ErrorIs(t, err, model.ErrInvalidInput)
+
+	_, err = albumService.AddAlbum(model.Album{ArtistId: 2, Title: "Звезда по имени Солнце"})
+	assert.ErrorIs(t, err, model.ErrInvalidInput)
+
+	_, err = albumService.AddAlbum(model.Album{ArtistId: 1, Title: "Ночь", Type: "mixtape"})
+	assert.ErrorIs(t, err, model.ErrInvalidInput)
+}
+
+func TestReplaceAlbumTracks(t *testing.T) {
+	albumService, repos := newAlbumTestService()
+
+	require.NoError(t, albumService.ReplaceAlbumTracks(1, []model.AlbumTrack{
+		{SongId: 10, TrackNumber: 2},
+		{SongId: 11, TrackNumber: 1},
+		{SongId: 12, DiscNumber: 2, TrackNumber: 1},
+	}))
+	assert.Equal(t, 1, repos.tracks[0].DiscNumber)
+	assert.Equal(t, 2, repos.tracks[2].DiscNumber)
+
+	err := albumService.ReplaceAlbumTracks(1, []model.AlbumTrack{{SongId: 10, TrackNumber: 1}, {SongId: 10, TrackNumber: 2}})
+	assert.ErrorIs(t, err, model.ErrInvalidInput)
+
+	err = albumService.ReplaceAlbumTracks(1, []model.AlbumTrack{{SongId: 10, TrackNumber: 1}, {SongId: 11, DiscNumber: 1, TrackNumber: 1}})
+	assert.ErrorIs(t, err, model.ErrInvalidInput)
+
+	err = albumService.ReplaceAlbumTracks(1, []model.AlbumTrack{{SongId: 10}})
+	assert.ErrorIs(t, err, model.ErrInvalidInput)
+}
+
+func TestAddAlbumTrackDefaultsDisc(t *testing.T) {
+	albumService, repos := newAlbumTestService()
+
+	require.NoError(t, albumService.AddAlbumTrack(1, model.AlbumTrack{SongId: 10}))
+	assert.Equal(t, model.AlbumTrack{SongId: 10, DiscNumber: 1}, repos.tracks[0])
+
+	assert.ErrorIs(t, albumService.AddAlbumTrack(1, model.AlbumTrack{SongId: 10, TrackNumber: -1}), model.ErrInvalidInput)
+}
@@ -0,0 +1,134 @@
+package service
+
+import (
+	"BestMusicLibrary/internal/model"
+	"BestMusicLibrary/internal/repository"
+	"BestMusicLibrary/internal/search"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+type ArtistService struct {
+	artistRepos repository.Artist
+}
+
+func NewArtistService(repos repository.Artist) *ArtistService {
+	return &ArtistService{artistRepos: repos}
+}
+
+// GetArtists Исполнители с фильтром по имени или другому написанию на любой письменности и пагинацией
+func (s *ArtistService) GetArtists(filter model.ArtistFilter, rawPage, rawLimit int) ([]model.Artist, error) {
+	page, limit := handlePagingData(rawPage, rawLimit)
+	filter.Name = search.Key(filter.Name)
+	return s.artistRepos.GetArtists(filter, page, limit)
+}
+
+func (s *ArtistService) GetArtist(id int64) (model.Artist, error) {
+	return s.artistRepos.GetArtist(id)
+}
+
+// AddArtist Добавление исполнителя. Имя и другие написания не должны совпадать по ключу поиска с другим исполнителем
+func (s *ArtistService) AddArtist(artist model.Artist) (int64, error) {
+	artist.Id = 0
+	if err := s.prepareArtist(&artist); err != nil {
+		return 0, err
+	}
+	return s.artistRepos.AddArtist(artist)
+}
+
+// UpdateArtist Изменение исполнителя, новое имя показывается во всех его песнях
+func (s *ArtistService) UpdateArtist(artist model.Artist) error {
+	if err := s.prepareArtist(&artist); err != nil {
+		return err
+	}
+	return s.artistRepos.UpdateArtist(artist)
+}
+
+// DeleteArtist Удаление исполнителя. Исполнителя с песнями или альбомами удалить нельзя
+func (s *ArtistService) DeleteArtist(id int64) error {
+	return s.artistRepos.DeleteArtist(id)
+}
+
+// prepareArtist Проверка и нормализация полей исполнителя и заполнение ключей поиска
+func (s *ArtistService) prepareArtist(artist *model.Artist) error {
+	artist.Name = strings.TrimSpace(artist.Name)
+	artist.SearchKey = search.Key(artist.Name)
+	if artist.SearchKey == "" {
+		return fmt.Errorf("%w: artist name is required", model.ErrInvalidInput)
+	}
+
+	artist.Country = strings.ToUpper(strings.TrimSpace(artist.Country))
+	if artist.Country != "" && !isCountryCode(artist.Country) {
+		return fmt.Errorf("%w: country %q is not an ISO 3166-1 alpha-2 code", model.ErrInvalidInput, artist.Country)
+	}
+	if artist.FormedYear != nil && *artist.FormedYear <= 0 {
+		return fmt.Errorf("%w: invalid formed year %d", model.ErrInvalidInput, *artist.FormedYear)
+	}
+	if artist.DisbandedYear != nil && artist.FormedYear != nil && *artist.DisbandedYear < *artist.FormedYear {
+		return fmt.Errorf("%w: disbanded year %d is before formed year %d", model.ErrInvalidInput, *artist.DisbandedYear, *artist.FormedYear)
+	}
+
+	aliases := make([]string, 0, len(artist.Aliases))
+	aliasKeys := make([]string, 0, len(artist.Aliases))
+	seen := map[string]bool{artist.Name: true}
+	for _, alias := range artist.Aliases {
+		alias = strings.TrimSpace(alias)
+		if seen[alias] || search.Key(alias) == "" {
+			continue
+		}
+		seen[alias] = true
+		aliases = append(aliases, alias)
+		aliasKeys = append(aliasKeys, search.Key(alias))
+	}
+	artist.Aliases, artist.AliasSearchKeys = aliases, aliasKeys
+
+	for _, key := range append([]string{artist.SearchKey}, aliasKeys...) {
+		existing, err := s.artistRepos.FindArtistBySearchKey(key)
+		if errors.Is(err, model.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if existing.Id != artist.Id {
+			return fmt.Errorf("%w: %q already names artist %d", model.ErrConflict, key, existing.Id)
+		}
+	}
+	return nil
+}
+
+func isCountryCode(country string) bool {
+	return len(country) == 2 && country[0] >= 'A' && country[0] <= 'Z' && country[1] >= 'A' && country[1] <= 'Z'
+}
+
+// resolveArtist Исполнитель песни: указанный клиентом по идентификатору или найденный по названию группы
+// среди имен и других написаний. Название группы в песне заменяется каноническим именем исполнителя.
+// Для неизвестной группы ArtistId остается нулевым, исполнитель создается в транзакции записи песни
+func (s *SongService) resolveArtist(song *model.Song) error {
+	if song.ArtistId != 0 {
+		artist, err := s.artistRepos.GetArtist(song.ArtistId)
+		if errors.Is(err, model.ErrNotFound) {
+			return fmt.Errorf("%w: unknown artist %d", model.ErrInvalidInput, song.ArtistId)
+		}
+		if err != nil {
+			return err
+		}
+		song.Group = artist.Name
+		return nil
+	}
+
+	song.Group = strings.TrimSpace(song.Group)
+	if search.Key(song.Group) == "" {
+		return fmt.Errorf("%w: group or artist_id is required", model.ErrInvalidInput)
+	}
+	artist, err := s.artistRepos.FindArtistBySearchKey(search.Key(song.Group))
+	if errors.Is(err, model.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	song.ArtistId, song.Group = artist.Id, artist.Name
+	return nil
+}
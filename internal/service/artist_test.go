@@ -0,0 +1,83 @@
+package service
+
+import (
+	"BestMusicLibrary/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestAddArtistNormalizesFields(t *testing.T) {
+	repos := &stubArtistRepository{}
+	artistService := NewArtistService(repos)
+	formed := 1981
+
+	id, err := artistService.AddArtist(model.Artist{
+		Name:       " Кино ",
+		Aliases:    []string{"Kino", "Кино", " ", "Kino"},
+		Country:    "su",
+		FormedYear: &formed,
+	})
+	require.NoError(t, err)
+
+	artist, err := repos.GetArtist(id)
+	require.NoError(t, err)
+	assert.Equal(t, "Кино", artist.Name)
+	assert.Equal(t, "kino", artist.SearchKey)
+	assert.Equal(t, []string{"Kino"}, artist.Aliases)
+	assert.Equal(t, []string{"kino"}, artist.AliasSearchKeys)
+	assert.Equal(t, "SU", artist.Country)
+}
+
+func TestAddArtistRejectsInvalidFields(t *testing.T) {
+	artistService := NewArtistService(&stubArtistRepository{})
+	formed, disbanded := 1990, 1985
+
+	_, err := artistService.AddArtist(model.Artist{Name: "  "})
+	assert.ErrorIs(t, err, model.ErrInvalidInput)
+
+	_, err = artistService.AddArtist(model.Artist{Name: "Aria", Country: "RUS"})
+	assert.ErrorIs(t, err, model.ErrInvalidInput)
+
+	_, err = artistService.AddArtist(model.Artist{Name: "Aria", FormedYear: &formed, DisbandedYear: &disbanded})
+	assert.ErrorIs(t, err, model.ErrInvalidInput)
+}
+
+func TestArtistNamesMustNotCollide(t *testing.T) {
+	repos := &stubArtistRepository{}
+	artistService := NewArtistService(repos)
+
+	id, err := artistService.AddArtist(model.Artist{Name: "Кино", Aliases: []string{"Kino"}})
+	require.NoError(t, err)
+
+	_, err = artistService.AddArtist(model.Artist{Name: "KINO"})
+	assert.ErrorIs(t, err, model.ErrConflict)
+
+	otherId, err := artistService.AddArtist(model.Artist{Name: "Алиса"})
+	require.NoError(t, err)
+	err = artistService.UpdateArtist(model.Artist{Id: otherId, Name: "Алиса", Aliases: []string{"Кино"}})
+	assert.ErrorIs(t, err, model.ErrConflict)
+
+	require.NoError(t, artistService.UpdateArtist(model.Artist{Id: id, Name: "Kino", Aliases: []string{"Кино"}}))
+}
+
+func TestUpdateSongResolvesArtist(t *testing.T) {
+	artists := &stubArtistRepository{artists: []model.Artist{
+		{Id: 7, Name: "Кино", SearchKey: "kino", AliasSearchKeys: []string{"kino band"}},
+	}}
+	repos := &stubSongRepository{song: model.Song{Id: 1}}
+	songService := NewSongService(repos, SongServiceOptions{Artists: artists})
+
+	require.NoError(t, songService.UpdateSong(model.Song{Id: 1, Group: "Kino Band", Name: "Кукушка"}, ""))
+	assert.Equal(t, int64(7), repos.song.ArtistId)
+	assert.Equal(t, "Кино", repos.song.Group)
+	assert.Equal(t, "kino", repos.song.GroupSearchKey)
+
+	require.NoError(t, songService.UpdateSong(model.Song{Id: 1, Group: "Алиса", Name: "Трасса Е95"}, ""))
+	assert.Zero(t, repos.song.ArtistId)
+	assert.Equal(t, "alisa", repos.song.GroupSearchKey)
+	assert.Len(t, artists.artists, 1)
+
+	err := songService.UpdateSong(model.Song{Id: 1, ArtistId: 42, Name: "Кукушка"}, "")
+	assert.ErrorIs(t, err, model.ErrInvalidInput)
+}
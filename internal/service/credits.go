@@ -0,0 +1,185 @@
+package service
+
+import (
+	"BestMusicLibrary/internal/credits"
+	"BestMusicLibrary/internal/model"
+	"BestMusicLibrary/internal/search"
+	"errors"
+	"fmt"
+)
+
+// GetSongCredits Участники песни, основной исполнитель первым
+func (s *SongService) GetSongCredits(id int64) ([]model.SongCredit, error) {
+	if err := checkSongExists(s.songRepos, id); err != nil {
+		return nil, err
+	}
+	return s.songRepos.GetSongCredits(id)
+}
+
+// SetSongCredits Замена всех участников песни. Нужен хотя бы один основной исполнитель, первый из них
+// становится исполнителем песни, поэтому песня проверяется на дубликат с новым названием группы
+func (s *SongService) SetSongCredits(id int64, songCredits []model.SongCredit) error {
+	primary := make([]model.SongCredit, 0, len(songCredits))
+	other := make([]model.SongCredit, 0, len(songCredits))
+	for _, credit := range songCredits {
+		if !isCreditRole(credit.Role) {
+			return fmt.Errorf("%w: unknown credit role %q", model.ErrInvalidInput, credit.Role)
+		}
+		if credit.ArtistId <= 0 {
+			return fmt.Errorf("%w: artist_id is required in credits", model.ErrInvalidInput)
+		}
+		if credit.Role == model.CreditRolePrimary {
+			primary = appendCredit(primary, credit)
+		} else {
+			other = appendCredit(other, credit)
+		}
+	}
+	if len(primary) == 0 {
+		return fmt.Errorf("%w: credits need a primary artist", model.ErrInvalidInput)
+	}
+
+	song, err := s.songRepos.GetSong(id)
+	if err != nil {
+		return err
+	}
+	artist, err := s.artistRepos.GetArtist(primary[0].ArtistId)
+	if errors.Is(err, model.ErrNotFound) {
+		return fmt.Errorf("%w: unknown artist %d", model.ErrInvalidInput, primary[0].ArtistId)
+	}
+	if err != nil {
+		return err
+	}
+	song.ArtistId, song.Group = artist.Id, artist.Name
+	setSearchKeys(&song)
+	if err = s.checkDuplicate(song); err != nil {
+		return err
+	}
+
+	for _, credit := range other {
+		primary = appendCredit(primary, credit)
+	}
+	return s.songRepos.ReplaceSongCredits(id, primary)
+}
+
+// parseCredits Разбор участников из названий группы и песни перед записью. Отметки "feat." и "ft." убираются
+// из названий, приглашенные участники попадают в Credits. Группа, перечисленная через "&", делится на нескольких
+// основных исполнителей, только если каждая часть уже известна как исполнитель
+func (s *SongService) parseCredits(song *model.Song) error {
+	songCredits := make([]model.SongCredit, 0)
+	if song.ArtistId == 0 {
+		group, featured := credits.Featuring(song.Group)
+		names, err := s.splitArtistNames(group)
+		if err != nil {
+			return err
+		}
+		song.Group = names[0]
+		for _, name := range names[1:] {
+			songCredits = append(songCredits, model.SongCredit{Artist: name, Role: model.CreditRolePrimary})
+		}
+		if songCredits, err = s.appendFeatured(songCredits, featured); err != nil {
+			return err
+		}
+	}
+
+	title, featured := credits.Featuring(song.Name)
+	song.Name = title
+	songCredits, err := s.appendFeatured(songCredits, featured)
+	if err != nil {
+		return err
+	}
+	song.Credits = songCredits
+	return nil
+}
+
+func (s *SongService) appendFeatured(songCredits []model.SongCredit, featured string) ([]model.SongCredit, error) {
+	if featured == "" {
+		return songCredits, nil
+	}
+	names, err := s.splitArtistNames(featured)
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range names {
+		songCredits = append(songCredits, model.SongCredit{Artist: name, Role: model.CreditRoleFeatured})
+	}
+	return songCredits, nil
+}
+
+// splitArtistNames Имена исполнителей из строки вида "A & B". Строка делится, только если она сама не является
+// именем известного исполнителя, а каждая ее часть является: иначе "Earth, Wind & Fire" и подобные названия
+// стали бы несколькими новыми исполнителями. Перечислить участников явно можно через PUT /songs/{id}/credits
+func (s *SongService) splitArtistNames(names string) ([]string, error) {
+	parts := credits.SplitNames(names)
+	if len(parts) < 2 {
+		return []string{names}, nil
+	}
+	known, err := s.isKnownArtist(names)
+	if known || err != nil {
+		return []string{names}, err
+	}
+	for _, part := range parts {
+		if known, err = s.isKnownArtist(part); !known || err != nil {
+			return []string{names}, err
+		}
+	}
+	return parts, nil
+}
+
+func (s *SongService) isKnownArtist(name string) (bool, error) {
+	_, err := s.artistRepos.FindArtistBySearchKey(search.Key(name))
+	if errors.Is(err, model.ErrNotFound) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// resolveCredits Поиск известных исполнителей среди разобранных участников и удаление повторов.
+// Для неизвестных исполнителей заполняется ключ поиска, они создаются при записи песни
+func (s *SongService) resolveCredits(song *model.Song) error {
+	songCredits := []model.SongCredit{{ArtistId: song.ArtistId, SearchKey: song.GroupSearchKey, Role: model.CreditRolePrimary}}
+	for _, credit := range song.Credits {
+		credit.SearchKey = search.Key(credit.Artist)
+		if credit.SearchKey == "" {
+			continue
+		}
+		artist, err := s.artistRepos.FindArtistBySearchKey(credit.SearchKey)
+		if err == nil {
+			credit.ArtistId, credit.Artist = artist.Id, artist.Name
+		} else if !errors.Is(err, model.ErrNotFound) {
+			return err
+		}
+		songCredits = appendCredit(songCredits, credit)
+	}
+	song.Credits = songCredits[1:]
+	return nil
+}
+
+// appendCredit Добавление участника без повторов. Основной исполнитель не указывается приглашенным
+func appendCredit(songCredits []model.SongCredit, credit model.SongCredit) []model.SongCredit {
+	for _, existing := range songCredits {
+		if !sameArtist(existing, credit) {
+			continue
+		}
+		if existing.Role == credit.Role || existing.Role == model.CreditRolePrimary && credit.Role == model.CreditRoleFeatured {
+			return songCredits
+		}
+	}
+	return append(songCredits, credit)
+}
+
+// sameArtist Один ли исполнитель в двух участиях. Еще не созданные исполнители сравниваются по ключу поиска
+func sameArtist(a, b model.SongCredit) bool {
+	if a.ArtistId != 0 || b.ArtistId != 0 {
+		return a.ArtistId == b.ArtistId
+	}
+	return a.SearchKey == b.SearchKey
+}
+
+func isCreditRole(role model.CreditRole) bool {
+	for _, r := range model.CreditRoles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
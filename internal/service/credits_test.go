@@ -0,0 +1,81 @@
+package service
+
+import (
+	"BestMusicLibrary/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestUpdateSongParsesCredits(t *testing.T) {
+	artists := &stubArtistRepository{artists: []model.Artist{
+		{Id: 1, Name: "Simon & Garfunkel", SearchKey: "simon & garfunkel"},
+		{Id: 2, Name: "Баста", SearchKey: "basta"},
+		{Id: 3, Name: "Смоки Мо", SearchKey: "smoki mo"},
+	}}
+	repos := &stubSongRepository{song: model.Song{Id: 1}, credits: []model.SongCredit{
+		{ArtistId: 1, Role: model.CreditRolePrimary},
+		{ArtistId: 9, Role: model.CreditRoleFeatured},
+		{ArtistId: 1, Role: model.CreditRoleProducer},
+	}}
+	songService := NewSongService(repos, SongServiceOptions{Artists: artists})
+
+	require.NoError(t, songService.UpdateSong(model.Song{Id: 1, Group: "Баста & Смоки Мо ft. Скриптонит", Name: "Song (feat. Guf)"}, ""))
+	assert.Equal(t, "Баста", repos.song.Group)
+	assert.Equal(t, "Song", repos.song.Name)
+	assert.Equal(t, []model.SongCredit{
+		{ArtistId: 2, Artist: "Баста", Role: model.CreditRolePrimary},
+		{ArtistId: 3, Artist: "Смоки Мо", Role: model.CreditRolePrimary, SearchKey: "smoki mo"},
+		{Artist: "Скриптонит", Role: model.CreditRoleFeatured, SearchKey: "skriptonit"},
+		{Artist: "Guf", Role: model.CreditRoleFeatured, SearchKey: "guf"},
+		{ArtistId: 1, Role: model.CreditRoleProducer},
+	}, repos.credits)
+
+	require.NoError(t, songService.UpdateSong(model.Song{Id: 1, Group: "Simon & Garfunkel", Name: "The Boxer"}, ""))
+	assert.Equal(t, int64(1), repos.song.ArtistId)
+	assert.Equal(t, []model.SongCredit{
+		{ArtistId: 1, Artist: "Simon & Garfunkel", Role: model.CreditRolePrimary},
+		{ArtistId: 1, Role: model.CreditRoleProducer},
+	}, repos.credits)
+}
+
+func TestUpdateSongKeepsUnknownNamesWhole(t *testing.T) {
+	artists := &stubArtistRepository{artists: []model.Artist{{Id: 1, Name: "Young", SearchKey: "young"}}}
+	repos := &stubSongRepository{song: model.Song{Id: 1}}
+	songService := NewSongService(repos, SongServiceOptions{Artists: artists})
+
+	require.NoError(t, songService.UpdateSong(model.Song{Id: 1, Group: "Earth, Wind & Fire feat. Crosby, Stills, Nash & Young", Name: "Song"}, ""))
+	assert.Equal(t, "Earth, Wind & Fire", repos.song.Group)
+	assert.Equal(t, []model.SongCredit{
+		{ArtistId: repos.song.ArtistId, Artist: "Earth, Wind & Fire", Role: model.CreditRolePrimary},
+		{Artist: "Crosby, Stills, Nash & Young", Role: model.CreditRoleFeatured, SearchKey: "crosby, stills, nash & young"},
+	}, repos.credits)
+}
+
+func TestSetSongCredits(t *testing.T) {
+	artists := &stubArtistRepository{artists: []model.Artist{
+		{Id: 1, Name: "Eminem", SearchKey: "eminem"},
+		{Id: 2, Name: "Rihanna", SearchKey: "rihanna"},
+	}}
+	repos := &stubSongRepository{song: model.Song{Id: 1, ArtistId: 2, Group: "Rihanna", Name: "Love the Way You Lie"}}
+	songService := NewSongService(repos, SongServiceOptions{Artists: artists})
+
+	require.NoError(t, songService.SetSongCredits(1, []model.SongCredit{
+		{ArtistId: 2, Role: model.CreditRoleFeatured},
+		{ArtistId: 1, Role: model.CreditRolePrimary},
+		{ArtistId: 1, Role: model.CreditRolePrimary},
+	}))
+	assert.Equal(t, []model.SongCredit{
+		{ArtistId: 1, Role: model.CreditRolePrimary},
+		{ArtistId: 2, Role: model.CreditRoleFeatured},
+	}, repos.credits)
+
+	err := songService.SetSongCredits(1, []model.SongCredit{{ArtistId: 2, Role: model.CreditRoleFeatured}})
+	assert.ErrorIs(t, err, model.ErrInvalidInput)
+
+	err = songService.SetSongCredits(1, []model.SongCredit{{ArtistId: 1, Role: "singer"}})
+	assert.ErrorIs(t, err, model.ErrInvalidInput)
+
+	err = songService.SetSongCredits(1, []model.SongCredit{{ArtistId: 3, Role: model.CreditRolePrimary}})
+	assert.ErrorIs(t, err, model.ErrInvalidInput)
+}
@@ -0,0 +1,117 @@
+package service
+
+import (
+	"BestMusicLibrary/internal/model"
+	"context"
+	"fmt"
+	"strings"
+)
+
+// FieldChange Изменение поля песни, предложенное провайдером
+type FieldChange struct {
+	Field    SongField
+	Old      string
+	New      string
+	Provider string
+}
+
+// EnrichmentDiff Результат повторного обогащения песни
+type EnrichmentDiff struct {
+	SongId   int64
+	Changes  []FieldChange
+	Warnings []string
+	Applied  bool
+}
+
+// EnrichSong Повторное обогащение сохраненной песни. Сохраненное значение поля выбирается против значения
+// провайдера по тем же правилам Precedence, что и значение клиента при добавлении песни.
+// В режиме dryRun изменения только вычисляются
+func (s *SongService) EnrichSong(id int64, dryRun bool) (EnrichmentDiff, error) {
+	song, err := s.songRepos.GetSong(id)
+	if err != nil {
+		return EnrichmentDiff{}, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), enrichmentTimeout)
+	defer cancel()
+	songDetails, sources, err := s.providers.Fetch(ctx, song.Group, song.Name)
+	if err != nil {
+		return EnrichmentDiff{}, err
+	}
+
+	diff := EnrichmentDiff{SongId: id, Changes: make([]FieldChange, 0), Warnings: make([]string, 0)}
+	updated := song
+	updated.Sources = make([]model.SongSource, 0)
+	for _, source := range sources {
+		field := SongField(source.Field)
+		value := songDetails.field(field)
+		stored := songFieldValue(updated, field)
+		if _, keepStored := s.precedence[field].resolve(&stored, value); keepStored {
+			continue
+		}
+
+		candidate := updated
+		switch field {
+		case SongFieldReleaseDate:
+			releaseDate, precision, parseErr := parseReleaseDate(value)
+			if parseErr != nil {
+				diff.Warnings = append(diff.Warnings, releaseDateWarning(parseErr, source.Provider))
+				continue
+			}
+			candidate.ReleaseDate = releaseDate
+			candidate.ReleaseDatePrecision = precision
+		case SongFieldText:
+			candidate.Verses, candidate.Arrangement = parseLyrics(value)
+		case SongFieldLink:
+			candidate.Link = value
+		}
+
+		newValue := songFieldValue(candidate, field)
+		if stored == newValue {
+			continue
+		}
+
+		updated = candidate
+		updated.Sources = append(updated.Sources, source)
+		diff.Changes = append(diff.Changes, FieldChange{Field: field, Old: stored, New: newValue, Provider: source.Provider})
+	}
+
+	if dryRun || len(diff.Changes) == 0 {
+		return diff, nil
+	}
+
+	detectSongLanguage(&updated)
+	s.detectExplicit(&updated)
+	setSearchKeys(&updated)
+	setSongTerms(&updated)
+	if err = s.songRepos.UpdateSong(updated); err != nil {
+		return EnrichmentDiff{}, err
+	}
+	diff.Applied = true
+
+	return diff, nil
+}
+
+func songFieldValue(song model.Song, field SongField) string {
+	switch field {
+	case SongFieldReleaseDate:
+		return formatReleaseDate(song.ReleaseDate, song.ReleaseDatePrecision)
+	case SongFieldText:
+		return versesToText(expandVerses(song.Verses, song.Arrangement))
+	case SongFieldLink:
+		return song.Link
+	}
+	return ""
+}
+
+func releaseDateWarning(err error, provider string) string {
+	return fmt.Sprintf("%s: %s from provider %s", SongFieldReleaseDate, err, provider)
+}
+
+func versesToText(verses []model.Verse) string {
+	texts := make([]string, 0, len(verses))
+	for _, verse := range verses {
+		texts = append(texts, verse.Text)
+	}
+	return strings.Join(texts, "\n\n")
+}
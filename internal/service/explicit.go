@@ -0,0 +1,18 @@
+package service
+
+import "BestMusicLibrary/internal/model"
+
+// detectExplicit Определение нецензурной лексики в названии и тексте песни.
+// Без детектора песня не отмечается, решение редактора не меняется
+func (s *SongService) detectExplicit(song *model.Song) {
+	if s.explicitDetector == nil {
+		return
+	}
+	explicit := s.explicitDetector.Detect(song.Name + "\n\n" + versesToText(song.Verses))
+	song.ExplicitDetected = &explicit
+}
+
+// SetExplicitOverride Ручная отметка откровенной песни редактором. nil возвращает автоматическое определение
+func (s *SongService) SetExplicitOverride(id int64, explicit *bool) error {
+	return s.songRepos.SetExplicitOverride(id, explicit)
+}
@@ -0,0 +1,110 @@
+package service
+
+import (
+	"BestMusicLibrary/internal/model"
+	"BestMusicLibrary/internal/repository"
+	"BestMusicLibrary/internal/search"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+type GenreService struct {
+	genreRepos repository.Genre
+	songRepos  repository.Song
+}
+
+func NewGenreService(repos repository.Genre, songRepos repository.Song) *GenreService {
+	return &GenreService{genreRepos: repos, songRepos: songRepos}
+}
+
+// GetGenreTree Дерево жанров: корневые жанры с вложенными поджанрами, на каждом уровне по алфавиту
+func (s *GenreService) GetGenreTree() ([]model.Genre, error) {
+	genres, err := s.genreRepos.GetGenres()
+	if err != nil {
+		return nil, err
+	}
+	return genreTree(genres, 0), nil
+}
+
+// AddGenre Добавление жанра. Названия жанров уникальны по ключу поиска во всей иерархии
+func (s *GenreService) AddGenre(genre model.Genre) (int64, error) {
+	genre.Id = 0
+	if err := s.prepareGenre(&genre); err != nil {
+		return 0, err
+	}
+	return s.genreRepos.AddGenre(genre)
+}
+
+// UpdateGenre Переименование жанра или перенос к другому родителю. Жанр нельзя сделать поджанром самого себя
+// или своего поджанра, последнее проверяет репозиторий при сохранении
+func (s *GenreService) UpdateGenre(genre model.Genre) error {
+	if err := s.prepareGenre(&genre); err != nil {
+		return err
+	}
+	return s.genreRepos.UpdateGenre(genre)
+}
+
+// DeleteGenre Удаление жанра без поджанров
+func (s *GenreService) DeleteGenre(id int64) error {
+	return s.genreRepos.DeleteGenre(id)
+}
+
+func (s *GenreService) GetSongGenres(songId int64) ([]model.Genre, error) {
+	if err := checkSongExists(s.songRepos, songId); err != nil {
+		return nil, err
+	}
+	return s.genreRepos.GetSongGenres(songId)
+}
+
+// AddSongGenre Назначение жанра песне
+func (s *GenreService) AddSongGenre(songId, genreId int64) error {
+	if err := checkSongExists(s.songRepos, songId); err != nil {
+		return err
+	}
+	if _, err := s.genreRepos.GetGenre(genreId); err != nil {
+		return err
+	}
+	return s.genreRepos.AddSongGenre(songId, genreId)
+}
+
+func (s *GenreService) DeleteSongGenre(songId, genreId int64) error {
+	return s.genreRepos.DeleteSongGenre(songId, genreId)
+}
+
+func (s *GenreService) prepareGenre(genre *model.Genre) error {
+	genre.Name = strings.TrimSpace(genre.Name)
+	genre.SearchKey = search.Key(genre.Name)
+	if genre.SearchKey == "" {
+		return fmt.Errorf("%w: genre name is required", model.ErrInvalidInput)
+	}
+	if genre.ParentId == 0 {
+		return nil
+	}
+	if genre.ParentId == genre.Id {
+		return fmt.Errorf("%w: genre %d cannot be its own parent", model.ErrInvalidInput, genre.Id)
+	}
+	_, err := s.genreRepos.GetGenre(genre.ParentId)
+	if errors.Is(err, model.ErrNotFound) {
+		return fmt.Errorf("%w: unknown parent genre %d", model.ErrInvalidInput, genre.ParentId)
+	}
+	return err
+}
+
+// genreTree Поджанры жанра parentId с вложенными поджанрами в порядке исходного списка.
+// Жанры, уже попавшие в дерево, пропускаются, поэтому цикл в данных не приводит к бесконечной рекурсии
+func genreTree(genres []model.Genre, parentId int64) []model.Genre {
+	return genreSubtree(genres, parentId, make(map[int64]bool, len(genres)))
+}
+
+func genreSubtree(genres []model.Genre, parentId int64, visited map[int64]bool) []model.Genre {
+	children := make([]model.Genre, 0)
+	for _, genre := range genres {
+		if genre.ParentId == parentId && !visited[genre.Id] {
+			visited[genre.Id] = true
+			genre.Children = genreSubtree(genres, genre.Id, visited)
+			children = append(children, genre)
+		}
+	}
+	return children
+}
@@ -0,0 +1,55 @@
+package service
+
+import (
+	"BestMusicLibrary/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func newGenreTestService() (*GenreService, *stubGenreRepository) {
+	repos := &stubGenreRepository{genres: []model.Genre{
+		{Id: 3, ParentId: 1, Name: "Post-punk"},
+		{Id: 1, Name: "Rock"},
+		{Id: 4, ParentId: 3, Name: "Coldwave"},
+		{Id: 2, Name: "Электроника"},
+	}}
+	return NewGenreService(repos, nil), repos
+}
+
+func TestGetGenreTree(t *testing.T) {
+	genreService, _ := newGenreTestService()
+
+	tree, err := genreService.GetGenreTree()
+	require.NoError(t, err)
+	require.Len(t, tree, 2)
+	assert.Equal(t, "Rock", tree[0].Name)
+	require.Len(t, tree[0].Children, 1)
+	assert.Equal(t, "Post-punk", tree[0].Children[0].Name)
+	assert.Equal(t, "Coldwave", tree[0].Children[0].Children[0].Name)
+	assert.Equal(t, "Электроника", tree[1].Name)
+	assert.Empty(t, tree[1].Children)
+}
+
+func TestUpdateGenreValidatesParent(t *testing.T) {
+	genreService, repos := newGenreTestService()
+
+	err := genreService.UpdateGenre(model.Genre{Id: 1, ParentId: 1, Name: "Rock"})
+	assert.ErrorIs(t, err, model.ErrInvalidInput)
+
+	err = genreService.UpdateGenre(model.Genre{Id: 3, ParentId: 9, Name: "Post-punk"})
+	assert.ErrorIs(t, err, model.ErrInvalidInput)
+
+	require.NoError(t, genreService.UpdateGenre(model.Genre{Id: 4, ParentId: 2, Name: " Coldwave "}))
+	assert.Equal(t, model.Genre{Id: 4, ParentId: 2, Name: "Coldwave", SearchKey: "coldwave"}, repos.genres[2])
+}
+
+func TestGetGenreTreeSkipsCycles(t *testing.T) {
+	tree := genreTree([]model.Genre{
+		{Id: 1, Name: "Rock"},
+		{Id: 2, ParentId: 3, Name: "Post-punk"},
+		{Id: 3, ParentId: 2, Name: "Coldwave"},
+	}, 0)
+	require.Len(t, tree, 1)
+	assert.Empty(t, tree[0].Children)
+}
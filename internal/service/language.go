@@ -0,0 +1,118 @@
+package service
+
+import (
+	"BestMusicLibrary/internal/langdetect"
+	"BestMusicLibrary/internal/model"
+	"fmt"
+	"golang.org/x/text/language"
+)
+
+// normalizeLanguageTag Приведение тега BCP 47 к каноническому виду, пустой тег означает, что язык неизвестен
+func normalizeLanguageTag(raw string) (string, error) {
+	if raw == "" {
+		return "", nil
+	}
+	tag, err := language.Parse(raw)
+	if err != nil {
+		return "", fmt.Errorf("%w: invalid language tag %q: %s", model.ErrInvalidInput, raw, err)
+	}
+	return tag.String(), nil
+}
+
+// detectSongLanguage Определение языка оригинала по тексту, если клиент его не указал
+func detectSongLanguage(song *model.Song) {
+	if song.Language != "" {
+		return
+	}
+	song.Language = langdetect.Detect(versesToText(expandVerses(song.Verses, song.Arrangement)))
+}
+
+// selectLanguage Выбор версии текста по предпочтениям клиента среди оригинала и переводов.
+// Без предпочтений или без подходящего перевода возвращается язык оригинала
+func (s *SongService) selectLanguage(id int64, preferred []language.Tag) (string, error) {
+	original, translations, err := s.songRepos.GetSongLanguages(id)
+	if err != nil {
+		return "", err
+	}
+	if len(preferred) == 0 || len(translations) == 0 {
+		return original, nil
+	}
+
+	candidates := make([]string, 0, len(translations)+1)
+	supported := make([]language.Tag, 0, len(translations)+1)
+	candidates = append(candidates, original)
+	if original == "" {
+		supported = append(supported, language.Und)
+	} else {
+		supported = append(supported, language.Make(original))
+	}
+	for _, translation := range translations {
+		candidates = append(candidates, translation)
+		supported = append(supported, language.Make(translation))
+	}
+
+	_, index, confidence := language.NewMatcher(supported).Match(preferred...)
+	if confidence == language.No {
+		return original, nil
+	}
+	return candidates[index], nil
+}
+
+// SetTranslation Сохранение перевода текста песни. Куплеты перевода сопоставляются с куплетами оригинала по порядку:
+// перевод может повторять развернутый текст или содержать каждый уникальный куплет один раз.
+// Без указанного языка он определяется по тексту перевода. Пустой текст удаляет перевод
+func (s *SongService) SetTranslation(id int64, rawLanguage, text string) error {
+	tag, err := normalizeLanguageTag(rawLanguage)
+	if err != nil {
+		return err
+	}
+	if tag == "" {
+		tag = langdetect.Detect(text)
+	}
+	if tag == "" {
+		return fmt.Errorf("%w: translation language is not given and could not be detected", model.ErrInvalidInput)
+	}
+
+	song, err := s.songRepos.GetSong(id)
+	if err != nil {
+		return err
+	}
+	if tag == song.Language {
+		return fmt.Errorf("%w: %s is the original language of song %d", model.ErrInvalidInput, tag, id)
+	}
+
+	translated := textToVerses(text)
+	if len(translated) == 0 {
+		return s.songRepos.ReplaceTranslation(id, tag, nil)
+	}
+
+	numbers := song.Arrangement
+	if numbers == nil {
+		numbers = make([]int, len(song.Verses))
+		for index := range numbers {
+			numbers[index] = index
+		}
+	}
+
+	verses := make([]model.Verse, 0, len(song.Verses))
+	switch len(translated) {
+	case len(song.Verses):
+		for index, verse := range translated {
+			verses = append(verses, model.Verse{VerseNumber: index, Text: verse.Text})
+		}
+	case len(numbers):
+		seen := make(map[int]bool, len(song.Verses))
+		for position, verse := range translated {
+			if seen[numbers[position]] {
+				continue
+			}
+			seen[numbers[position]] = true
+			verses = append(verses, model.Verse{VerseNumber: numbers[position], Text: verse.Text})
+		}
+	default:
+		return fmt.Errorf("%w: translation has %d verses, song %d has %d verses with %d unique", model.ErrInvalidInput,
+			len(translated), id, len(numbers), len(song.Verses))
+	}
+
+	return s.songRepos.ReplaceTranslation(id, tag, verses)
+}
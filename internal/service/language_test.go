@@ -0,0 +1,101 @@
+package service
+
+import (
+	"BestMusicLibrary/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/language"
+	"testing"
+)
+
+func newTranslationTestService() (*SongService, *stubSongRepository) {
+	verses, arrangement := parseLyrics("[Chorus]\nПоем вместе\n\nПервый куплет\n\n[Chorus]\n\n[Verse 2]\nВторой куплет")
+	repos := &stubSongRepository{
+		song:         model.Song{Id: 1, Language: "ru", Verses: verses, Arrangement: arrangement},
+		translations: make(map[string][]model.Verse),
+	}
+	return NewSongService(repos, SongServiceOptions{}), repos
+}
+
+func TestNormalizeLanguageTag(t *testing.T) {
+	tag, err := normalizeLanguageTag("EN-gb")
+	require.NoError(t, err)
+	assert.Equal(t, "en-GB", tag)
+
+	tag, err = normalizeLanguageTag("")
+	require.NoError(t, err)
+	assert.Empty(t, tag)
+
+	_, err = normalizeLanguageTag("not a tag")
+	assert.ErrorIs(t, err, model.ErrInvalidInput)
+}
+
+func TestSetTranslationExpanded(t *testing.T) {
+	service, repos := newTranslationTestService()
+
+	err := service.SetTranslation(1, "en", "Sing along\n\nFirst verse\n\nSing along again\n\nSecond verse")
+	require.NoError(t, err)
+	assert.Equal(t, []model.Verse{
+		{VerseNumber: 0, Text: "Sing along"},
+		{VerseNumber: 1, Text: "First verse"},
+		{VerseNumber: 2, Text: "Second verse"},
+	}, repos.translations["en"])
+}
+
+func TestSetTranslationCompact(t *testing.T) {
+	service, repos := newTranslationTestService()
+
+	err := service.SetTranslation(1, "uk", "Співаємо разом\n\nПерший куплет\n\nДругий куплет")
+	require.NoError(t, err)
+	assert.Len(t, repos.translations["uk"], 3)
+
+	require.NoError(t, service.SetTranslation(1, "uk", ""))
+	assert.NotContains(t, repos.translations, "uk")
+}
+
+func TestSetTranslationInvalid(t *testing.T) {
+	service, _ := newTranslationTestService()
+
+	assert.ErrorIs(t, service.SetTranslation(1, "en", "Only one verse"), model.ErrInvalidInput)
+	assert.ErrorIs(t, service.SetTranslation(1, "ru", "a\n\nb\n\nc"), model.ErrInvalidInput)
+	assert.ErrorIs(t, service.SetTranslation(1, "", "a\n\nb\n\nc"), model.ErrInvalidInput)
+	assert.ErrorIs(t, service.SetTranslation(2, "en", "a\n\nb\n\nc"), model.ErrNotFound)
+}
+
+func TestSelectLanguage(t *testing.T) {
+	service, repos := newTranslationTestService()
+	repos.translations["en"] = []model.Verse{{VerseNumber: 0, Text: "Sing along"}}
+
+	preferred, _, err := language.ParseAcceptLanguage("en-GB,ru;q=0.5")
+	require.NoError(t, err)
+	lang, err := service.selectLanguage(1, preferred)
+	require.NoError(t, err)
+	assert.Equal(t, "en", lang)
+
+	lang, err = service.selectLanguage(1, []language.Tag{language.German})
+	require.NoError(t, err)
+	assert.Equal(t, "ru", lang)
+
+	lang, err = service.selectLanguage(1, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "ru", lang)
+}
+
+func TestSetTranslationDetectsLanguage(t *testing.T) {
+	service, repos := newTranslationTestService()
+
+	err := service.SetTranslation(1, "", "We are singing together tonight\n\nThe first verse of the song\n\nThe second verse of the song")
+	require.NoError(t, err)
+	assert.Contains(t, repos.translations, "en")
+}
+
+func TestDetectSongLanguage(t *testing.T) {
+	verses, arrangement := parseLyrics("Группа крови на рукаве\nМой порядковый номер на рукаве\n\nПожелай мне удачи в бою")
+	song := model.Song{Verses: verses, Arrangement: arrangement}
+	detectSongLanguage(&song)
+	assert.Equal(t, "ru", song.Language)
+
+	song.Language = "uk"
+	detectSongLanguage(&song)
+	assert.Equal(t, "uk", song.Language, "the language given by the client should be kept")
+}
@@ -0,0 +1,321 @@
+package service
+
+import (
+	"BestMusicLibrary/internal/model"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// LyricsFormat Формат выгрузки синхронизированного текста
+type LyricsFormat string
+
+const (
+	LyricsFormatLRC    LyricsFormat = "lrc"
+	LyricsFormatWebVTT LyricsFormat = "vtt"
+)
+
+// vttEscaper Экранирование текста реплики WebVTT
+var vttEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+
+// defaultCueDurationMs Длительность последней строки, если конец песни неизвестен
+const defaultCueDurationMs = 5000
+
+// lrcLine Строка LRC-файла с одним временем начала. Строка без текста отмечает конец предыдущей
+type lrcLine struct {
+	sourceLine int
+	startMs    int64
+	endMs      int64
+	text       string
+	words      []model.TimedWord
+}
+
+// parseLRC Разбор LRC, в том числе расширенного формата с временем слов вида <mm:ss.xx>.
+// Строка с несколькими метками времени повторяется для каждой из них, и тогда строки упорядочиваются по времени,
+// иначе время строк обязано возрастать. Совпадающее время строк и слова вне своей строки считаются перекрытием
+func parseLRC(text string) ([]lrcLine, error) {
+	lines := make([]lrcLine, 0)
+	var offsetMs, lengthMs int64
+	repeated := false
+
+	for index, rawLine := range strings.Split(lineEndingReplacer.Replace(text), "\n") {
+		sourceLine := index + 1
+		rest := strings.TrimSpace(rawLine)
+		if rest == "" {
+			continue
+		}
+
+		starts := make([]int64, 0, 1)
+		for strings.HasPrefix(rest, "[") {
+			end := strings.Index(rest, "]")
+			if end < 0 {
+				break
+			}
+			tag := rest[1:end]
+			if start, ok := parseLRCTime(tag); ok {
+				starts = append(starts, start)
+				rest = rest[end+1:]
+				continue
+			}
+			if len(starts) > 0 {
+				break
+			}
+
+			key, value, found := strings.Cut(tag, ":")
+			if !found {
+				break
+			}
+			var err error
+			switch strings.ToLower(strings.TrimSpace(key)) {
+			case "offset":
+				offsetMs, err = strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+			case "length":
+				var ok bool
+				if lengthMs, ok = parseLRCTime(strings.TrimSpace(value)); !ok {
+					err = fmt.Errorf("invalid length %q", value)
+				}
+			}
+			if err != nil {
+				return nil, fmt.Errorf("%w: line %d: %s", model.ErrInvalidInput, sourceLine, err)
+			}
+			rest = strings.TrimSpace(rest[end+1:])
+		}
+
+		if len(starts) == 0 {
+			if rest == "" {
+				continue
+			}
+			return nil, fmt.Errorf("%w: line %d has no timestamp", model.ErrInvalidInput, sourceLine)
+		}
+
+		lineText, words := parseLRCWords(rest, starts[0])
+		if len(starts) > 1 {
+			if len(words) > 0 {
+				return nil, fmt.Errorf("%w: line %d has word timings and several timestamps", model.ErrInvalidInput, sourceLine)
+			}
+			repeated = true
+		}
+		for _, start := range starts {
+			lines = append(lines, lrcLine{sourceLine: sourceLine, startMs: start, text: lineText, words: words})
+		}
+	}
+
+	// Положительное смещение означает, что текст должен появляться раньше
+	for index := range lines {
+		lines[index].startMs -= offsetMs
+		for wordIndex := range lines[index].words {
+			lines[index].words[wordIndex].StartMs -= offsetMs
+		}
+		if lines[index].startMs < 0 {
+			return nil, fmt.Errorf("%w: line %d starts before the beginning of the song", model.ErrInvalidInput, lines[index].sourceLine)
+		}
+	}
+	if lengthMs > 0 {
+		lengthMs -= offsetMs
+	}
+
+	if repeated {
+		sort.SliceStable(lines, func(i, j int) bool {
+			return lines[i].startMs < lines[j].startMs
+		})
+	}
+
+	for index := 1; index < len(lines); index++ {
+		previous, current := lines[index-1], lines[index]
+		if current.startMs == previous.startMs {
+			return nil, fmt.Errorf("%w: lines %d and %d overlap at %s", model.ErrInvalidInput,
+				previous.sourceLine, current.sourceLine, formatLRCTime(current.startMs))
+		}
+		if current.startMs < previous.startMs {
+			return nil, fmt.Errorf("%w: line %d at %s is earlier than line %d at %s", model.ErrInvalidInput,
+				current.sourceLine, formatLRCTime(current.startMs), previous.sourceLine, formatLRCTime(previous.startMs))
+		}
+	}
+
+	timed := make([]lrcLine, 0, len(lines))
+	for index, line := range lines {
+		if line.text == "" && len(line.words) == 0 {
+			continue
+		}
+		if index+1 < len(lines) {
+			line.endMs = lines[index+1].startMs
+		} else if lengthMs > line.startMs {
+			line.endMs = lengthMs
+		}
+		if err := validateLRCWords(line); err != nil {
+			return nil, fmt.Errorf("%w: line %d: %s", model.ErrInvalidInput, line.sourceLine, err)
+		}
+		timed = append(timed, line)
+	}
+
+	return timed, nil
+}
+
+// parseLRCWords Разбор времени слов. Текст до первой метки слова начинается вместе со строкой
+func parseLRCWords(rest string, lineStartMs int64) (string, []model.TimedWord) {
+	if !strings.Contains(rest, "<") {
+		return strings.TrimSpace(rest), nil
+	}
+
+	words := make([]model.TimedWord, 0)
+	var lineText strings.Builder
+	wordStart := lineStartMs
+	var word strings.Builder
+	hasWord := false
+
+	for rest != "" {
+		open := strings.Index(rest, "<")
+		if open < 0 {
+			word.WriteString(rest)
+			break
+		}
+		closing := strings.Index(rest[open:], ">")
+		if closing < 0 {
+			word.WriteString(rest)
+			break
+		}
+		start, ok := parseLRCTime(rest[open+1 : open+closing])
+		if !ok {
+			word.WriteString(rest[:open+closing+1])
+			rest = rest[open+closing+1:]
+			continue
+		}
+
+		word.WriteString(rest[:open])
+		if hasWord || word.Len() > 0 {
+			words = append(words, model.TimedWord{StartMs: wordStart, Text: word.String()})
+			lineText.WriteString(word.String())
+		}
+		word.Reset()
+		wordStart = start
+		hasWord = true
+		rest = rest[open+closing+1:]
+	}
+	if !hasWord {
+		return strings.TrimSpace(word.String()), nil
+	}
+	words = append(words, model.TimedWord{StartMs: wordStart, Text: word.String()})
+	lineText.WriteString(word.String())
+
+	return strings.TrimSpace(lineText.String()), words
+}
+
+func validateLRCWords(line lrcLine) error {
+	for index, word := range line.words {
+		if word.StartMs < line.startMs {
+			return fmt.Errorf("word %q at %s starts before its line at %s", word.Text, formatLRCTime(word.StartMs), formatLRCTime(line.startMs))
+		}
+		if index > 0 && word.StartMs <= line.words[index-1].StartMs {
+			return fmt.Errorf("word %q at %s is not later than the previous word", word.Text, formatLRCTime(word.StartMs))
+		}
+		if line.endMs == 0 {
+			continue
+		}
+		if word.StartMs > line.endMs || (word.Text != "" && word.StartMs == line.endMs) {
+			return fmt.Errorf("word %q at %s overlaps the next line at %s", word.Text, formatLRCTime(word.StartMs), formatLRCTime(line.endMs))
+		}
+	}
+	return nil
+}
+
+// parseLRCTime Разбор метки mm:ss, mm:ss.x, mm:ss.xx или mm:ss.xxx, дробная часть может отделяться двоеточием
+func parseLRCTime(tag string) (int64, bool) {
+	rawMinutes, rawSeconds, found := strings.Cut(strings.TrimSpace(tag), ":")
+	if !found {
+		return 0, false
+	}
+	rawSeconds, rawFraction, hasFraction := strings.Cut(rawSeconds, ".")
+	if !hasFraction {
+		rawSeconds, rawFraction, hasFraction = strings.Cut(rawSeconds, ":")
+	}
+
+	minutes, err := strconv.ParseUint(rawMinutes, 10, 32)
+	if err != nil {
+		return 0, false
+	}
+	seconds, err := strconv.ParseUint(rawSeconds, 10, 8)
+	if err != nil || len(rawSeconds) != 2 || seconds >= 60 {
+		return 0, false
+	}
+
+	var fractionMs uint64
+	if hasFraction {
+		if len(rawFraction) == 0 || len(rawFraction) > 3 {
+			return 0, false
+		}
+		fraction, err := strconv.ParseUint(rawFraction, 10, 16)
+		if err != nil {
+			return 0, false
+		}
+		for digits := len(rawFraction); digits < 3; digits++ {
+			fraction *= 10
+		}
+		fractionMs = fraction
+	}
+
+	return int64(minutes*60_000 + seconds*1000 + fractionMs), true
+}
+
+// formatLRCTime Метка времени LRC вида mm:ss.xx
+func formatLRCTime(ms int64) string {
+	return fmt.Sprintf("%02d:%02d.%02d", ms/60_000, ms/1000%60, ms%1000/10)
+}
+
+// formatVTTTime Метка времени WebVTT вида hh:mm:ss.mmm
+func formatVTTTime(ms int64) string {
+	return fmt.Sprintf("%02d:%02d:%02d.%03d", ms/3_600_000, ms/60_000%60, ms/1000%60, ms%1000)
+}
+
+// formatLRC Выгрузка в расширенный LRC. Конец строки записывается отдельной меткой, если после него пауза
+func formatLRC(song model.Song, lines []model.TimedLine) string {
+	var builder strings.Builder
+	fmt.Fprintf(&builder, "[ar:%s]\n[ti:%s]\n", song.Group, song.Name)
+
+	for index, line := range lines {
+		builder.WriteString("[" + formatLRCTime(line.StartMs) + "]")
+		if len(line.Words) == 0 {
+			builder.WriteString(line.Text)
+		}
+		for _, word := range line.Words {
+			builder.WriteString("<" + formatLRCTime(word.StartMs) + ">" + word.Text)
+		}
+		builder.WriteString("\n")
+
+		if line.EndMs != 0 && (index+1 == len(lines) || lines[index+1].StartMs != line.EndMs) {
+			builder.WriteString("[" + formatLRCTime(line.EndMs) + "]\n")
+		}
+	}
+
+	return builder.String()
+}
+
+// formatWebVTT Выгрузка в субтитры WebVTT. Время слов передается метками внутри реплики
+func formatWebVTT(lines []model.TimedLine) string {
+	var builder strings.Builder
+	builder.WriteString("WEBVTT\n")
+
+	for index, line := range lines {
+		end := line.EndMs
+		if end == 0 && index+1 < len(lines) {
+			end = lines[index+1].StartMs
+		}
+		if end == 0 {
+			end = line.StartMs + defaultCueDurationMs
+		}
+
+		fmt.Fprintf(&builder, "\n%d\n%s --> %s\n", index+1, formatVTTTime(line.StartMs), formatVTTTime(end))
+		if len(line.Words) == 0 {
+			builder.WriteString(vttEscaper.Replace(line.Text))
+		}
+		for _, word := range line.Words {
+			if word.StartMs > line.StartMs && word.Text != "" {
+				builder.WriteString("<" + formatVTTTime(word.StartMs) + ">")
+			}
+			builder.WriteString(vttEscaper.Replace(word.Text))
+		}
+		builder.WriteString("\n")
+	}
+
+	return builder.String()
+}
@@ -0,0 +1,111 @@
+package service
+
+import (
+	"BestMusicLibrary/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestParseLRCTime(t *testing.T) {
+	cases := map[string]int64{
+		"00:12":     12_000,
+		"00:12.5":   12_500,
+		"01:02.03":  62_030,
+		"01:02.034": 62_034,
+		"01:02:03":  62_030,
+		"120:00.00": 7_200_000,
+	}
+	for tag, expected := range cases {
+		ms, ok := parseLRCTime(tag)
+		assert.True(t, ok, tag)
+		assert.Equal(t, expected, ms, tag)
+	}
+
+	for _, tag := range []string{"ar:Muse", "00:60.00", "0:1", "00:12.", "00:12.1234", "-1:00.00"} {
+		_, ok := parseLRCTime(tag)
+		assert.False(t, ok, tag)
+	}
+}
+
+func TestParseLRC(t *testing.T) {
+	lrc := "[ar:Muse]\r\n[ti:Uprising]\r\n[offset:+500]\r\n\r\n[00:12.50]Paranoia is in bloom\r\n" +
+		"[00:16.00]The PR transmissions will resume\r\n[00:20.00]\r\n[00:25.00]They'll try to push drugs"
+	lines, err := parseLRC(lrc)
+	require.NoError(t, err)
+	assert.Equal(t, []lrcLine{
+		{sourceLine: 5, startMs: 12_000, endMs: 15_500, text: "Paranoia is in bloom"},
+		{sourceLine: 6, startMs: 15_500, endMs: 19_500, text: "The PR transmissions will resume"},
+		{sourceLine: 8, startMs: 24_500, text: "They'll try to push drugs"},
+	}, lines)
+}
+
+func TestParseLRCEnhancedWords(t *testing.T) {
+	lines, err := parseLRC("[length:00:10.00]\n[00:01.00]<00:01.00>Hello <00:01.50>world<00:02.00>\n[00:03.00]Intro <00:03.40>line")
+	require.NoError(t, err)
+	assert.Equal(t, []lrcLine{
+		{sourceLine: 2, startMs: 1000, endMs: 3000, text: "Hello world", words: []model.TimedWord{
+			{StartMs: 1000, Text: "Hello "}, {StartMs: 1500, Text: "world"}, {StartMs: 2000, Text: ""},
+		}},
+		{sourceLine: 3, startMs: 3000, endMs: 10_000, text: "Intro line", words: []model.TimedWord{
+			{StartMs: 3000, Text: "Intro "}, {StartMs: 3400, Text: "line"},
+		}},
+	}, lines)
+}
+
+func TestParseLRCRepeatedLines(t *testing.T) {
+	lines, err := parseLRC("[00:10.00][00:30.00]Chorus\n[00:20.00]Verse")
+	require.NoError(t, err)
+	assert.Equal(t, []lrcLine{
+		{sourceLine: 1, startMs: 10_000, endMs: 20_000, text: "Chorus"},
+		{sourceLine: 2, startMs: 20_000, endMs: 30_000, text: "Verse"},
+		{sourceLine: 1, startMs: 30_000, text: "Chorus"},
+	}, lines)
+}
+
+func TestParseLRCRejectsInvalidTimings(t *testing.T) {
+	cases := map[string]string{
+		"non-monotonic":         "[00:20.00]Second\n[00:10.00]First",
+		"overlapping lines":     "[00:10.00]First\n[00:10.00]Second",
+		"repeated overlap":      "[00:10.00][00:20.00]Chorus\n[00:20.00]Verse",
+		"word before line":      "[00:10.00]<00:09.00>Early",
+		"non-monotonic words":   "[00:10.00]<00:11.00>One <00:10.50>two",
+		"word overlaps next":    "[00:10.00]<00:10.00>One <00:12.00>two\n[00:11.00]Next",
+		"missing timestamp":     "[00:10.00]First\nNo tag here",
+		"negative after offset": "[offset:2000]\n[00:01.00]Too early",
+	}
+	for name, lrc := range cases {
+		_, err := parseLRC(lrc)
+		assert.ErrorIs(t, err, model.ErrInvalidInput, name)
+	}
+}
+
+func TestFormatLRC(t *testing.T) {
+	lines := []model.TimedLine{
+		{StartMs: 12_000, EndMs: 15_500, Text: "Paranoia is in bloom"},
+		{StartMs: 15_500, EndMs: 19_500, Text: "Hello world", Words: []model.TimedWord{{StartMs: 15_500, Text: "Hello "}, {StartMs: 16_000, Text: "world"}}},
+		{StartMs: 24_500, Text: "They'll try to push drugs"},
+	}
+	lrc := formatLRC(model.Song{Group: "Muse", Name: "Uprising"}, lines)
+	assert.Equal(t, "[ar:Muse]\n[ti:Uprising]\n[00:12.00]Paranoia is in bloom\n[00:15.50]<00:15.50>Hello <00:16.00>world\n"+
+		"[00:19.50]\n[00:24.50]They'll try to push drugs\n", lrc)
+
+	parsed, err := parseLRC(lrc)
+	require.NoError(t, err)
+	require.Len(t, parsed, len(lines))
+	for index, line := range parsed {
+		assert.Equal(t, lines[index].StartMs, line.startMs)
+		assert.Equal(t, lines[index].EndMs, line.endMs)
+		assert.Equal(t, lines[index].Text, line.text)
+		assert.Equal(t, lines[index].Words, line.words)
+	}
+}
+
+func TestFormatWebVTT(t *testing.T) {
+	lines := []model.TimedLine{
+		{StartMs: 12_000, EndMs: 15_500, Text: "Rock & <roll>"},
+		{StartMs: 3_615_500, Text: "Hello world", Words: []model.TimedWord{{StartMs: 3_615_500, Text: "Hello "}, {StartMs: 3_616_000, Text: "world"}}},
+	}
+	assert.Equal(t, "WEBVTT\n\n1\n00:00:12.000 --> 00:00:15.500\nRock &amp; &lt;roll&gt;\n\n"+
+		"2\n01:00:15.500 --> 01:00:20.500\nHello <01:00:16.000>world\n", formatWebVTT(lines))
+}
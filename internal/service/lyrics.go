@@ -0,0 +1,233 @@
+package service
+
+import (
+	"BestMusicLibrary/internal/model"
+	"BestMusicLibrary/internal/normalize"
+	"strings"
+	"unicode"
+)
+
+type sectionKeyword struct {
+	prefix      string
+	sectionType string
+}
+
+// sectionKeywords Ключевые слова заголовков разделов. Более длинные префиксы идут раньше пересекающихся коротких
+var sectionKeywords = []sectionKeyword{
+	{"prechorus", model.SectionPreChorus},
+	{"postchorus", model.SectionPostChorus},
+	{"chorus", model.SectionChorus},
+	{"refrain", model.SectionChorus},
+	{"verse", model.SectionVerse},
+	{"bridge", model.SectionBridge},
+	{"intro", model.SectionIntro},
+	{"outro", model.SectionOutro},
+	{"hook", model.SectionHook},
+	{"interlude", model.SectionInterlude},
+	{"предприпев", model.SectionPreChorus},
+	{"припев", model.SectionChorus},
+	{"куплет", model.SectionVerse},
+	{"бридж", model.SectionBridge},
+	{"переход", model.SectionBridge},
+	{"вступление", model.SectionIntro},
+	{"интро", model.SectionIntro},
+	{"концовка", model.SectionOutro},
+	{"аутро", model.SectionOutro},
+	{"проигрыш", model.SectionInterlude},
+}
+
+var lineEndingReplacer = strings.NewReplacer("\r\n", "\n", "\r", "\n")
+
+type lyricSection struct {
+	sectionType string
+	label       string
+}
+
+// textToVerses Разбор текста песни на куплеты. Куплеты разделяются пустыми строками, заголовки разделов
+// вида [Chorus], [Verse 2] или "Припев:" не попадают в текст и задают тип и метку следующего куплета.
+// Переводы строк \r\n и \r приводятся к \n, поэтому строки куплета не содержат \r
+func textToVerses(text string) []model.Verse {
+	text = lineEndingReplacer.Replace(text)
+	cleanedVerses := make([]model.Verse, 0)
+	var section lyricSection
+	stanza := make([]string, 0)
+
+	flush := func() bool {
+		cleanedVerse := strings.TrimSpace(strings.Join(stanza, "\n"))
+		stanza = stanza[:0]
+		if cleanedVerse == "" {
+			return false
+		}
+		cleanedVerses = append(cleanedVerses, model.Verse{
+			VerseNumber:  len(cleanedVerses),
+			Text:         cleanedVerse,
+			SectionType:  section.sectionType,
+			SectionLabel: section.label,
+		})
+		section = lyricSection{}
+		return true
+	}
+
+	// repeat Заголовок без текста, за которым сразу идет другой заголовок или конец песни,
+	// повторяет ранее встреченный раздел
+	repeat := func() {
+		if verse, ok := repeatedSection(cleanedVerses, section); ok {
+			verse.VerseNumber = len(cleanedVerses)
+			cleanedVerses = append(cleanedVerses, verse)
+		}
+		section = lyricSection{}
+	}
+
+	for _, line := range strings.Split(text, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if header, ok := parseSectionHeader(trimmed); ok {
+			if !flush() {
+				repeat()
+			}
+			section = header
+			continue
+		}
+		if trimmed == "" {
+			flush()
+			continue
+		}
+		stanza = append(stanza, line)
+	}
+	if !flush() {
+		repeat()
+	}
+
+	return cleanedVerses
+}
+
+// repeatedSection Поиск последнего куплета с той же меткой, а для припевов и хуков с тем же типом раздела
+func repeatedSection(verses []model.Verse, section lyricSection) (model.Verse, bool) {
+	if section.label == "" {
+		return model.Verse{}, false
+	}
+	for index := len(verses) - 1; index >= 0; index-- {
+		if strings.EqualFold(verses[index].SectionLabel, section.label) {
+			return verses[index], true
+		}
+	}
+
+	switch section.sectionType {
+	case model.SectionChorus, model.SectionPreChorus, model.SectionPostChorus, model.SectionHook:
+		for index := len(verses) - 1; index >= 0; index-- {
+			if verses[index].SectionType == section.sectionType {
+				return verses[index], true
+			}
+		}
+	}
+	return model.Verse{}, false
+}
+
+// arrangeVerses Объединение одинаковых куплетов. Куплеты считаются одинаковыми, если совпадают без учета регистра,
+// пунктуации и пробелов, а их типы разделов не противоречат друг другу. Возвращаются уникальные куплеты
+// в порядке первого появления и порядок исполнения в виде их номеров
+func arrangeVerses(verses []model.Verse) ([]model.Verse, []int) {
+	unique := make([]model.Verse, 0, len(verses))
+	keys := make([]string, 0, len(verses))
+	arrangement := make([]int, 0, len(verses))
+
+	for _, verse := range verses {
+		key := stanzaKey(verse.Text)
+		number := -1
+		for index, uniqueVerse := range unique {
+			if keys[index] == key && compatibleSections(uniqueVerse.SectionType, verse.SectionType) {
+				number = index
+				break
+			}
+		}
+		if number < 0 {
+			number = len(unique)
+			verse.VerseNumber = number
+			unique = append(unique, verse)
+			keys = append(keys, key)
+		}
+		arrangement = append(arrangement, number)
+	}
+
+	return unique, arrangement
+}
+
+// expandVerses Развернутый текст песни: куплеты в порядке исполнения, номер куплета равен позиции
+func expandVerses(verses []model.Verse, arrangement []int) []model.Verse {
+	if arrangement == nil {
+		return verses
+	}
+
+	expanded := make([]model.Verse, 0, len(arrangement))
+	for position, number := range arrangement {
+		if number < 0 || number >= len(verses) {
+			continue
+		}
+		verse := verses[number]
+		verse.VerseNumber = position
+		verse.Positions = nil
+		expanded = append(expanded, verse)
+	}
+	return expanded
+}
+
+// parseLyrics Разбор текста песни на уникальные куплеты и порядок их исполнения
+func parseLyrics(text string) ([]model.Verse, []int) {
+	return arrangeVerses(textToVerses(text))
+}
+
+// stanzaKey Ключ сравнения куплетов и строк: слова без регистра и пунктуации. Текст без букв и цифр, например
+// "♪ ♪" или "...", сравнивается как есть с точностью до пробелов, иначе разные такие куплеты совпали бы
+func stanzaKey(text string) string {
+	words := strings.FieldsFunc(strings.ToLower(normalize.Text(text)), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	if len(words) == 0 {
+		return strings.Join(strings.Fields(text), " ")
+	}
+	return strings.Join(words, " ")
+}
+
+func compatibleSections(first, second string) bool {
+	return first == "" || second == "" || first == second
+}
+
+// parseSectionHeader Распознавание строки-заголовка: любая строка в квадратных скобках
+// или ключевое слово раздела с номером и двоеточием в конце, например "Припев:" или "Verse 2:"
+func parseSectionHeader(line string) (lyricSection, bool) {
+	if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") && len(line) > 2 {
+		label := strings.TrimSpace(line[1 : len(line)-1])
+		if label == "" {
+			return lyricSection{}, false
+		}
+		sectionType, _ := sectionTypeOf(label)
+		return lyricSection{sectionType: sectionType, label: label}, true
+	}
+
+	if strings.HasSuffix(line, ":") {
+		label := strings.TrimSpace(strings.TrimSuffix(line, ":"))
+		if sectionType, exact := sectionTypeOf(label); exact {
+			return lyricSection{sectionType: sectionType, label: label}, true
+		}
+	}
+
+	return lyricSection{}, false
+}
+
+// sectionTypeOf Тип раздела по метке: "Pre-Chorus" и "Pre chorus" дают pre-chorus, "Куплет 2: Артист" дает verse.
+// exact сообщает, что метка состоит только из ключевого слова и номера
+func sectionTypeOf(label string) (sectionType string, exact bool) {
+	name, _, _ := strings.Cut(strings.ToLower(label), ":")
+	key := strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) {
+			return r
+		}
+		return -1
+	}, name)
+
+	for _, keyword := range sectionKeywords {
+		if strings.HasPrefix(key, keyword.prefix) {
+			return keyword.sectionType, key == keyword.prefix
+		}
+	}
+	return model.SectionOther, false
+}
@@ -0,0 +1,116 @@
+package service
+
+import (
+	"BestMusicLibrary/internal/model"
+	"BestMusicLibrary/internal/repository"
+	"fmt"
+	"strings"
+)
+
+type PlaylistService struct {
+	playlistRepos repository.Playlist
+}
+
+func NewPlaylistService(repos repository.Playlist) *PlaylistService {
+	return &PlaylistService{playlistRepos: repos}
+}
+
+// GetPlaylists Открытые плейлисты и плейлисты пользователя caller с пагинацией, с владельцем — только его плейлисты
+func (s *PlaylistService) GetPlaylists(filter model.PlaylistFilter, rawPage, rawLimit int) ([]model.Playlist, error) {
+	page, limit := handlePagingData(rawPage, rawLimit)
+	filter.Owner = strings.TrimSpace(filter.Owner)
+	filter.Caller = strings.TrimSpace(filter.Caller)
+	return s.playlistRepos.GetPlaylists(filter, page, limit)
+}
+
+// GetPlaylist Плейлист с записями по порядку. Закрытый плейлист другого пользователя считается ненайденным
+func (s *PlaylistService) GetPlaylist(id int64, caller string) (model.Playlist, error) {
+	playlist, err := s.playlistRepos.GetPlaylist(id)
+	if err != nil {
+		return model.Playlist{}, err
+	}
+	if !canReadPlaylist(playlist.Owner, playlist.Public, caller) {
+		return model.Playlist{}, fmt.Errorf("playlist %d: %w", id, model.ErrNotFound)
+	}
+	return playlist, nil
+}
+
+func (s *PlaylistService) AddPlaylist(playlist model.Playlist) (int64, error) {
+	if err := preparePlaylist(&playlist); err != nil {
+		return 0, err
+	}
+	return s.playlistRepos.AddPlaylist(playlist)
+}
+
+// UpdatePlaylist Изменение данных плейлиста без записей. Изменять плейлист может только владелец caller,
+// для остальных пользователей плейлист не найден
+func (s *PlaylistService) UpdatePlaylist(playlist model.Playlist, caller string) error {
+	if err := preparePlaylist(&playlist); err != nil {
+		return err
+	}
+	return s.playlistRepos.UpdatePlaylist(playlist, strings.TrimSpace(caller))
+}
+
+// DeletePlaylist Удаление плейлиста владельцем caller, песни плейлиста остаются в библиотеке
+func (s *PlaylistService) DeletePlaylist(id int64, caller string) error {
+	return s.playlistRepos.DeletePlaylist(id, strings.TrimSpace(caller))
+}
+
+// AddPlaylistEntry Добавление песни в плейлист владельца caller после записи afterEntryId: nil добавляет песню
+// в конец, ноль — в начало плейлиста
+func (s *PlaylistService) AddPlaylistEntry(playlistId int64, caller string, songId int64, afterEntryId *int64) (int64, error) {
+	if songId <= 0 {
+		return 0, fmt.Errorf("%w: song_id is required", model.ErrInvalidInput)
+	}
+	if err := validateAfterEntry(afterEntryId); err != nil {
+		return 0, err
+	}
+	return s.playlistRepos.AddPlaylistEntry(playlistId, strings.TrimSpace(caller), songId, afterEntryId)
+}
+
+// MovePlaylistEntry Перемещение записи плейлиста владельца caller после другой записи, в начало или в конец
+// плейлиста. Перемещение записи после самой себя ничего не меняет
+func (s *PlaylistService) MovePlaylistEntry(playlistId int64, caller string, entryId int64, afterEntryId *int64) error {
+	if err := validateAfterEntry(afterEntryId); err != nil {
+		return err
+	}
+	if afterEntryId != nil && *afterEntryId == entryId {
+		return nil
+	}
+	return s.playlistRepos.MovePlaylistEntry(playlistId, strings.TrimSpace(caller), entryId, afterEntryId)
+}
+
+// DeletePlaylistEntry Удаление записи из плейлиста владельца caller
+func (s *PlaylistService) DeletePlaylistEntry(playlistId int64, caller string, entryId int64) error {
+	return s.playlistRepos.DeletePlaylistEntry(playlistId, strings.TrimSpace(caller), entryId)
+}
+
+func preparePlaylist(playlist *model.Playlist) error {
+	return preparePlaylistFields(&playlist.Name, &playlist.Description, &playlist.Owner)
+}
+
+// preparePlaylistFields Проверка и очистка общих полей обычного и умного плейлиста, название и владелец обязательны
+func preparePlaylistFields(name, description, owner *string) error {
+	*name = strings.TrimSpace(*name)
+	*description = strings.TrimSpace(*description)
+	*owner = strings.TrimSpace(*owner)
+	if *name == "" {
+		return fmt.Errorf("%w: playlist name is required", model.ErrInvalidInput)
+	}
+	if *owner == "" {
+		return fmt.Errorf("%w: playlist owner is required", model.ErrInvalidInput)
+	}
+	return nil
+}
+
+// canReadPlaylist Доступ к обычному или умному плейлисту: открытый плейлист виден всем, закрытый — только владельцу
+func canReadPlaylist(owner string, public bool, caller string) bool {
+	return public || owner == strings.TrimSpace(caller)
+}
+
+func validateAfterEntry(afterEntryId *int64) error {
+	if afterEntryId != nil && *afterEntryId < 0 {
+		return fmt.Errorf("%w: after_entry_id must not be negative", model.ErrInvalidInput)
+	}
+	return nil
+}
@@ -0,0 +1,93 @@
+package service
+
+import (
+	"BestMusicLibrary/internal/model"
+	"BestMusicLibrary/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+// stubPlaylistRepository Хранит один плейлист, считает перемещения записей и запоминает пользователя,
+// от которого выполнено последнее изменение
+type stubPlaylistRepository struct {
+	repository.Playlist
+	playlist model.Playlist
+	moves    int
+	caller   string
+}
+
+func (r *stubPlaylistRepository) GetPlaylist(id int64) (model.Playlist, error) {
+	if id != r.playlist.Id {
+		return model.Playlist{}, model.ErrNotFound
+	}
+	return r.playlist, nil
+}
+
+func (r *stubPlaylistRepository) AddPlaylist(playlist model.Playlist) (int64, error) {
+	r.playlist = playlist
+	return 1, nil
+}
+
+func (r *stubPlaylistRepository) AddPlaylistEntry(_ int64, caller string, _ int64, _ *int64) (int64, error) {
+	r.caller = caller
+	return 1, nil
+}
+
+func (r *stubPlaylistRepository) MovePlaylistEntry(_ int64, caller string, _ int64, _ *int64) error {
+	r.moves++
+	r.caller = caller
+	return nil
+}
+
+func TestAddPlaylistRequiresNameAndOwner(t *testing.T) {
+	repos := &stubPlaylistRepository{}
+	playlistService := NewPlaylistService(repos)
+
+	_, err := playlistService.AddPlaylist(model.Playlist{Name: " Дорога домой ", Owner: " anna ", Public: true})
+	require.NoError(t, err)
+	assert.Equal(t, model.Playlist{Name: "Дорога домой", Owner: "anna", Public: true}, repos.playlist)
+
+	_, err = playlistService.AddPlaylist(model.Playlist{Name: "  ", Owner: "anna"})
+	assert.ErrorIs(t, err, model.ErrInvalidInput)
+
+	_, err = playlistService.AddPlaylist(model.Playlist{Name: "Дорога домой"})
+	assert.ErrorIs(t, err, model.ErrInvalidInput)
+}
+
+func TestPlaylistEntryArgumentsAndSelfMove(t *testing.T) {
+	repos := &stubPlaylistRepository{}
+	playlistService := NewPlaylistService(repos)
+	start, self, negative := int64(0), int64(5), int64(-1)
+
+	_, err := playlistService.AddPlaylistEntry(1, "anna", 0, nil)
+	assert.ErrorIs(t, err, model.ErrInvalidInput)
+	_, err = playlistService.AddPlaylistEntry(1, "anna", 10, &negative)
+	assert.ErrorIs(t, err, model.ErrInvalidInput)
+	_, err = playlistService.AddPlaylistEntry(1, " anna ", 10, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "anna", repos.caller)
+
+	require.NoError(t, playlistService.MovePlaylistEntry(1, "anna", 5, &self))
+	assert.Equal(t, 0, repos.moves)
+	require.NoError(t, playlistService.MovePlaylistEntry(1, "anna", 5, &start))
+	assert.Equal(t, 1, repos.moves)
+}
+
+func TestGetPlaylistHidesPrivatePlaylists(t *testing.T) {
+	repos := &stubPlaylistRepository{playlist: model.Playlist{Id: 1, Name: "Дорога домой", Owner: "anna"}}
+	playlistService := NewPlaylistService(repos)
+
+	playlist, err := playlistService.GetPlaylist(1, " anna ")
+	require.NoError(t, err)
+	assert.Equal(t, "anna", playlist.Owner)
+
+	_, err = playlistService.GetPlaylist(1, "boris")
+	assert.ErrorIs(t, err, model.ErrNotFound)
+	_, err = playlistService.GetPlaylist(1, "")
+	assert.ErrorIs(t, err, model.ErrNotFound)
+
+	repos.playlist.Public = true
+	_, err = playlistService.GetPlaylist(1, "boris")
+	require.NoError(t, err)
+}
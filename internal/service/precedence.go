@@ -0,0 +1,117 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Precedence Правило выбора между значением клиента и значением провайдера при добавлении песни
+type Precedence string
+
+const (
+	// PrecedenceClient Переданное клиентом значение используется всегда, даже пустое
+	PrecedenceClient Precedence = "client"
+	// PrecedenceUpstream Значение провайдера используется, если он его вернул
+	PrecedenceUpstream Precedence = "upstream"
+	// PrecedenceFillMissing Провайдер заполняет только поля, которые клиент не передал или передал пустыми
+	PrecedenceFillMissing Precedence = "fill_missing"
+)
+
+const clientSourceName = "client"
+
+// ClientSongData Данные песни, переданные клиентом. nil означает, что поле не передано
+type ClientSongData struct {
+	ReleaseDate *string
+	Link        *string
+	Text        *string
+}
+
+func (d ClientSongData) field(field SongField) *string {
+	switch field {
+	case SongFieldReleaseDate:
+		return d.ReleaseDate
+	case SongFieldText:
+		return d.Text
+	case SongFieldLink:
+		return d.Link
+	}
+	return nil
+}
+
+// ParsePrecedence Разбор правила по умолчанию и правил для отдельных полей вида "release_date=client;text=upstream"
+func ParsePrecedence(rawDefault, rawFields string) (map[SongField]Precedence, error) {
+	defaultPrecedence := PrecedenceFillMissing
+	if rawDefault = strings.TrimSpace(rawDefault); rawDefault != "" {
+		defaultPrecedence = Precedence(rawDefault)
+		if !isPrecedence(defaultPrecedence) {
+			return nil, fmt.Errorf("unknown enrichment precedence %q", rawDefault)
+		}
+	}
+
+	precedence := make(map[SongField]Precedence, len(songFields))
+	for _, field := range songFields {
+		precedence[field] = defaultPrecedence
+	}
+
+	for _, rawRule := range strings.Split(rawFields, ";") {
+		rawRule = strings.TrimSpace(rawRule)
+		if rawRule == "" {
+			continue
+		}
+
+		rawField, rawPrecedence, found := strings.Cut(rawRule, "=")
+		if !found {
+			return nil, fmt.Errorf("invalid precedence rule %q", rawRule)
+		}
+
+		field := SongField(strings.TrimSpace(rawField))
+		if !isSongField(field) {
+			return nil, fmt.Errorf("unknown song field %q", field)
+		}
+		fieldPrecedence := Precedence(strings.TrimSpace(rawPrecedence))
+		if !isPrecedence(fieldPrecedence) {
+			return nil, fmt.Errorf("unknown enrichment precedence %q for field %q", fieldPrecedence, field)
+		}
+		precedence[field] = fieldPrecedence
+	}
+
+	return precedence, nil
+}
+
+// needsUpstream Нужно ли запрашивать поле у провайдеров при данном значении клиента
+func (p Precedence) needsUpstream(clientValue *string) bool {
+	switch p {
+	case PrecedenceClient:
+		return clientValue == nil
+	case PrecedenceFillMissing:
+		return clientValue == nil || *clientValue == ""
+	}
+	return true
+}
+
+// resolve Выбор итогового значения поля. fromClient сообщает, что значение взято у клиента
+func (p Precedence) resolve(clientValue *string, upstreamValue string) (value string, fromClient bool) {
+	switch p {
+	case PrecedenceClient:
+		if clientValue != nil {
+			return *clientValue, true
+		}
+	case PrecedenceUpstream:
+		if upstreamValue == "" && clientValue != nil {
+			return *clientValue, true
+		}
+	case PrecedenceFillMissing:
+		if clientValue != nil && *clientValue != "" {
+			return *clientValue, true
+		}
+	}
+	return upstreamValue, false
+}
+
+func isPrecedence(precedence Precedence) bool {
+	switch precedence {
+	case PrecedenceClient, PrecedenceUpstream, PrecedenceFillMissing:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,97 @@
+package service
+
+import (
+	"BestMusicLibrary/internal/model"
+	"context"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func newPrecedenceTestService(t *testing.T, precedence map[SongField]Precedence) *SongService {
+	chain, err := NewProviderChain([]SongDataProvider{
+		{Name: "api", Fetcher: stubFetcher{data: SongFetchData{ReleaseDate: "16.07.2006", Text: "Upstream verse", Link: "https://upstream"}}},
+	}, nil)
+	assert.NoError(t, err)
+	return NewSongService(nil, SongServiceOptions{Providers: chain, Precedence: precedence})
+}
+
+func TestEnrichSongWithAPIKeepsRequestFields(t *testing.T) {
+	precedence, err := ParsePrecedence("", "")
+	assert.NoError(t, err)
+	service := newPrecedenceTestService(t, precedence)
+
+	song, warnings, err := service.enrichSongWithAPI(context.Background(), model.Song{Group: "Muse", Name: "Supermassive Black Hole"}, ClientSongData{})
+	assert.NoError(t, err)
+	assert.Empty(t, warnings)
+	assert.Equal(t, "Muse", song.Group)
+	assert.Equal(t, "Supermassive Black Hole", song.Name)
+	assert.Equal(t, time.Date(2006, 7, 16, 0, 0, 0, 0, time.UTC), song.ReleaseDate)
+	assert.Equal(t, "https://upstream", song.Link)
+}
+
+func TestEnrichSongWithAPIPrecedence(t *testing.T) {
+	empty, link := "", "https://client"
+	precedence, err := ParsePrecedence("fill_missing", "link=upstream;text=client")
+	assert.NoError(t, err)
+	service := newPrecedenceTestService(t, precedence)
+
+	song, _, err := service.enrichSongWithAPI(context.Background(), model.Song{Group: "Muse", Name: "Uprising"}, ClientSongData{
+		ReleaseDate: &empty,
+		Link:        &link,
+		Text:        &empty,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2006, 7, 16, 0, 0, 0, 0, time.UTC), song.ReleaseDate)
+	assert.Equal(t, "https://upstream", song.Link)
+	assert.Empty(t, song.Verses)
+	assert.Equal(t, []model.SongSource{
+		{Field: string(SongFieldReleaseDate), Provider: "api"},
+		{Field: string(SongFieldLink), Provider: "api"},
+	}, song.Sources)
+}
+
+func TestEnrichSongWithAPIInvalidClientReleaseDate(t *testing.T) {
+	releaseDate := "someday"
+	precedence, err := ParsePrecedence("client", "")
+	assert.NoError(t, err)
+	service := newPrecedenceTestService(t, precedence)
+
+	_, _, err = service.enrichSongWithAPI(context.Background(), model.Song{Group: "Muse", Name: "Uprising"}, ClientSongData{ReleaseDate: &releaseDate})
+	assert.ErrorIs(t, err, model.ErrInvalidInput)
+}
+
+func TestEnrichSongPrecedence(t *testing.T) {
+	precedence, err := ParsePrecedence("fill_missing", "release_date=upstream")
+	assert.NoError(t, err)
+	chain, err := NewProviderChain([]SongDataProvider{
+		{Name: "api", Fetcher: stubFetcher{data: SongFetchData{ReleaseDate: "16.07.2006", Text: "Upstream verse", Link: "https://upstream"}}},
+	}, nil)
+	assert.NoError(t, err)
+	repos := &stubSongRepository{song: model.Song{
+		Id:                   1,
+		Group:                "Muse",
+		Name:                 "Uprising",
+		ReleaseDate:          time.Date(2009, 1, 1, 0, 0, 0, 0, time.UTC),
+		ReleaseDatePrecision: model.DatePrecisionYear,
+		Link:                 "https://stored",
+	}}
+	service := NewSongService(repos, SongServiceOptions{Providers: chain, Precedence: precedence})
+
+	diff, err := service.EnrichSong(1, false)
+	assert.NoError(t, err)
+	assert.True(t, diff.Applied)
+	assert.Equal(t, []FieldChange{
+		{Field: SongFieldReleaseDate, Old: "2009", New: "2006-07-16", Provider: "api"},
+		{Field: SongFieldText, Old: "", New: "Upstream verse", Provider: "api"},
+	}, diff.Changes)
+	assert.Equal(t, "https://stored", repos.song.Link)
+}
+
+func TestParsePrecedenceUnknown(t *testing.T) {
+	_, err := ParsePrecedence("server", "")
+	assert.Error(t, err)
+
+	_, err = ParsePrecedence("", "text=server")
+	assert.Error(t, err)
+}
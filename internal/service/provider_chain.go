@@ -0,0 +1,217 @@
+package service
+
+import (
+	"BestMusicLibrary/internal/model"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/sirupsen/logrus"
+	"strings"
+)
+
+type SongField string
+
+const (
+	SongFieldReleaseDate SongField = "release_date"
+	SongFieldText        SongField = "text"
+	SongFieldLink        SongField = "link"
+)
+
+var songFields = []SongField{SongFieldReleaseDate, SongFieldText, SongFieldLink}
+
+type SongDataProvider struct {
+	Name    string
+	Fetcher SongDataFetcher
+}
+
+// ProviderChain Цепочка провайдеров обогащения в порядке приоритета с правилами выбора провайдера для каждого поля
+type ProviderChain struct {
+	providers []SongDataProvider
+	rules     map[SongField][]string
+}
+
+type providerResult struct {
+	provider string
+	data     SongFetchData
+	err      error
+}
+
+func NewProviderChain(providers []SongDataProvider, rules map[SongField][]string) (*ProviderChain, error) {
+	if len(providers) == 0 {
+		return nil, errors.New("at least one song data provider is required")
+	}
+
+	names := make(map[string]bool, len(providers))
+	for _, provider := range providers {
+		if provider.Name == "" {
+			return nil, errors.New("song data provider name is empty")
+		}
+		if provider.Name == clientSourceName {
+			return nil, fmt.Errorf("song data provider name %q is reserved", clientSourceName)
+		}
+		if names[provider.Name] {
+			return nil, fmt.Errorf("duplicate song data provider %q", provider.Name)
+		}
+		names[provider.Name] = true
+	}
+
+	for field := range rules {
+		if !isSongField(field) {
+			return nil, fmt.Errorf("unknown song field %q", field)
+		}
+	}
+
+	chainRules := make(map[SongField][]string, len(songFields))
+	for _, field := range songFields {
+		order, ok := rules[field]
+		if !ok {
+			order = make([]string, 0, len(providers))
+			for _, provider := range providers {
+				order = append(order, provider.Name)
+			}
+		}
+		for _, name := range order {
+			if !names[name] {
+				return nil, fmt.Errorf("unknown provider %q in rule for field %q", name, field)
+			}
+		}
+		chainRules[field] = order
+	}
+
+	return &ProviderChain{providers: providers, rules: chainRules}, nil
+}
+
+// ParseFieldRules Разбор правил вида "release_date=a,b;text=b": для каждого поля провайдеры в порядке приоритета
+func ParseFieldRules(raw string) (map[SongField][]string, error) {
+	rules := make(map[SongField][]string)
+	for _, rawRule := range strings.Split(raw, ";") {
+		rawRule = strings.TrimSpace(rawRule)
+		if rawRule == "" {
+			continue
+		}
+
+		rawField, rawProviders, found := strings.Cut(rawRule, "=")
+		if !found {
+			return nil, fmt.Errorf("invalid field rule %q", rawRule)
+		}
+
+		field := SongField(strings.TrimSpace(rawField))
+		if !isSongField(field) {
+			return nil, fmt.Errorf("unknown song field %q", field)
+		}
+
+		providers := make([]string, 0)
+		for _, name := range strings.Split(rawProviders, ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				providers = append(providers, name)
+			}
+		}
+		if len(providers) == 0 {
+			return nil, fmt.Errorf("no providers in rule for field %q", field)
+		}
+		rules[field] = providers
+	}
+	return rules, nil
+}
+
+// Fetch Опрос провайдеров и слияние полей по правилам. Ошибка возвращается, только если не ответил ни один провайдер
+func (c *ProviderChain) Fetch(ctx context.Context, group, song string) (SongFetchData, []model.SongSource, error) {
+	needed := c.neededProviders()
+	resChan := make(chan providerResult, len(needed))
+
+	for _, provider := range needed {
+		go func(provider SongDataProvider) {
+			songDetails, clientError := provider.Fetcher.FetchSongDetails(group, song)
+			resChan <- providerResult{provider: provider.Name, data: songDetails, err: clientError}
+		}(provider)
+	}
+
+	results := make(map[string]SongFetchData, len(needed))
+	errs := make([]error, 0)
+	for range needed {
+		select {
+		case res := <-resChan:
+			if res.err != nil {
+				logrus.WithFields(logrus.Fields{
+					"provider": res.provider,
+					"group":    group,
+					"song":     song,
+				}).Warn(res.err)
+				errs = append(errs, fmt.Errorf("provider %s: %w", res.provider, res.err))
+				continue
+			}
+			results[res.provider] = res.data
+		case <-ctx.Done():
+			return SongFetchData{}, nil, ctx.Err()
+		}
+	}
+
+	if len(results) == 0 {
+		return SongFetchData{}, nil, errors.Join(errs...)
+	}
+
+	var merged SongFetchData
+	sources := make([]model.SongSource, 0, len(songFields))
+	for _, field := range songFields {
+		for _, name := range c.rules[field] {
+			data, ok := results[name]
+			if !ok || data.field(field) == "" {
+				continue
+			}
+			merged.setField(field, data.field(field))
+			sources = append(sources, model.SongSource{Field: string(field), Provider: name})
+			break
+		}
+	}
+
+	return merged, sources, nil
+}
+
+func (c *ProviderChain) neededProviders() []SongDataProvider {
+	used := make(map[string]bool)
+	for _, order := range c.rules {
+		for _, name := range order {
+			used[name] = true
+		}
+	}
+
+	needed := make([]SongDataProvider, 0, len(used))
+	for _, provider := range c.providers {
+		if used[provider.Name] {
+			needed = append(needed, provider)
+		}
+	}
+	return needed
+}
+
+func (d SongFetchData) field(field SongField) string {
+	switch field {
+	case SongFieldReleaseDate:
+		return d.ReleaseDate
+	case SongFieldText:
+		return d.Text
+	case SongFieldLink:
+		return d.Link
+	}
+	return ""
+}
+
+func (d *SongFetchData) setField(field SongField, value string) {
+	switch field {
+	case SongFieldReleaseDate:
+		d.ReleaseDate = value
+	case SongFieldText:
+		d.Text = value
+	case SongFieldLink:
+		d.Link = value
+	}
+}
+
+func isSongField(field SongField) bool {
+	for _, f := range songFields {
+		if f == field {
+			return true
+		}
+	}
+	return false
+}
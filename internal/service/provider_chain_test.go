@@ -0,0 +1,70 @@
+package service
+
+import (
+	"BestMusicLibrary/internal/model"
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+type stubFetcher struct {
+	data SongFetchData
+	err  error
+}
+
+func (f stubFetcher) FetchSongDetails(string, string) (SongFetchData, error) {
+	return f.data, f.err
+}
+
+func TestProviderChainFetchMergesByRules(t *testing.T) {
+	chain, err := NewProviderChain([]SongDataProvider{
+		{Name: "a", Fetcher: stubFetcher{data: SongFetchData{ReleaseDate: "16.07.2006", Text: "a text", Link: "a link"}}},
+		{Name: "b", Fetcher: stubFetcher{data: SongFetchData{Text: "b text"}}},
+	}, map[SongField][]string{SongFieldText: {"b", "a"}})
+	assert.NoError(t, err)
+
+	data, sources, err := chain.Fetch(context.Background(), "Muse", "Supermassive Black Hole")
+	assert.NoError(t, err)
+	assert.Equal(t, SongFetchData{ReleaseDate: "16.07.2006", Text: "b text", Link: "a link"}, data)
+	assert.Equal(t, []model.SongSource{
+		{Field: string(SongFieldReleaseDate), Provider: "a"},
+		{Field: string(SongFieldText), Provider: "b"},
+		{Field: string(SongFieldLink), Provider: "a"},
+	}, sources)
+}
+
+func TestProviderChainFetchSkipsFailedProvider(t *testing.T) {
+	chain, err := NewProviderChain([]SongDataProvider{
+		{Name: "a", Fetcher: stubFetcher{err: errors.New("unavailable")}},
+		{Name: "b", Fetcher: stubFetcher{data: SongFetchData{Link: "b link"}}},
+	}, nil)
+	assert.NoError(t, err)
+
+	data, sources, err := chain.Fetch(context.Background(), "Muse", "Uprising")
+	assert.NoError(t, err)
+	assert.Equal(t, SongFetchData{Link: "b link"}, data)
+	assert.Equal(t, []model.SongSource{{Field: string(SongFieldLink), Provider: "b"}}, sources)
+}
+
+func TestProviderChainFetchAllProvidersFailed(t *testing.T) {
+	chain, err := NewProviderChain([]SongDataProvider{
+		{Name: "a", Fetcher: stubFetcher{err: errors.New("unavailable")}},
+	}, nil)
+	assert.NoError(t, err)
+
+	_, _, err = chain.Fetch(context.Background(), "Muse", "Uprising")
+	assert.Error(t, err)
+}
+
+func TestParseFieldRules(t *testing.T) {
+	rules, err := ParseFieldRules("release_date=a, b; text=b")
+	assert.NoError(t, err)
+	assert.Equal(t, map[SongField][]string{SongFieldReleaseDate: {"a", "b"}, SongFieldText: {"b"}}, rules)
+
+	_, err = ParseFieldRules("lyrics=a")
+	assert.Error(t, err)
+
+	_, err = NewProviderChain([]SongDataProvider{{Name: "a", Fetcher: stubFetcher{}}}, map[SongField][]string{SongFieldLink: {"c"}})
+	assert.Error(t, err)
+}
@@ -0,0 +1,67 @@
+package service
+
+import (
+	"strconv"
+)
+
+// Поля песни, которые вычисляются из ее названий и текста и пересчитываются ReindexSong
+const (
+	derivedFieldGroupSearchKey SongField = "group_search_key"
+	derivedFieldNameSearchKey  SongField = "title_search_key"
+	derivedFieldLanguage       SongField = "language"
+	derivedFieldExplicit       SongField = "explicit"
+)
+
+// reindexProvider Источник изменений, найденных при пересчете
+const reindexProvider = "reindex"
+
+// ReindexSong Пересчет ключей поиска, языка оригинала, определения откровенной песни и термов похожих песен
+// по сохраненным данным. Используется после изменения алгоритмов вместо миграций данных.
+// Язык определяется только для песен без языка, потому что определенный язык нельзя отличить от указанного клиентом.
+// Термы перестраиваются при каждом применении, в режиме dryRun изменения только вычисляются
+func (s *SongService) ReindexSong(id int64, dryRun bool) (EnrichmentDiff, error) {
+	song, err := s.songRepos.GetSong(id)
+	if err != nil {
+		return EnrichmentDiff{}, err
+	}
+
+	updated := song
+	setSearchKeys(&updated)
+	detectSongLanguage(&updated)
+	s.detectExplicit(&updated)
+
+	diff := EnrichmentDiff{SongId: id, Changes: make([]FieldChange, 0), Warnings: make([]string, 0)}
+	addChange := func(field SongField, oldValue, newValue string) {
+		if oldValue != newValue {
+			diff.Changes = append(diff.Changes, FieldChange{Field: field, Old: oldValue, New: newValue, Provider: reindexProvider})
+		}
+	}
+	addChange(derivedFieldGroupSearchKey, song.GroupSearchKey, updated.GroupSearchKey)
+	addChange(derivedFieldNameSearchKey, song.NameSearchKey, updated.NameSearchKey)
+	addChange(derivedFieldLanguage, song.Language, updated.Language)
+	addChange(derivedFieldExplicit, formatDetected(song.ExplicitDetected), formatDetected(updated.ExplicitDetected))
+
+	if dryRun {
+		return diff, nil
+	}
+	if len(diff.Changes) > 0 {
+		if err = s.songRepos.UpdateSongDerived(updated); err != nil {
+			return EnrichmentDiff{}, err
+		}
+	}
+	setSongTerms(&updated)
+	if err = s.songRepos.ReplaceSongTerms(id, updated.Terms); err != nil {
+		return EnrichmentDiff{}, err
+	}
+	diff.Applied = true
+
+	return diff, nil
+}
+
+// formatDetected Результат определения для отчета об изменениях, пустой если песня еще не проверялась
+func formatDetected(detected *bool) string {
+	if detected == nil {
+		return ""
+	}
+	return strconv.FormatBool(*detected)
+}
@@ -0,0 +1,33 @@
+package service
+
+import (
+	"BestMusicLibrary/internal/model"
+	"BestMusicLibrary/internal/profanity"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestReindexSong(t *testing.T) {
+	stale := model.Song{Id: 1, Group: "Кино", Name: "Пачка сигарет", GroupSearchKey: "кино", NameSearchKey: "pachka sigaret",
+		Language: "ru", Verses: []model.Verse{{VerseNumber: 1, Text: "What the fuck"}}, Arrangement: []int{1}}
+	repos := &stubSongRepository{song: stale}
+	songService := NewSongService(repos, SongServiceOptions{ExplicitDetector: profanity.Default()})
+
+	diff, err := songService.ReindexSong(1, true)
+	require.NoError(t, err)
+	assert.False(t, diff.Applied)
+	assert.Equal(t, []FieldChange{
+		{Field: derivedFieldGroupSearchKey, Old: "кино", New: "kino", Provider: reindexProvider},
+		{Field: derivedFieldExplicit, Old: "", New: "true", Provider: reindexProvider},
+	}, diff.Changes)
+	assert.Equal(t, stale, repos.song)
+
+	diff, err = songService.ReindexSong(1, false)
+	require.NoError(t, err)
+	assert.True(t, diff.Applied)
+	assert.Equal(t, "kino", repos.song.GroupSearchKey)
+	require.NotNil(t, repos.song.ExplicitDetected)
+	assert.True(t, *repos.song.ExplicitDetected)
+	assert.Equal(t, "ru", repos.song.Language)
+}
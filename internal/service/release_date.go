@@ -0,0 +1,69 @@
+package service
+
+import (
+	"BestMusicLibrary/internal/model"
+	"fmt"
+	"strings"
+	"time"
+)
+
+type releaseDateLayout struct {
+	layout    string
+	precision model.DatePrecision
+}
+
+var releaseDateLayouts = []releaseDateLayout{
+	{"02.01.2006", model.DatePrecisionDay},
+	{"2.1.2006", model.DatePrecisionDay},
+	{time.DateOnly, model.DatePrecisionDay},
+	{time.RFC3339, model.DatePrecisionDay},
+	{"2006-01-02T15:04:05", model.DatePrecisionDay},
+	{"2006/01/02", model.DatePrecisionDay},
+	{"January 2, 2006", model.DatePrecisionDay},
+	{"Jan 2, 2006", model.DatePrecisionDay},
+	{"2 January 2006", model.DatePrecisionDay},
+	{"2 Jan 2006", model.DatePrecisionDay},
+	{"01.2006", model.DatePrecisionMonth},
+	{"2006-01", model.DatePrecisionMonth},
+	{"2006/01", model.DatePrecisionMonth},
+	{"January 2006", model.DatePrecisionMonth},
+	{"Jan 2006", model.DatePrecisionMonth},
+	{"2006", model.DatePrecisionYear},
+}
+
+// parseReleaseDate Разбор даты релиза в одном из известных форматов с определением точности.
+// Для пустой строки возвращается нулевая дата без ошибки
+func parseReleaseDate(raw string) (time.Time, model.DatePrecision, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return time.Time{}, "", nil
+	}
+
+	for _, layout := range releaseDateLayouts {
+		releaseDate, err := time.Parse(layout.layout, raw)
+		if err != nil {
+			continue
+		}
+		if releaseDate.Year() < 1000 {
+			break
+		}
+		year, month, day := releaseDate.Date()
+		return time.Date(year, month, day, 0, 0, 0, 0, time.UTC), layout.precision, nil
+	}
+
+	return time.Time{}, "", fmt.Errorf("unrecognized release date %q", raw)
+}
+
+// formatReleaseDate Представление даты с учетом ее точности: 2006, 2006-07 или 2006-07-16
+func formatReleaseDate(releaseDate time.Time, precision model.DatePrecision) string {
+	if releaseDate.IsZero() {
+		return ""
+	}
+	switch precision {
+	case model.DatePrecisionYear:
+		return releaseDate.Format("2006")
+	case model.DatePrecisionMonth:
+		return releaseDate.Format("2006-01")
+	}
+	return releaseDate.Format(time.DateOnly)
+}
@@ -0,0 +1,54 @@
+package service
+
+import (
+	"BestMusicLibrary/internal/model"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestParseReleaseDate(t *testing.T) {
+	tests := []struct {
+		raw       string
+		expected  time.Time
+		precision model.DatePrecision
+	}{
+		{"16.07.2006", time.Date(2006, 7, 16, 0, 0, 0, 0, time.UTC), model.DatePrecisionDay},
+		{"2006-07-16", time.Date(2006, 7, 16, 0, 0, 0, 0, time.UTC), model.DatePrecisionDay},
+		{"2006-07-16T10:00:00+03:00", time.Date(2006, 7, 16, 0, 0, 0, 0, time.UTC), model.DatePrecisionDay},
+		{"July 16, 2006", time.Date(2006, 7, 16, 0, 0, 0, 0, time.UTC), model.DatePrecisionDay},
+		{"2006-07", time.Date(2006, 7, 1, 0, 0, 0, 0, time.UTC), model.DatePrecisionMonth},
+		{"07.2006", time.Date(2006, 7, 1, 0, 0, 0, 0, time.UTC), model.DatePrecisionMonth},
+		{" 2006 ", time.Date(2006, 1, 1, 0, 0, 0, 0, time.UTC), model.DatePrecisionYear},
+	}
+
+	for _, test := range tests {
+		releaseDate, precision, err := parseReleaseDate(test.raw)
+		assert.NoError(t, err, test.raw)
+		assert.Equal(t, test.expected, releaseDate, test.raw)
+		assert.Equal(t, test.precision, precision, test.raw)
+	}
+}
+
+func TestParseReleaseDateEmpty(t *testing.T) {
+	releaseDate, precision, err := parseReleaseDate("")
+	assert.NoError(t, err)
+	assert.True(t, releaseDate.IsZero())
+	assert.Equal(t, model.DatePrecision(""), precision)
+}
+
+func TestParseReleaseDateUnrecognized(t *testing.T) {
+	for _, raw := range []string{"summer 2006", "16/07/2006", "0001", "32.13.2006"} {
+		releaseDate, _, err := parseReleaseDate(raw)
+		assert.Error(t, err, raw)
+		assert.True(t, releaseDate.IsZero(), raw)
+	}
+}
+
+func TestFormatReleaseDate(t *testing.T) {
+	releaseDate := time.Date(2006, 7, 16, 0, 0, 0, 0, time.UTC)
+	assert.Equal(t, "2006", formatReleaseDate(releaseDate, model.DatePrecisionYear))
+	assert.Equal(t, "2006-07", formatReleaseDate(releaseDate, model.DatePrecisionMonth))
+	assert.Equal(t, "2006-07-16", formatReleaseDate(releaseDate, model.DatePrecisionDay))
+	assert.Equal(t, "", formatReleaseDate(time.Time{}, model.DatePrecisionDay))
+}
@@ -0,0 +1,64 @@
+package service
+
+import (
+	"BestMusicLibrary/internal/model"
+	"BestMusicLibrary/internal/repository"
+	"fmt"
+)
+
+type ReportService struct {
+	reportRepos repository.Report
+}
+
+func NewReportService(repos repository.Report) *ReportService {
+	return &ReportService{reportRepos: repos}
+}
+
+// SongsPerGroup Число песен каждой группы по убыванию, limit ограничивает число групп
+func (s *ReportService) SongsPerGroup(filter model.SongFilter, limit int) ([]model.GroupSongCount, error) {
+	if limit < 0 {
+		return nil, fmt.Errorf("%w: negative limit %d", model.ErrInvalidInput, limit)
+	}
+	filter, err := normalizeSongFilter(filter)
+	if err != nil {
+		return nil, err
+	}
+	return s.reportRepos.SongsPerGroup(filter, limit)
+}
+
+// SongsPerReleasePeriod Число песен по годам или десятилетиям релиза, по умолчанию по годам
+func (s *ReportService) SongsPerReleasePeriod(filter model.SongFilter, period model.ReleasePeriod) ([]model.ReleasePeriodCount, error) {
+	var years int
+	switch period {
+	case "", model.ReleasePeriodYear:
+		years = 1
+	case model.ReleasePeriodDecade:
+		years = 10
+	default:
+		return nil, fmt.Errorf("%w: unknown release period %q", model.ErrInvalidInput, period)
+	}
+
+	filter, err := normalizeSongFilter(filter)
+	if err != nil {
+		return nil, err
+	}
+	return s.reportRepos.SongsPerReleasePeriod(filter, years)
+}
+
+// LyricsLength Средняя длина текста песен
+func (s *ReportService) LyricsLength(filter model.SongFilter) (model.LyricsLengthReport, error) {
+	filter, err := normalizeSongFilter(filter)
+	if err != nil {
+		return model.LyricsLengthReport{}, err
+	}
+	return s.reportRepos.LyricsLength(filter)
+}
+
+// EnrichmentCoverage Число песен без даты релиза, ссылки или текста
+func (s *ReportService) EnrichmentCoverage(filter model.SongFilter) (model.EnrichmentCoverage, error) {
+	filter, err := normalizeSongFilter(filter)
+	if err != nil {
+		return model.EnrichmentCoverage{}, err
+	}
+	return s.reportRepos.EnrichmentCoverage(filter)
+}
@@ -0,0 +1,38 @@
+package service
+
+import (
+	"BestMusicLibrary/internal/model"
+	"BestMusicLibrary/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+// stubReportRepository Запоминает фильтр и длину периода последнего запроса
+type stubReportRepository struct {
+	repository.Report
+	filter model.SongFilter
+	years  int
+}
+
+func (r *stubReportRepository) SongsPerReleasePeriod(filter model.SongFilter, years int) ([]model.ReleasePeriodCount, error) {
+	r.filter, r.years = filter, years
+	return []model.ReleasePeriodCount{}, nil
+}
+
+func TestSongsPerReleasePeriod(t *testing.T) {
+	repos := &stubReportRepository{}
+	reportService := NewReportService(repos)
+
+	_, err := reportService.SongsPerReleasePeriod(model.SongFilter{Group: "Кино", Language: "RU"}, model.ReleasePeriodDecade)
+	require.NoError(t, err)
+	assert.Equal(t, 10, repos.years)
+	assert.Equal(t, model.SongFilter{Group: "kino", Language: "ru"}, repos.filter)
+
+	_, err = reportService.SongsPerReleasePeriod(model.SongFilter{}, "")
+	require.NoError(t, err)
+	assert.Equal(t, 1, repos.years)
+
+	_, err = reportService.SongsPerReleasePeriod(model.SongFilter{}, "century")
+	assert.ErrorIs(t, err, model.ErrInvalidInput)
+}
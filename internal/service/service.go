@@ -2,21 +2,123 @@ package service
 
 import (
 	"BestMusicLibrary/internal/model"
+	"BestMusicLibrary/internal/profanity"
 	"BestMusicLibrary/internal/repository"
+	"golang.org/x/text/language"
 )
 
 type Song interface {
-	GetSongs(group, song string, page, limit int) ([]model.Song, error)
-	GetSongVerses(id int64, page, limit int) ([]model.Verse, error)
+	GetSongs(filter model.SongFilter, page, limit int) ([]model.Song, error)
+	GetSongIds(filter model.SongFilter, afterId int64, limit int) ([]int64, error)
+	CountSongs(filter model.SongFilter) (int, error)
+	GetSongVerses(id int64, page, limit int, compact bool, preferred []language.Tag) ([]model.Verse, string, error)
+	SetTranslation(id int64, language, text string) error
+	GetSongStats(id int64) (model.SongStats, error)
+	GetSimilarSongs(id int64, threshold *float64, limit int) ([]model.SimilarSong, error)
+	GetVerseLines(id int64, verseNumber, page, limit int) ([]model.VerseLine, error)
+	ImportSyncedLyrics(id int64, lrc string) ([]model.TimedLine, error)
+	ExportSyncedLyrics(id int64, format LyricsFormat) (string, error)
 	DeleteSong(id int64) error
 	UpdateSong(song model.Song, text string) error
-	AddSong(song model.Song) (int64, error)
+	SetExplicitOverride(id int64, explicit *bool) error
+	AddSong(song model.Song, clientData ClientSongData) (int64, []string, error)
+	GetSongSources(id int64) ([]model.SongSource, error)
+	GetSongCredits(id int64) ([]model.SongCredit, error)
+	SetSongCredits(id int64, credits []model.SongCredit) error
+	EnrichSong(id int64, dryRun bool) (EnrichmentDiff, error)
+	ReindexSong(id int64, dryRun bool) (EnrichmentDiff, error)
+}
+
+type Report interface {
+	SongsPerGroup(filter model.SongFilter, limit int) ([]model.GroupSongCount, error)
+	SongsPerReleasePeriod(filter model.SongFilter, period model.ReleasePeriod) ([]model.ReleasePeriodCount, error)
+	LyricsLength(filter model.SongFilter) (model.LyricsLengthReport, error)
+	EnrichmentCoverage(filter model.SongFilter) (model.EnrichmentCoverage, error)
+}
+
+type Artist interface {
+	GetArtists(filter model.ArtistFilter, page, limit int) ([]model.Artist, error)
+	GetArtist(id int64) (model.Artist, error)
+	AddArtist(artist model.Artist) (int64, error)
+	UpdateArtist(artist model.Artist) error
+	DeleteArtist(id int64) error
+}
+
+type Album interface {
+	GetAlbums(filter model.AlbumFilter, page, limit int) ([]model.Album, error)
+	GetAlbum(id int64) (model.Album, error)
+	AddAlbum(album model.Album) (int64, error)
+	UpdateAlbum(album model.Album) error
+	DeleteAlbum(id int64) error
+	ReplaceAlbumTracks(albumId int64, tracks []model.AlbumTrack) error
+	AddAlbumTrack(albumId int64, track model.AlbumTrack) error
+	DeleteAlbumTrack(albumId, songId int64) error
+}
+
+type Genre interface {
+	GetGenreTree() ([]model.Genre, error)
+	AddGenre(genre model.Genre) (int64, error)
+	UpdateGenre(genre model.Genre) error
+	DeleteGenre(id int64) error
+	GetSongGenres(songId int64) ([]model.Genre, error)
+	AddSongGenre(songId, genreId int64) error
+	DeleteSongGenre(songId, genreId int64) error
+}
+
+type Tag interface {
+	GetSongTags(songId int64) ([]string, error)
+	AddSongTag(songId int64, tag string) error
+	DeleteSongTag(songId int64, tag string) error
+	SuggestTags(prefix string, limit int) ([]string, error)
+	GetTagCounts(page, limit int) ([]model.TagCount, error)
+}
+
+type Playlist interface {
+	GetPlaylists(filter model.PlaylistFilter, page, limit int) ([]model.Playlist, error)
+	GetPlaylist(id int64, caller string) (model.Playlist, error)
+	AddPlaylist(playlist model.Playlist) (int64, error)
+	UpdatePlaylist(playlist model.Playlist, caller string) error
+	DeletePlaylist(id int64, caller string) error
+	AddPlaylistEntry(playlistId int64, caller string, songId int64, afterEntryId *int64) (int64, error)
+	MovePlaylistEntry(playlistId int64, caller string, entryId int64, afterEntryId *int64) error
+	DeletePlaylistEntry(playlistId int64, caller string, entryId int64) error
+}
+
+type SmartPlaylist interface {
+	GetSmartPlaylists(filter model.PlaylistFilter, page, limit int) ([]model.SmartPlaylist, error)
+	GetSmartPlaylist(id int64, caller string) (model.SmartPlaylist, error)
+	AddSmartPlaylist(playlist model.SmartPlaylist) (int64, error)
+	UpdateSmartPlaylist(playlist model.SmartPlaylist, caller string) error
+	DeleteSmartPlaylist(id int64, caller string) error
+	GetSmartPlaylistSongs(id int64, caller string) ([]model.Song, int, error)
+	PreviewSmartPlaylist(playlist model.SmartPlaylist) ([]model.Song, int, error)
 }
 
 type Service struct {
-	Song Song
+	Song          Song
+	Artist        Artist
+	Album         Album
+	Genre         Genre
+	Tag           Tag
+	Playlist      Playlist
+	SmartPlaylist SmartPlaylist
+	Report        Report
 }
 
-func NewService(repos *repository.Repository, songDataFetcher SongDataFetcher) *Service {
-	return &Service{Song: NewSongService(repos.Song, songDataFetcher)}
+func NewService(repos *repository.Repository, providers *ProviderChain, precedence map[SongField]Precedence, explicitDetector *profanity.Detector) *Service {
+	return &Service{
+		Song: NewSongService(repos.Song, SongServiceOptions{
+			Artists:          repos.Artist,
+			Providers:        providers,
+			Precedence:       precedence,
+			ExplicitDetector: explicitDetector,
+		}),
+		Artist:        NewArtistService(repos.Artist),
+		Album:         NewAlbumService(repos.Album, repos.Artist),
+		Genre:         NewGenreService(repos.Genre, repos.Song),
+		Tag:           NewTagService(repos.Tag, repos.Song),
+		Playlist:      NewPlaylistService(repos.Playlist),
+		SmartPlaylist: NewSmartPlaylistService(repos.SmartPlaylist, repos.Song, repos.Genre),
+		Report:        NewReportService(repos.Report),
+	}
 }
@@ -0,0 +1,41 @@
+package service
+
+import (
+	"BestMusicLibrary/internal/lyricstats"
+	"BestMusicLibrary/internal/model"
+	"fmt"
+)
+
+const (
+	defaultSimilarityThreshold = 0.1
+	defaultSimilarSongsLimit   = 10
+	maxSimilarSongsLimit       = 100
+)
+
+// GetSimilarSongs Песни с наиболее похожим текстом по убыванию близости. Без threshold используется порог
+// по умолчанию, нулевой limit возвращает десять песен
+func (s *SongService) GetSimilarSongs(id int64, threshold *float64, limit int) ([]model.SimilarSong, error) {
+	minSimilarity := defaultSimilarityThreshold
+	if threshold != nil {
+		if *threshold < 0 || *threshold > 1 {
+			return nil, fmt.Errorf("%w: similarity threshold %v is outside [0, 1]", model.ErrInvalidInput, *threshold)
+		}
+		minSimilarity = *threshold
+	}
+	switch {
+	case limit < 0 || limit > maxSimilarSongsLimit:
+		return nil, fmt.Errorf("%w: limit %d is outside [0, %d]", model.ErrInvalidInput, limit, maxSimilarSongsLimit)
+	case limit == 0:
+		limit = defaultSimilarSongsLimit
+	}
+
+	if err := checkSongExists(s.songRepos, id); err != nil {
+		return nil, err
+	}
+	return s.songRepos.GetSimilarSongs(id, minSimilarity, limit)
+}
+
+// setSongTerms Заполнение термов текста песни перед записью, они сохраняются вместе с песней
+func setSongTerms(song *model.Song) {
+	song.Terms = lyricstats.TermCounts(expandVerses(song.Verses, song.Arrangement))
+}
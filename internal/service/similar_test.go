@@ -0,0 +1,21 @@
+package service
+
+import (
+	"BestMusicLibrary/internal/model"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestGetSimilarSongsValidatesParameters(t *testing.T) {
+	songService := NewSongService(&stubSongRepository{song: model.Song{Id: 1}}, SongServiceOptions{})
+
+	threshold := 1.5
+	_, err := songService.GetSimilarSongs(1, &threshold, 0)
+	assert.ErrorIs(t, err, model.ErrInvalidInput)
+
+	_, err = songService.GetSimilarSongs(1, nil, maxSimilarSongsLimit+1)
+	assert.ErrorIs(t, err, model.ErrInvalidInput)
+
+	_, err = songService.GetSimilarSongs(2, nil, 0)
+	assert.ErrorIs(t, err, model.ErrNotFound)
+}
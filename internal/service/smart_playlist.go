@@ -0,0 +1,146 @@
+package service
+
+import (
+	"BestMusicLibrary/internal/model"
+	"BestMusicLibrary/internal/repository"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const (
+	defaultSmartPlaylistLimit = 100
+	maxSmartPlaylistLimit     = 500
+)
+
+type SmartPlaylistService struct {
+	playlistRepos repository.SmartPlaylist
+	songRepos     repository.Song
+	genreRepos    repository.Genre
+}
+
+func NewSmartPlaylistService(repos repository.SmartPlaylist, songRepos repository.Song, genreRepos repository.Genre) *SmartPlaylistService {
+	return &SmartPlaylistService{playlistRepos: repos, songRepos: songRepos, genreRepos: genreRepos}
+}
+
+// GetSmartPlaylists Открытые умные плейлисты и плейлисты пользователя caller с пагинацией,
+// с владельцем — только его плейлисты
+func (s *SmartPlaylistService) GetSmartPlaylists(filter model.PlaylistFilter, rawPage, rawLimit int) ([]model.SmartPlaylist, error) {
+	page, limit := handlePagingData(rawPage, rawLimit)
+	filter.Owner = strings.TrimSpace(filter.Owner)
+	filter.Caller = strings.TrimSpace(filter.Caller)
+	return s.playlistRepos.GetSmartPlaylists(filter, page, limit)
+}
+
+// GetSmartPlaylist Умный плейлист с правилом. Закрытый плейлист другого пользователя считается ненайденным
+func (s *SmartPlaylistService) GetSmartPlaylist(id int64, caller string) (model.SmartPlaylist, error) {
+	playlist, err := s.playlistRepos.GetSmartPlaylist(id)
+	if err != nil {
+		return model.SmartPlaylist{}, err
+	}
+	if !canReadPlaylist(playlist.Owner, playlist.Public, caller) {
+		return model.SmartPlaylist{}, fmt.Errorf("smart playlist %d: %w", id, model.ErrNotFound)
+	}
+	return playlist, nil
+}
+
+func (s *SmartPlaylistService) AddSmartPlaylist(playlist model.SmartPlaylist) (int64, error) {
+	if err := preparePlaylistFields(&playlist.Name, &playlist.Description, &playlist.Owner); err != nil {
+		return 0, err
+	}
+	if err := s.prepareRule(&playlist); err != nil {
+		return 0, err
+	}
+	return s.playlistRepos.AddSmartPlaylist(playlist)
+}
+
+// UpdateSmartPlaylist Изменение умного плейлиста. Изменять плейлист может только владелец caller,
+// для остальных пользователей плейлист не найден
+func (s *SmartPlaylistService) UpdateSmartPlaylist(playlist model.SmartPlaylist, caller string) error {
+	if err := preparePlaylistFields(&playlist.Name, &playlist.Description, &playlist.Owner); err != nil {
+		return err
+	}
+	if err := s.prepareRule(&playlist); err != nil {
+		return err
+	}
+	return s.playlistRepos.UpdateSmartPlaylist(playlist, strings.TrimSpace(caller))
+}
+
+// DeleteSmartPlaylist Удаление умного плейлиста владельцем caller
+func (s *SmartPlaylistService) DeleteSmartPlaylist(id int64, caller string) error {
+	return s.playlistRepos.DeleteSmartPlaylist(id, strings.TrimSpace(caller))
+}
+
+// GetSmartPlaylistSongs Песни, которые правило умного плейлиста подбирает сейчас, и общее число подходящих песен
+// без учета ограничения плейлиста. Доступ к плейлисту проверяется как в GetSmartPlaylist
+func (s *SmartPlaylistService) GetSmartPlaylistSongs(id int64, caller string) ([]model.Song, int, error) {
+	playlist, err := s.GetSmartPlaylist(id, caller)
+	if err != nil {
+		return nil, 0, err
+	}
+	return s.matchSongs(playlist)
+}
+
+// PreviewSmartPlaylist Песни, которые подобрало бы правило, без сохранения плейлиста. Название и владелец
+// для предпросмотра не нужны
+func (s *SmartPlaylistService) PreviewSmartPlaylist(playlist model.SmartPlaylist) ([]model.Song, int, error) {
+	if err := s.prepareRule(&playlist); err != nil {
+		return nil, 0, err
+	}
+	return s.matchSongs(playlist)
+}
+
+// matchSongs Подбор песен тем же фильтром, что и в списке песен
+func (s *SmartPlaylistService) matchSongs(playlist model.SmartPlaylist) ([]model.Song, int, error) {
+	filter, err := normalizeSongFilter(playlist.Rule.SongFilter())
+	if err != nil {
+		return nil, 0, err
+	}
+	songs, err := s.playlistRepos.MatchSongs(filter, playlist.Sort, playlist.Descending, playlist.Limit)
+	if err != nil {
+		return nil, 0, err
+	}
+	total, err := s.songRepos.CountSongs(filter)
+	if err != nil {
+		return nil, 0, err
+	}
+	return songs, total, nil
+}
+
+// prepareRule Проверка правила, порядка и ограничения числа песен. Порядок по умолчанию — порядок добавления,
+// язык приводится к каноническому тегу BCP 47, жанр должен существовать и не может быть удален, пока на него
+// ссылается правило
+func (s *SmartPlaylistService) prepareRule(playlist *model.SmartPlaylist) error {
+	switch playlist.Sort {
+	case "":
+		playlist.Sort = model.SongSortAdded
+	case model.SongSortAdded, model.SongSortTitle, model.SongSortGroup, model.SongSortReleaseDate, model.SongSortRandom:
+	default:
+		return fmt.Errorf("%w: unknown sort %q", model.ErrInvalidInput, playlist.Sort)
+	}
+
+	switch {
+	case playlist.Limit < 0 || playlist.Limit > maxSmartPlaylistLimit:
+		return fmt.Errorf("%w: limit %d is outside [0, %d]", model.ErrInvalidInput, playlist.Limit, maxSmartPlaylistLimit)
+	case playlist.Limit == 0:
+		playlist.Limit = defaultSmartPlaylistLimit
+	}
+
+	rule := &playlist.Rule
+	rule.Group = strings.TrimSpace(rule.Group)
+	rule.Tag = strings.TrimSpace(rule.Tag)
+	filter, err := normalizeSongFilter(rule.SongFilter())
+	if err != nil {
+		return err
+	}
+	rule.Language = filter.Language
+
+	if rule.GenreId == 0 {
+		return nil
+	}
+	_, err = s.genreRepos.GetGenre(rule.GenreId)
+	if errors.Is(err, model.ErrNotFound) {
+		return fmt.Errorf("%w: unknown genre %d", model.ErrInvalidInput, rule.GenreId)
+	}
+	return err
+}
@@ -0,0 +1,96 @@
+package service
+
+import (
+	"BestMusicLibrary/internal/model"
+	"BestMusicLibrary/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+// stubSmartPlaylistRepository Хранит один плейлист и запоминает фильтр последнего подбора песен
+type stubSmartPlaylistRepository struct {
+	repository.SmartPlaylist
+	playlist model.SmartPlaylist
+	filter   model.SongFilter
+	limit    int
+}
+
+func (r *stubSmartPlaylistRepository) GetSmartPlaylist(id int64) (model.SmartPlaylist, error) {
+	if id != r.playlist.Id {
+		return model.SmartPlaylist{}, model.ErrNotFound
+	}
+	return r.playlist, nil
+}
+
+func (r *stubSmartPlaylistRepository) AddSmartPlaylist(playlist model.SmartPlaylist) (int64, error) {
+	r.playlist = playlist
+	return 1, nil
+}
+
+func (r *stubSmartPlaylistRepository) MatchSongs(filter model.SongFilter, _ model.SongSort, _ bool, limit int) ([]model.Song, error) {
+	r.filter, r.limit = filter, limit
+	return []model.Song{{Id: 1}}, nil
+}
+
+func newSmartPlaylistTestService() (*SmartPlaylistService, *stubSmartPlaylistRepository) {
+	repos := &stubSmartPlaylistRepository{}
+	genres := &stubGenreRepository{genres: []model.Genre{{Id: 1, Name: "Rock"}}}
+	return NewSmartPlaylistService(repos, &stubSongRepository{}, genres), repos
+}
+
+func TestAddSmartPlaylistValidatesRule(t *testing.T) {
+	playlistService, repos := newSmartPlaylistTestService()
+
+	_, err := playlistService.AddSmartPlaylist(model.SmartPlaylist{
+		Name:  "Русский рок",
+		Owner: "anna",
+		Rule:  model.SmartRule{GenreId: 1, Language: "RU", Tag: " летние хиты "},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, model.SongSortAdded, repos.playlist.Sort)
+	assert.Equal(t, defaultSmartPlaylistLimit, repos.playlist.Limit)
+	assert.Equal(t, model.SmartRule{GenreId: 1, Language: "ru", Tag: "летние хиты"}, repos.playlist.Rule)
+
+	from := time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(1990, 1, 1, 0, 0, 0, 0, time.UTC)
+	for _, playlist := range []model.SmartPlaylist{
+		{Name: "Русский рок", Owner: "anna", Rule: model.SmartRule{GenreId: 2}},
+		{Name: "Русский рок", Owner: "anna", Rule: model.SmartRule{ReleasedFrom: &from, ReleasedTo: &to}},
+		{Name: "Русский рок", Owner: "anna", Sort: "rating"},
+		{Name: "Русский рок", Owner: "anna", Limit: maxSmartPlaylistLimit + 1},
+		{Name: "Русский рок", Rule: model.SmartRule{GenreId: 1}},
+	} {
+		_, err = playlistService.AddSmartPlaylist(playlist)
+		assert.ErrorIs(t, err, model.ErrInvalidInput)
+	}
+}
+
+func TestPreviewSmartPlaylistUsesSongFilter(t *testing.T) {
+	playlistService, repos := newSmartPlaylistTestService()
+	explicit := false
+
+	songs, total, err := playlistService.PreviewSmartPlaylist(model.SmartPlaylist{
+		Rule:  model.SmartRule{Group: "Кино", Explicit: &explicit},
+		Limit: 20,
+	})
+	require.NoError(t, err)
+	assert.Len(t, songs, 1)
+	assert.Equal(t, 1, total)
+	assert.Equal(t, model.SongFilter{Group: "kino", Explicit: &explicit}, repos.filter)
+	assert.Equal(t, 20, repos.limit)
+}
+
+func TestGetSmartPlaylistSongsHidesPrivatePlaylists(t *testing.T) {
+	playlistService, repos := newSmartPlaylistTestService()
+	repos.playlist = model.SmartPlaylist{Id: 1, Name: "Русский рок", Owner: "anna", Limit: 20}
+
+	_, _, err := playlistService.GetSmartPlaylistSongs(1, "boris")
+	assert.ErrorIs(t, err, model.ErrNotFound)
+
+	songs, _, err := playlistService.GetSmartPlaylistSongs(1, "anna")
+	require.NoError(t, err)
+	assert.Len(t, songs, 1)
+	assert.Equal(t, 20, repos.limit)
+}
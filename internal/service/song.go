@@ -2,9 +2,13 @@ package service
 
 import (
 	"BestMusicLibrary/internal/model"
+	"BestMusicLibrary/internal/profanity"
 	"BestMusicLibrary/internal/repository"
+	"BestMusicLibrary/internal/search"
 	"context"
-	"strings"
+	"errors"
+	"fmt"
+	"golang.org/x/text/language"
 	"time"
 )
 
@@ -19,90 +23,288 @@ type SongDataFetcher interface {
 }
 
 type SongService struct {
-	songRepos       repository.Song
-	songDataFetcher SongDataFetcher
+	songRepos        repository.Song
+	artistRepos      repository.Artist
+	providers        *ProviderChain
+	precedence       map[SongField]Precedence
+	explicitDetector *profanity.Detector
 }
 
 const (
 	defaultPagePagingAmount  = 0
 	defaultLimitPagingAmount = 5
+	enrichmentTimeout        = 5 * time.Second
 )
 
-func NewSongService(repos repository.Song, songFetcher SongDataFetcher) *SongService {
-	return &SongService{songRepos: repos, songDataFetcher: songFetcher}
+// SongServiceOptions Зависимости сервиса песен кроме хранилища песен. Без словаря откровенных слов
+// откровенность песен не определяется
+type SongServiceOptions struct {
+	Artists          repository.Artist
+	Providers        *ProviderChain
+	Precedence       map[SongField]Precedence
+	ExplicitDetector *profanity.Detector
 }
 
-// GetSongs Получение данных библиотеки с фильтрацией по всем полям и пагинацией
-func (s *SongService) GetSongs(group, song string, rawPage, rawLimit int) ([]model.Song, error) {
+func NewSongService(repos repository.Song, options SongServiceOptions) *SongService {
+	return &SongService{
+		songRepos:        repos,
+		artistRepos:      options.Artists,
+		providers:        options.Providers,
+		precedence:       options.Precedence,
+		explicitDetector: options.ExplicitDetector,
+	}
+}
+
+// GetSongs Получение данных библиотеки с фильтрацией по всем полям и пагинацией. Без единого условия фильтра
+// список пуст, как и прежде; вся библиотека обходится через GetSongIds
+func (s *SongService) GetSongs(filter model.SongFilter, rawPage, rawLimit int) ([]model.Song, error) {
 	page, limit := handlePagingData(rawPage, rawLimit)
-	return s.songRepos.GetSongs(group, song, page, limit)
+	if filter == (model.SongFilter{}) {
+		return make([]model.Song, 0), nil
+	}
+	filter, err := normalizeSongFilter(filter)
+	if err != nil {
+		return nil, err
+	}
+	return s.songRepos.GetSongs(filter, page, limit)
+}
+
+// GetSongIds Получение идентификаторов песен по фильтру после afterId в порядке возрастания
+func (s *SongService) GetSongIds(filter model.SongFilter, afterId int64, limit int) ([]int64, error) {
+	filter, err := normalizeSongFilter(filter)
+	if err != nil {
+		return nil, err
+	}
+	return s.songRepos.GetSongIds(filter, afterId, limit)
+}
+
+// CountSongs Количество песен по фильтру
+func (s *SongService) CountSongs(filter model.SongFilter) (int, error) {
+	filter, err := normalizeSongFilter(filter)
+	if err != nil {
+		return 0, err
+	}
+	return s.songRepos.CountSongs(filter)
+}
+
+// checkSongExists Проверка существования песни перед чтением или изменением связанных с ней данных
+func checkSongExists(songRepos repository.Song, id int64) error {
+	exists, err := songRepos.SongExists(id)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return fmt.Errorf("song %d: %w", id, model.ErrNotFound)
+	}
+	return nil
 }
 
-// GetSongVerses Получение текста песни с пагинацией по куплетам
-func (s *SongService) GetSongVerses(id int64, rawPage, rawLimit int) ([]model.Verse, error) {
+// setSearchKeys Заполнение ключей поиска по названиям группы и песни перед записью
+func setSearchKeys(song *model.Song) {
+	song.GroupSearchKey = search.Key(song.Group)
+	song.NameSearchKey = search.Key(song.Name)
+}
+
+// checkDuplicate Проверка, что другой песни с теми же ключами группы и названия нет.
+// Названия, отличающиеся только формой Unicode, кавычками, буквой ё или письменностью, считаются одинаковыми.
+// Проверка дает понятное сообщение об ошибке, одновременные записи отклоняет уникальный индекс по ключам
+func (s *SongService) checkDuplicate(song model.Song) error {
+	duplicateId, err := s.songRepos.FindSongBySearchKeys(song.GroupSearchKey, song.NameSearchKey)
+	if errors.Is(err, model.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if duplicateId != song.Id {
+		return fmt.Errorf("%w: song %q by %q already exists with id %d", model.ErrConflict, song.Name, song.Group, duplicateId)
+	}
+	return nil
+}
+
+// normalizeSongFilter Проверка фильтра и приведение его значений к виду, в котором они хранятся.
+// Группа и название заменяются ключами поиска, поэтому запрос на любой письменности находит песню
+func normalizeSongFilter(filter model.SongFilter) (model.SongFilter, error) {
+	filter.Group = search.Key(filter.Group)
+	filter.Name = search.Key(filter.Name)
+	filter.Tag = search.Key(filter.Tag)
+	if filter.ReleasedFrom != nil && filter.ReleasedTo != nil && filter.ReleasedFrom.After(*filter.ReleasedTo) {
+		return model.SongFilter{}, fmt.Errorf("%w: released_from is after released_to", model.ErrInvalidInput)
+	}
+
+	var err error
+	if filter.Language, err = normalizeLanguageTag(filter.Language); err != nil {
+		return model.SongFilter{}, err
+	}
+	return filter, nil
+}
+
+// GetSongVerses Получение текста песни с пагинацией по куплетам. В сжатом виде повторяющиеся куплеты
+// возвращаются один раз вместе со своими позициями. Версия текста выбирается по предпочтительным языкам,
+// вместе с куплетами возвращается ее язык
+func (s *SongService) GetSongVerses(id int64, rawPage, rawLimit int, compact bool, preferred []language.Tag) ([]model.Verse, string, error) {
 	page, limit := handlePagingData(rawPage, rawLimit)
-	return s.songRepos.GetSongVerses(id, page, limit)
+	lang, err := s.selectLanguage(id, preferred)
+	if err != nil {
+		return nil, "", err
+	}
+
+	verses, err := s.songRepos.GetSongVerses(id, page, limit, compact, lang)
+	if err != nil {
+		return nil, "", err
+	}
+	return verses, lang, nil
 }
 
-// DeleteSong Удаление песни
+// GetVerseLines Получение строк куплета с пагинацией. Куплет задается позицией в развернутом тексте песни
+func (s *SongService) GetVerseLines(id int64, verseNumber, rawPage, rawLimit int) ([]model.VerseLine, error) {
+	if verseNumber < 0 {
+		return nil, fmt.Errorf("%w: verse number must not be negative", model.ErrInvalidInput)
+	}
+	page, limit := handlePagingData(rawPage, rawLimit)
+	return s.songRepos.GetVerseLines(id, verseNumber, page, limit)
+}
+
+// DeleteSong Удаление песни. Записи песни в альбомах и плейлистах удаляются каскадно
 func (s *SongService) DeleteSong(id int64) error {
 	return s.songRepos.DeleteSong(id)
 }
 
 // UpdateSong Изменение песни
 func (s *SongService) UpdateSong(song model.Song, text string) error {
-	song.Verses = textToVerses(text)
+	switch song.ReleaseDatePrecision {
+	case "", model.DatePrecisionYear, model.DatePrecisionMonth, model.DatePrecisionDay:
+	default:
+		return fmt.Errorf("%w: unknown release date precision %q", model.ErrInvalidInput, song.ReleaseDatePrecision)
+	}
+
+	var err error
+	if song.Language, err = normalizeLanguageTag(song.Language); err != nil {
+		return err
+	}
+
+	if err = s.parseCredits(&song); err != nil {
+		return err
+	}
+	if err = s.resolveArtist(&song); err != nil {
+		return err
+	}
+	setSearchKeys(&song)
+	if err = s.checkDuplicate(song); err != nil {
+		return err
+	}
+	if err = s.resolveCredits(&song); err != nil {
+		return err
+	}
+
+	song.Verses, song.Arrangement = parseLyrics(text)
+	detectSongLanguage(&song)
+	s.detectExplicit(&song)
+	setSongTerms(&song)
 	return s.songRepos.UpdateSong(song)
 }
 
-// AddSong Добавление песни
-func (s *SongService) AddSong(song model.Song) (int64, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+// AddSong Добавление песни. Вместе с идентификатором возвращаются предупреждения обогащения
+func (s *SongService) AddSong(song model.Song, clientData ClientSongData) (int64, []string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), enrichmentTimeout)
 	defer cancel()
-	enrichedSong, err := s.enrichSongWithAPI(ctx, song)
+
+	var err error
+	if song.Language, err = normalizeLanguageTag(song.Language); err != nil {
+		return 0, nil, err
+	}
+
+	if err = s.parseCredits(&song); err != nil {
+		return 0, nil, err
+	}
+	if err = s.resolveArtist(&song); err != nil {
+		return 0, nil, err
+	}
+	setSearchKeys(&song)
+	if err = s.checkDuplicate(song); err != nil {
+		return 0, nil, err
+	}
+
+	enrichedSong, warnings, err := s.enrichSongWithAPI(ctx, song, clientData)
 	if err != nil {
-		return 0, err
+		return 0, nil, err
 	}
 
-	return s.songRepos.AddSong(enrichedSong)
+	detectSongLanguage(&enrichedSong)
+	s.detectExplicit(&enrichedSong)
+	setSearchKeys(&enrichedSong)
+	setSongTerms(&enrichedSong)
+	if err = s.resolveCredits(&enrichedSong); err != nil {
+		return 0, warnings, err
+	}
+	songId, err := s.songRepos.AddSong(enrichedSong)
+	return songId, warnings, err
 }
 
-// EnrichSongWithAPI Обогащение данных с использованием стороннего сервиса
-func (s *SongService) enrichSongWithAPI(ctx context.Context, song model.Song) (enrichedSong model.Song, err error) {
-	errChan := make(chan error)
-	resChan := make(chan SongFetchData)
-
-	go func() {
-		songDetails, clientError := s.songDataFetcher.FetchSongDetails(song.Group, song.Name)
-		if clientError != nil {
-			errChan <- clientError
-			return
-		}
-		resChan <- songDetails
-	}()
+// GetSongSources Получение истории провайдеров, предоставивших поля песни
+func (s *SongService) GetSongSources(id int64) ([]model.SongSource, error) {
+	return s.songRepos.GetSongSources(id)
+}
 
+// EnrichSongWithAPI Обогащение данных с использованием стороннего сервиса.
+// Значения клиента и провайдеров объединяются по правилам приоритета для каждого поля
+func (s *SongService) enrichSongWithAPI(ctx context.Context, song model.Song, clientData ClientSongData) (enrichedSong model.Song, warnings []string, err error) {
 	var songDetails SongFetchData
-	select {
-	case clientError := <-errChan:
-		return model.Song{}, clientError
-	case res := <-resChan:
-		songDetails = res
-	case <-ctx.Done():
-		return model.Song{}, ctx.Err()
+	upstreamProviders := make(map[SongField]string)
+	if s.needsUpstream(clientData) {
+		var sources []model.SongSource
+		songDetails, sources, err = s.providers.Fetch(ctx, song.Group, song.Name)
+		if err != nil {
+			return model.Song{}, nil, err
+		}
+		for _, source := range sources {
+			upstreamProviders[SongField(source.Field)] = source.Provider
+		}
 	}
 
-	if err != nil {
-		return song, err
-	}
+	enrichedSong = song
+	enrichedSong.Sources = make([]model.SongSource, 0, len(songFields))
+	warnings = make([]string, 0)
+	for _, field := range songFields {
+		value, fromClient := s.precedence[field].resolve(clientData.field(field), songDetails.field(field))
+		provider := upstreamProviders[field]
+		if fromClient {
+			provider = clientSourceName
+		}
 
-	layout := "02.01.2006"
-	releaseDate, err := time.Parse(layout, songDetails.ReleaseDate)
+		switch field {
+		case SongFieldReleaseDate:
+			releaseDate, precision, parseErr := parseReleaseDate(value)
+			if parseErr != nil && fromClient {
+				return model.Song{}, nil, fmt.Errorf("%w: %s", model.ErrInvalidInput, parseErr)
+			}
+			if parseErr != nil {
+				warnings = append(warnings, releaseDateWarning(parseErr, provider))
+				continue
+			}
+			enrichedSong.ReleaseDate = releaseDate
+			enrichedSong.ReleaseDatePrecision = precision
+		case SongFieldText:
+			enrichedSong.Verses, enrichedSong.Arrangement = parseLyrics(value)
+		case SongFieldLink:
+			enrichedSong.Link = value
+		}
 
-	enrichedSong.ReleaseDate = releaseDate
-	enrichedSong.Verses = textToVerses(songDetails.Text)
-	enrichedSong.Link = songDetails.Link
+		if value != "" {
+			enrichedSong.Sources = append(enrichedSong.Sources, model.SongSource{Field: string(field), Provider: provider})
+		}
+	}
+
+	return enrichedSong, warnings, nil
+}
 
-	return enrichedSong, nil
+func (s *SongService) needsUpstream(clientData ClientSongData) bool {
+	for _, field := range songFields {
+		if s.precedence[field].needsUpstream(clientData.field(field)) {
+			return true
+		}
+	}
+	return false
 }
 
 func handlePagingData(rawPage, rawLimit int) (page, limit int) {
@@ -116,17 +318,3 @@ func handlePagingData(rawPage, rawLimit int) (page, limit int) {
 	}
 	return
 }
-
-func textToVerses(text string) []model.Verse {
-	verses := strings.Split(text, "\n\n")
-	cleanedVerses := make([]model.Verse, 0)
-	count := 0
-	for _, verse := range verses {
-		cleanedVerse := strings.TrimSpace(verse)
-		if cleanedVerse != "" {
-			cleanedVerses = append(cleanedVerses, model.Verse{VerseNumber: count, Text: cleanedVerse})
-			count++
-		}
-	}
-	return cleanedVerses
-}
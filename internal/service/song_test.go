@@ -0,0 +1,43 @@
+package service
+
+import (
+	"BestMusicLibrary/internal/model"
+	"BestMusicLibrary/internal/profanity"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestNormalizeSongFilterUsesSearchKeys(t *testing.T) {
+	filter, err := normalizeSongFilter(model.SongFilter{Group: "Kino", Name: "«Звезда по имени Солнце»"})
+	require.NoError(t, err)
+	assert.Equal(t, "kino", filter.Group)
+	assert.Equal(t, `"zvezda po imeni solncze"`, filter.Name)
+}
+
+func TestUpdateSongRejectsDuplicate(t *testing.T) {
+	existing := model.Song{Id: 1, Group: "Кино", Name: "Чёрный кот"}
+	setSearchKeys(&existing)
+	repos := &stubSongRepository{song: existing}
+	songService := NewSongService(repos, SongServiceOptions{Artists: &stubArtistRepository{}})
+
+	err := songService.UpdateSong(model.Song{Id: 2, Group: "KINO", Name: "Черный кот"}, "")
+	assert.ErrorIs(t, err, model.ErrConflict)
+
+	require.NoError(t, songService.UpdateSong(model.Song{Id: 1, Group: "Кино", Name: "Черный кот"}, ""))
+	assert.Equal(t, "Черный кот", repos.song.Name)
+	assert.Equal(t, "chernyj kot", repos.song.NameSearchKey)
+}
+
+func TestUpdateSongDetectsExplicit(t *testing.T) {
+	repos := &stubSongRepository{song: model.Song{Id: 1}}
+	songService := NewSongService(repos, SongServiceOptions{Artists: &stubArtistRepository{}, ExplicitDetector: profanity.Default()})
+
+	require.NoError(t, songService.UpdateSong(model.Song{Id: 1, Group: "Band", Name: "Song"}, "What the fuck\n\nis going on"))
+	require.NotNil(t, repos.song.ExplicitDetected)
+	assert.True(t, *repos.song.ExplicitDetected)
+
+	require.NoError(t, songService.UpdateSong(model.Song{Id: 1, Group: "Band", Name: "Song"}, "Группа крови на рукаве"))
+	require.NotNil(t, repos.song.ExplicitDetected)
+	assert.False(t, *repos.song.ExplicitDetected)
+}
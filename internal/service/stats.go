@@ -0,0 +1,32 @@
+package service
+
+import (
+	"BestMusicLibrary/internal/lyricstats"
+	"BestMusicLibrary/internal/model"
+	"errors"
+	"github.com/sirupsen/logrus"
+)
+
+// GetSongStats Статистика текста песни. Посчитанная статистика хранится до следующего изменения песни
+// или изменения алгоритма статистики
+func (s *SongService) GetSongStats(id int64) (model.SongStats, error) {
+	stats, err := s.songRepos.GetSongStats(id, lyricstats.Version)
+	if err == nil {
+		return stats, nil
+	}
+	if !errors.Is(err, model.ErrNotFound) {
+		return model.SongStats{}, err
+	}
+
+	song, err := s.songRepos.GetSong(id)
+	if err != nil {
+		return model.SongStats{}, err
+	}
+
+	stats = lyricstats.Compute(expandVerses(song.Verses, song.Arrangement))
+	stats.UniqueVerseCount = len(song.Verses)
+	if err = s.songRepos.SaveSongStats(id, song.UpdatedAt, lyricstats.Version, stats); err != nil {
+		logrus.WithField("songId", id).Warn(err)
+	}
+	return stats, nil
+}
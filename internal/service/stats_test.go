@@ -0,0 +1,67 @@
+package service
+
+import (
+	"BestMusicLibrary/internal/lyricstats"
+	"BestMusicLibrary/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+// stubStatsRepository Хранилище песни с кешем статистики, действующим до изменения песни или версии алгоритма
+type stubStatsRepository struct {
+	*stubSongRepository
+	cached        *model.SongStats
+	cachedAt      time.Time
+	cachedVersion int
+	songReads     int
+}
+
+func (r *stubStatsRepository) GetSong(id int64) (model.Song, error) {
+	r.songReads++
+	return r.stubSongRepository.GetSong(id)
+}
+
+func (r *stubStatsRepository) GetSongStats(id int64, version int) (model.SongStats, error) {
+	if id != r.song.Id || r.cached == nil || !r.cachedAt.Equal(r.song.UpdatedAt) || r.cachedVersion != version {
+		return model.SongStats{}, model.ErrNotFound
+	}
+	return *r.cached, nil
+}
+
+func (r *stubStatsRepository) SaveSongStats(_ int64, songUpdatedAt time.Time, version int, stats model.SongStats) error {
+	r.cached, r.cachedAt, r.cachedVersion = &stats, songUpdatedAt, version
+	return nil
+}
+
+func TestGetSongStatsCachesUntilUpdate(t *testing.T) {
+	verses, arrangement := parseLyrics("[Chorus]\nПоем вместе\n\nПервый куплет\n\n[Chorus]")
+	repos := &stubStatsRepository{stubSongRepository: &stubSongRepository{
+		song: model.Song{Id: 1, Verses: verses, Arrangement: arrangement, UpdatedAt: time.Unix(1, 0)},
+	}}
+	songService := NewSongService(repos, SongServiceOptions{})
+
+	stats, err := songService.GetSongStats(1)
+	require.NoError(t, err)
+	assert.Equal(t, 3, stats.VerseCount)
+	assert.Equal(t, 2, stats.UniqueVerseCount)
+	assert.Equal(t, 6, stats.WordCount)
+
+	_, err = songService.GetSongStats(1)
+	require.NoError(t, err)
+	assert.Equal(t, 1, repos.songReads)
+
+	repos.song.UpdatedAt = time.Unix(2, 0)
+	_, err = songService.GetSongStats(1)
+	require.NoError(t, err)
+	assert.Equal(t, 2, repos.songReads)
+
+	repos.cachedVersion = lyricstats.Version - 1
+	_, err = songService.GetSongStats(1)
+	require.NoError(t, err)
+	assert.Equal(t, 3, repos.songReads)
+
+	_, err = songService.GetSongStats(2)
+	assert.ErrorIs(t, err, model.ErrNotFound)
+}
@@ -0,0 +1,170 @@
+package service
+
+import (
+	"BestMusicLibrary/internal/model"
+	"BestMusicLibrary/internal/repository"
+)
+
+// Общие заглушки хранилищ для тестов сервисов. Заглушка встраивает интерфейс хранилища и реализует только методы,
+// которые вызывают тесты, вызов остальных методов завершает тест паникой
+
+// stubSongRepository Хранилище одной песни с переводами и участниками
+type stubSongRepository struct {
+	repository.Song
+	song         model.Song
+	translations map[string][]model.Verse
+	credits      []model.SongCredit
+}
+
+func (r *stubSongRepository) GetSong(id int64) (model.Song, error) {
+	if id != r.song.Id {
+		return model.Song{}, model.ErrNotFound
+	}
+	return r.song, nil
+}
+
+func (r *stubSongRepository) SongExists(id int64) (bool, error) {
+	return id == r.song.Id, nil
+}
+
+func (r *stubSongRepository) GetSongLanguages(id int64) (string, []string, error) {
+	if id != r.song.Id {
+		return "", nil, model.ErrNotFound
+	}
+	languages := make([]string, 0, len(r.translations))
+	for lang := range r.translations {
+		languages = append(languages, lang)
+	}
+	return r.song.Language, languages, nil
+}
+
+func (r *stubSongRepository) ReplaceTranslation(_ int64, lang string, verses []model.Verse) error {
+	if verses == nil {
+		delete(r.translations, lang)
+		return nil
+	}
+	r.translations[lang] = verses
+	return nil
+}
+
+func (r *stubSongRepository) FindSongBySearchKeys(groupKey, titleKey string) (int64, error) {
+	if groupKey != r.song.GroupSearchKey || titleKey != r.song.NameSearchKey {
+		return 0, model.ErrNotFound
+	}
+	return r.song.Id, nil
+}
+
+func (r *stubSongRepository) UpdateSong(song model.Song) error {
+	if song.Id != r.song.Id {
+		return model.ErrNotFound
+	}
+	if song.Credits != nil {
+		songCredits := append([]model.SongCredit{{ArtistId: song.ArtistId, Artist: song.Group, Role: model.CreditRolePrimary}}, song.Credits...)
+		for _, credit := range r.credits {
+			if credit.Role != model.CreditRolePrimary && credit.Role != model.CreditRoleFeatured {
+				songCredits = append(songCredits, credit)
+			}
+		}
+		r.credits = songCredits
+	}
+	r.song = song
+	return nil
+}
+
+func (r *stubSongRepository) UpdateSongDerived(song model.Song) error {
+	return r.UpdateSong(song)
+}
+
+func (r *stubSongRepository) ReplaceSongTerms(int64, map[string]int) error {
+	return nil
+}
+
+func (r *stubSongRepository) GetSongCredits(int64) ([]model.SongCredit, error) {
+	return r.credits, nil
+}
+
+func (r *stubSongRepository) ReplaceSongCredits(id int64, credits []model.SongCredit) error {
+	if id != r.song.Id {
+		return model.ErrNotFound
+	}
+	r.credits = credits
+	return nil
+}
+
+func (r *stubSongRepository) CountSongs(model.SongFilter) (int, error) {
+	return 1, nil
+}
+
+// stubArtistRepository Исполнители в памяти
+type stubArtistRepository struct {
+	repository.Artist
+	artists []model.Artist
+}
+
+func (r *stubArtistRepository) GetArtist(id int64) (model.Artist, error) {
+	for _, artist := range r.artists {
+		if artist.Id == id {
+			return artist, nil
+		}
+	}
+	return model.Artist{}, model.ErrNotFound
+}
+
+func (r *stubArtistRepository) FindArtistBySearchKey(searchKey string) (model.Artist, error) {
+	for _, artist := range r.artists {
+		if artist.SearchKey == searchKey {
+			return artist, nil
+		}
+		for _, aliasKey := range artist.AliasSearchKeys {
+			if aliasKey == searchKey {
+				return artist, nil
+			}
+		}
+	}
+	return model.Artist{}, model.ErrNotFound
+}
+
+func (r *stubArtistRepository) AddArtist(artist model.Artist) (int64, error) {
+	artist.Id = int64(len(r.artists) + 1)
+	r.artists = append(r.artists, artist)
+	return artist.Id, nil
+}
+
+func (r *stubArtistRepository) UpdateArtist(artist model.Artist) error {
+	for index := range r.artists {
+		if r.artists[index].Id == artist.Id {
+			r.artists[index] = artist
+			return nil
+		}
+	}
+	return model.ErrNotFound
+}
+
+// stubGenreRepository Жанры в памяти
+type stubGenreRepository struct {
+	repository.Genre
+	genres []model.Genre
+}
+
+func (r *stubGenreRepository) GetGenres() ([]model.Genre, error) {
+	return r.genres, nil
+}
+
+func (r *stubGenreRepository) GetGenre(id int64) (model.Genre, error) {
+	for _, genre := range r.genres {
+		if genre.Id == id {
+			return genre, nil
+		}
+	}
+	return model.Genre{}, model.ErrNotFound
+}
+
+func (r *stubGenreRepository) UpdateGenre(genre model.Genre) error {
+	for index := range r.genres {
+		if r.genres[index].Id == genre.Id {
+			r.genres[index] = genre
+			return nil
+		}
+	}
+	return model.ErrNotFound
+}
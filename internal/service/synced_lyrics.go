@@ -0,0 +1,70 @@
+package service
+
+import (
+	"BestMusicLibrary/internal/model"
+	"fmt"
+	"strings"
+)
+
+// ImportSyncedLyrics Загрузка времени строк песни из LRC. Строки LRC по порядку сопоставляются со строками
+// развернутого текста песни без учета регистра и пунктуации, прежние метки времени заменяются
+func (s *SongService) ImportSyncedLyrics(id int64, lrc string) ([]model.TimedLine, error) {
+	song, err := s.songRepos.GetSong(id)
+	if err != nil {
+		return nil, err
+	}
+
+	lrcLines, err := parseLRC(lrc)
+	if err != nil {
+		return nil, err
+	}
+
+	songLines := make([]model.TimedLine, 0, len(lrcLines))
+	for _, verse := range expandVerses(song.Verses, song.Arrangement) {
+		for lineNumber, line := range strings.Split(verse.Text, "\n") {
+			songLines = append(songLines, model.TimedLine{VerseNumber: verse.VerseNumber, LineNumber: lineNumber, Text: line})
+		}
+	}
+
+	if len(lrcLines) != len(songLines) {
+		return nil, fmt.Errorf("%w: lrc has %d lyric lines, song %d has %d", model.ErrInvalidInput, len(lrcLines), id, len(songLines))
+	}
+	for index, lrcLine := range lrcLines {
+		if stanzaKey(lrcLine.text) != stanzaKey(songLines[index].Text) {
+			return nil, fmt.Errorf("%w: lrc line %d %q does not match song line %q", model.ErrInvalidInput,
+				lrcLine.sourceLine, lrcLine.text, songLines[index].Text)
+		}
+		songLines[index].StartMs = lrcLine.startMs
+		songLines[index].EndMs = lrcLine.endMs
+		songLines[index].Words = lrcLine.words
+	}
+
+	if err = s.songRepos.ReplaceLyricTimings(id, songLines); err != nil {
+		return nil, err
+	}
+	return songLines, nil
+}
+
+// ExportSyncedLyrics Выгрузка синхронизированного текста песни в LRC или WebVTT
+func (s *SongService) ExportSyncedLyrics(id int64, format LyricsFormat) (string, error) {
+	song, err := s.songRepos.GetSong(id)
+	if err != nil {
+		return "", err
+	}
+
+	lines, err := s.songRepos.GetLyricTimings(id)
+	if err != nil {
+		return "", err
+	}
+	if len(lines) == 0 {
+		return "", fmt.Errorf("song %d has no synced lyrics: %w", id, model.ErrNotFound)
+	}
+
+	switch format {
+	case LyricsFormatLRC:
+		return formatLRC(song, lines), nil
+	case LyricsFormatWebVTT:
+		return formatWebVTT(lines), nil
+	}
+	return "", fmt.Errorf("%w: unknown lyrics format %q", model.ErrInvalidInput, format)
+}
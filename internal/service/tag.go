@@ -0,0 +1,71 @@
+package service
+
+import (
+	"BestMusicLibrary/internal/model"
+	"BestMusicLibrary/internal/normalize"
+	"BestMusicLibrary/internal/repository"
+	"BestMusicLibrary/internal/search"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	maxTagLength           = 50
+	defaultTagSuggestLimit = 10
+	maxTagSuggestLimit     = 100
+)
+
+type TagService struct {
+	tagRepos  repository.Tag
+	songRepos repository.Song
+}
+
+func NewTagService(repos repository.Tag, songRepos repository.Song) *TagService {
+	return &TagService{tagRepos: repos, songRepos: songRepos}
+}
+
+func (s *TagService) GetSongTags(songId int64) ([]string, error) {
+	if err := checkSongExists(s.songRepos, songId); err != nil {
+		return nil, err
+	}
+	return s.tagRepos.GetSongTags(songId)
+}
+
+// AddSongTag Добавление произвольного тега песне. Тег хранится в нижнем регистре, теги с одинаковым
+// ключом поиска считаются одним тегом
+func (s *TagService) AddSongTag(songId int64, tag string) error {
+	name := strings.ToLower(normalize.Text(tag))
+	if search.Key(name) == "" {
+		return fmt.Errorf("%w: tag is empty", model.ErrInvalidInput)
+	}
+	if utf8.RuneCountInString(name) > maxTagLength {
+		return fmt.Errorf("%w: tag is longer than %d characters", model.ErrInvalidInput, maxTagLength)
+	}
+	if err := checkSongExists(s.songRepos, songId); err != nil {
+		return err
+	}
+	return s.tagRepos.AddSongTag(songId, name, search.Key(name))
+}
+
+func (s *TagService) DeleteSongTag(songId int64, tag string) error {
+	return s.tagRepos.DeleteSongTag(songId, search.Key(tag))
+}
+
+// SuggestTags Автодополнение тега: используемые теги, начинающиеся с prefix на любой письменности,
+// самые частые первыми. Нулевой limit возвращает десять тегов
+func (s *TagService) SuggestTags(prefix string, limit int) ([]string, error) {
+	switch {
+	case limit < 0 || limit > maxTagSuggestLimit:
+		return nil, fmt.Errorf("%w: limit %d is outside [0, %d]", model.ErrInvalidInput, limit, maxTagSuggestLimit)
+	case limit == 0:
+		limit = defaultTagSuggestLimit
+	}
+	return s.tagRepos.SuggestTags(search.Key(prefix), limit)
+}
+
+// GetTagCounts Число песен каждого тега с пагинацией
+func (s *TagService) GetTagCounts(rawPage, rawLimit int) ([]model.TagCount, error) {
+	page, limit := handlePagingData(rawPage, rawLimit)
+	return s.tagRepos.GetTagCounts(page, limit)
+}
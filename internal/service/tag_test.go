@@ -0,0 +1,54 @@
+package service
+
+import (
+	"BestMusicLibrary/internal/model"
+	"BestMusicLibrary/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+)
+
+// stubTagRepository Запоминает последний добавленный тег и запрос автодополнения
+type stubTagRepository struct {
+	repository.Tag
+	name, searchKey string
+	prefix          string
+	limit           int
+}
+
+func (r *stubTagRepository) AddSongTag(_ int64, name, searchKey string) error {
+	r.name, r.searchKey = name, searchKey
+	return nil
+}
+
+func (r *stubTagRepository) SuggestTags(prefix string, limit int) ([]string, error) {
+	r.prefix, r.limit = prefix, limit
+	return []string{}, nil
+}
+
+func TestAddSongTagNormalizesTag(t *testing.T) {
+	tags := &stubTagRepository{}
+	tagService := NewTagService(tags, &stubSongRepository{song: model.Song{Id: 1}})
+
+	require.NoError(t, tagService.AddSongTag(1, "  Летние   Хиты "))
+	assert.Equal(t, "летние хиты", tags.name)
+	assert.Equal(t, "letnie xity", tags.searchKey)
+
+	assert.ErrorIs(t, tagService.AddSongTag(1, " "), model.ErrInvalidInput)
+	assert.ErrorIs(t, tagService.AddSongTag(1, strings.Repeat("a", maxTagLength+1)), model.ErrInvalidInput)
+	assert.ErrorIs(t, tagService.AddSongTag(2, "rock"), model.ErrNotFound)
+}
+
+func TestSuggestTags(t *testing.T) {
+	tags := &stubTagRepository{}
+	tagService := NewTagService(tags, nil)
+
+	_, err := tagService.SuggestTags("Лет", 0)
+	require.NoError(t, err)
+	assert.Equal(t, "let", tags.prefix)
+	assert.Equal(t, defaultTagSuggestLimit, tags.limit)
+
+	_, err = tagService.SuggestTags("let", maxTagSuggestLimit+1)
+	assert.ErrorIs(t, err, model.ErrInvalidInput)
+}
@@ -3,6 +3,7 @@ package service
 import (
 	"BestMusicLibrary/internal/model"
 	"github.com/stretchr/testify/assert"
+	"strings"
 	"testing"
 )
 
@@ -33,3 +34,174 @@ func TestTextToVersesEmptyText(t *testing.T) {
 	result := textToVerses(text)
 	assert.Equal(t, expected, result, "the verses should handle empty lines correctly")
 }
+
+func TestTextToVersesSectionHeaders(t *testing.T) {
+	text := "[Intro]\nOh-oh\n\n[Verse 1]\nFirst line\nSecond line\n\n[Chorus]\nSing along\n\n" +
+		"Припев:\nПоем вместе\n\n[Pre-Chorus]\nAlmost there\n\nJust a verse"
+	expected := []model.Verse{
+		{VerseNumber: 0, Text: "Oh-oh", SectionType: model.SectionIntro, SectionLabel: "Intro"},
+		{VerseNumber: 1, Text: "First line\nSecond line", SectionType: model.SectionVerse, SectionLabel: "Verse 1"},
+		{VerseNumber: 2, Text: "Sing along", SectionType: model.SectionChorus, SectionLabel: "Chorus"},
+		{VerseNumber: 3, Text: "Поем вместе", SectionType: model.SectionChorus, SectionLabel: "Припев"},
+		{VerseNumber: 4, Text: "Almost there", SectionType: model.SectionPreChorus, SectionLabel: "Pre-Chorus"},
+		{VerseNumber: 5, Text: "Just a verse"},
+	}
+	assert.Equal(t, expected, textToVerses(text))
+}
+
+func TestTextToVersesHeaderWithoutBlankLine(t *testing.T) {
+	text := "First verse\n[Chorus]\nSing along"
+	expected := []model.Verse{
+		{VerseNumber: 0, Text: "First verse"},
+		{VerseNumber: 1, Text: "Sing along", SectionType: model.SectionChorus, SectionLabel: "Chorus"},
+	}
+	assert.Equal(t, expected, textToVerses(text))
+}
+
+func TestTextToVersesUnknownHeader(t *testing.T) {
+	text := "[Verse 2: Guest Artist]\nRap part\n\n[Skit]\nTalking"
+	expected := []model.Verse{
+		{VerseNumber: 0, Text: "Rap part", SectionType: model.SectionVerse, SectionLabel: "Verse 2: Guest Artist"},
+		{VerseNumber: 1, Text: "Talking", SectionType: model.SectionOther, SectionLabel: "Skit"},
+	}
+	assert.Equal(t, expected, textToVerses(text))
+}
+
+func TestTextToVersesColonLineIsNotHeader(t *testing.T) {
+	text := "She said:\nHello there"
+	expected := []model.Verse{
+		{VerseNumber: 0, Text: "She said:\nHello there"},
+	}
+	assert.Equal(t, expected, textToVerses(text))
+}
+
+func TestTextToVersesEmptySection(t *testing.T) {
+	text := "[Intro]\n\n[Chorus]\nSing along"
+	expected := []model.Verse{
+		{VerseNumber: 0, Text: "Sing along", SectionType: model.SectionChorus, SectionLabel: "Chorus"},
+	}
+	assert.Equal(t, expected, textToVerses(text))
+}
+
+func TestTextToVersesRepeatedSection(t *testing.T) {
+	text := "[Verse 1]\nFirst\n\n[Chorus]\nSing along\n\n[Verse 2]\nSecond\n[Chorus]\n[Outro]"
+	expected := []model.Verse{
+		{VerseNumber: 0, Text: "First", SectionType: model.SectionVerse, SectionLabel: "Verse 1"},
+		{VerseNumber: 1, Text: "Sing along", SectionType: model.SectionChorus, SectionLabel: "Chorus"},
+		{VerseNumber: 2, Text: "Second", SectionType: model.SectionVerse, SectionLabel: "Verse 2"},
+		{VerseNumber: 3, Text: "Sing along", SectionType: model.SectionChorus, SectionLabel: "Chorus"},
+	}
+	assert.Equal(t, expected, textToVerses(text))
+}
+
+func TestTextToVersesRepeatedSectionByType(t *testing.T) {
+	text := "Припев:\nПоем вместе\n\nКуплет\n\n[Chorus]"
+	expected := []model.Verse{
+		{VerseNumber: 0, Text: "Поем вместе", SectionType: model.SectionChorus, SectionLabel: "Припев"},
+		{VerseNumber: 1, Text: "Куплет"},
+		{VerseNumber: 2, Text: "Поем вместе", SectionType: model.SectionChorus, SectionLabel: "Припев"},
+	}
+	assert.Equal(t, expected, textToVerses(text))
+}
+
+func TestTextToVersesWindowsLineEndings(t *testing.T) {
+	text := "[Verse 1]\r\nFirst line \r\nSecond line\r\n\r\nOld Mac\rline\r\rNext"
+	expected := []model.Verse{
+		{VerseNumber: 0, Text: "First line \nSecond line", SectionType: model.SectionVerse, SectionLabel: "Verse 1"},
+		{VerseNumber: 1, Text: "Old Mac\nline"},
+		{VerseNumber: 2, Text: "Next"},
+	}
+	verses := textToVerses(text)
+	assert.Equal(t, expected, verses)
+	assert.Equal(t, []string{"First line ", "Second line"}, strings.Split(verses[0].Text, "\n"))
+}
+
+func TestArrangeVerses(t *testing.T) {
+	verses, arrangement := parseLyrics("[Chorus]\nSing along, now!\n\nFirst verse\n\nsing along now\n\n" +
+		"Second verse\n\n[Chorus]\n\n[Bridge]\nSing along now")
+	expected := []model.Verse{
+		{VerseNumber: 0, Text: "Sing along, now!", SectionType: model.SectionChorus, SectionLabel: "Chorus"},
+		{VerseNumber: 1, Text: "First verse"},
+		{VerseNumber: 2, Text: "Second verse"},
+		{VerseNumber: 3, Text: "Sing along now", SectionType: model.SectionBridge, SectionLabel: "Bridge"},
+	}
+	assert.Equal(t, expected, verses)
+	assert.Equal(t, []int{0, 1, 0, 2, 0, 3}, arrangement)
+}
+
+func TestArrangeVersesWithoutLetters(t *testing.T) {
+	verses, arrangement := parseLyrics("♪ ♪\n\n...\n\n♪  ♪")
+	assert.Equal(t, []model.Verse{
+		{VerseNumber: 0, Text: "♪ ♪"},
+		{VerseNumber: 1, Text: "..."},
+	}, verses)
+	assert.Equal(t, []int{0, 1, 0}, arrangement)
+}
+
+func TestExpandVerses(t *testing.T) {
+	verses := []model.Verse{
+		{VerseNumber: 0, Text: "Chorus", SectionType: model.SectionChorus, SectionLabel: "Chorus", Positions: []int{0, 2}},
+		{VerseNumber: 1, Text: "Verse"},
+	}
+	expected := []model.Verse{
+		{VerseNumber: 0, Text: "Chorus", SectionType: model.SectionChorus, SectionLabel: "Chorus"},
+		{VerseNumber: 1, Text: "Verse"},
+		{VerseNumber: 2, Text: "Chorus", SectionType: model.SectionChorus, SectionLabel: "Chorus"},
+	}
+	assert.Equal(t, expected, expandVerses(verses, []int{0, 1, 0}))
+	assert.Equal(t, verses, expandVerses(verses, nil), "songs without arrangement should keep their verses")
+}
+
+func FuzzTextToVerses(f *testing.F) {
+	f.Add("This is verse 1.\n\nThis is verse 2.")
+	f.Add("[Chorus]\nSing along\n\n[Verse 2]\nNext")
+	f.Add("Припев:\nПоем\r\n\r\nКуплет 2:\nДальше")
+	f.Add("[]\n[ ]\n[Intro]\n\n\n")
+	f.Add("  indented\n\tline  \n\n[Bridge]")
+
+	f.Fuzz(func(t *testing.T, text string) {
+		verses := textToVerses(text)
+
+		rendered := make([]string, 0, len(verses))
+		for index, verse := range verses {
+			if verse.VerseNumber != index {
+				t.Fatalf("verse %d has number %d", index, verse.VerseNumber)
+			}
+			if verse.Text == "" || verse.Text != strings.TrimSpace(verse.Text) {
+				t.Fatalf("verse %d text %q is not trimmed", index, verse.Text)
+			}
+			if strings.Contains(verse.Text, "\r") {
+				t.Fatalf("verse %d text %q contains carriage return", index, verse.Text)
+			}
+			if (verse.SectionType == "") != (verse.SectionLabel == "") {
+				t.Fatalf("verse %d has type %q and label %q", index, verse.SectionType, verse.SectionLabel)
+			}
+			for _, line := range strings.Split(verse.Text, "\n") {
+				if _, ok := parseSectionHeader(strings.TrimSpace(line)); ok {
+					t.Fatalf("verse %d contains header line %q", index, line)
+				}
+				if strings.TrimSpace(line) == "" {
+					t.Fatalf("verse %d contains blank line", index)
+				}
+			}
+
+			block := verse.Text
+			if verse.SectionLabel != "" {
+				block = "[" + verse.SectionLabel + "]\n" + block
+			}
+			rendered = append(rendered, block)
+		}
+
+		assert.Equal(t, verses, textToVerses(strings.Join(rendered, "\n\n")), "parsing the rendered verses should give the same verses")
+
+		unique, arrangement := arrangeVerses(verses)
+		if len(arrangement) != len(verses) || len(unique) > len(verses) {
+			t.Fatalf("arrangement of %d verses has %d positions and %d unique verses", len(verses), len(arrangement), len(unique))
+		}
+		for position, verse := range expandVerses(unique, arrangement) {
+			if stanzaKey(verse.Text) != stanzaKey(verses[position].Text) {
+				t.Fatalf("position %d expands to %q instead of %q", position, verse.Text, verses[position].Text)
+			}
+		}
+	})
+}
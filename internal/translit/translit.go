@@ -0,0 +1,60 @@
+// Package translit Транслитерация кириллицы латиницей по ГОСТ 7.79-2000 (система Б, ISO 9 в записи ASCII).
+// Для русского алфавита используется русская таблица, буквы, встречающиеся только в украинском
+// и белорусском алфавитах, передаются по их таблицам
+package translit
+
+import (
+	"strings"
+	"unicode"
+)
+
+var letters = map[rune]string{
+	'а': "a", 'б': "b", 'в': "v", 'г': "g", 'д': "d", 'е': "e", 'ё': "yo", 'ж': "zh", 'з': "z", 'и': "i",
+	'й': "j", 'к': "k", 'л': "l", 'м': "m", 'н': "n", 'о': "o", 'п': "p", 'р': "r", 'с': "s", 'т': "t",
+	'у': "u", 'ф': "f", 'х': "x", 'ч': "ch", 'ш': "sh", 'щ': "shh", 'ъ': "``", 'ы': "y`", 'ь': "`",
+	'э': "e`", 'ю': "yu", 'я': "ya",
+	'і': "i", 'ї': "yi", 'є': "ye", 'ґ': "g", 'ў': "u`",
+}
+
+// czVowels Буквы, перед которыми "ц" передается как "cz"
+var czVowels = map[rune]bool{'е': true, 'ё': true, 'и': true, 'ы': true, 'й': true, 'і': true, 'є': true, 'ї': true}
+
+// ToLatin Транслитерация строки. Латиница и прочие символы не меняются, регистр сохраняется:
+// заглавная буква, передаваемая несколькими латинскими, пишется целиком заглавными только внутри заглавного слова
+func ToLatin(s string) string {
+	runes := []rune(s)
+	var builder strings.Builder
+	builder.Grow(len(s))
+
+	for index, r := range runes {
+		lower := unicode.ToLower(r)
+		var next rune
+		if index+1 < len(runes) {
+			next = runes[index+1]
+		}
+
+		latin, ok := letters[lower]
+		if lower == 'ц' {
+			latin, ok = "c", true
+			if czVowels[unicode.ToLower(next)] {
+				latin = "cz"
+			}
+		}
+		if !ok {
+			builder.WriteRune(r)
+			continue
+		}
+
+		if r == lower {
+			builder.WriteString(latin)
+			continue
+		}
+		if len(latin) > 1 && !unicode.IsUpper(next) && !(next == 0 && index > 0 && unicode.IsUpper(runes[index-1])) {
+			builder.WriteString(strings.ToUpper(latin[:1]) + latin[1:])
+			continue
+		}
+		builder.WriteString(strings.ToUpper(latin))
+	}
+
+	return builder.String()
+}
@@ -0,0 +1,26 @@
+package translit
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestToLatin(t *testing.T) {
+	cases := map[string]string{
+		"Кино":                "Kino",
+		"Группа крови":        "Gruppa krovi",
+		"Цой жив":             "Coj zhiv",
+		"Цветы и цирк":        "Cvety` i czirk",
+		"Щука съела ёжика":    "Shhuka s``ela yozhika",
+		"Жук":                 "Zhuk",
+		"ДДТ ЖЖЁТ":            "DDT ZHZHYOT",
+		"Ж":                   "Zh",
+		"Океан Ельзи":         "Okean El`zi",
+		"Їжак і ґанок, Євген": "Yizhak i ganok, Yevgen",
+		"Muse":                "Muse",
+		"Би-2 feat. Чичерина": "Bi-2 feat. Chicherina",
+	}
+	for cyrillic, expected := range cases {
+		assert.Equal(t, expected, ToLatin(cyrillic), cyrillic)
+	}
+}
@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE song_sources(
+    id SERIAL PRIMARY KEY,
+    song_id INT REFERENCES songs(id) ON DELETE CASCADE,
+    field VARCHAR(32) NOT NULL,
+    provider VARCHAR(64) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_song_sources_song_id ON song_sources(song_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS song_sources;
+-- +goose StatementEnd
@@ -0,0 +1,12 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE songs ADD COLUMN release_date_precision VARCHAR(8);
+
+UPDATE songs SET release_date = NULL WHERE release_date = '0001-01-01';
+UPDATE songs SET release_date_precision = 'day' WHERE release_date IS NOT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE songs DROP COLUMN IF EXISTS release_date_precision;
+-- +goose StatementEnd
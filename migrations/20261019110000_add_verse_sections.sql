@@ -0,0 +1,11 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE verses ADD COLUMN section_type VARCHAR(32) NOT NULL DEFAULT '';
+ALTER TABLE verses ADD COLUMN section_label TEXT NOT NULL DEFAULT '';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE verses DROP COLUMN IF EXISTS section_label;
+ALTER TABLE verses DROP COLUMN IF EXISTS section_type;
+-- +goose StatementEnd
@@ -0,0 +1,41 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE verse_arrangement(
+    id SERIAL PRIMARY KEY,
+    song_id INT NOT NULL REFERENCES songs(id) ON DELETE CASCADE,
+    position INT NOT NULL,
+    verse_id INT NOT NULL REFERENCES verses(id) ON DELETE CASCADE,
+    UNIQUE (song_id, position)
+);
+
+CREATE INDEX idx_verse_arrangement_verse_id ON verse_arrangement(verse_id);
+
+INSERT INTO verse_arrangement(song_id, position, verse_id)
+SELECT song_id,
+       ROW_NUMBER() OVER (PARTITION BY song_id ORDER BY id) - 1,
+       FIRST_VALUE(id) OVER (PARTITION BY song_id, text, section_type, section_label ORDER BY id)
+FROM verses
+WHERE song_id IS NOT NULL;
+
+DELETE FROM verses v
+WHERE v.song_id IS NOT NULL
+  AND NOT EXISTS (SELECT 1 FROM verse_arrangement a WHERE a.verse_id = v.id);
+
+UPDATE verses v
+SET verse_number = numbered.verse_number
+FROM (SELECT id, ROW_NUMBER() OVER (PARTITION BY song_id ORDER BY id) - 1 AS verse_number FROM verses) numbered
+WHERE v.id = numbered.id;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+INSERT INTO verses(song_id, verse_number, text, section_type, section_label)
+SELECT a.song_id, a.position, v.text, v.section_type, v.section_label
+FROM verse_arrangement a
+JOIN verses v ON v.id = a.verse_id
+ORDER BY a.song_id, a.position;
+
+DELETE FROM verses v WHERE EXISTS (SELECT 1 FROM verse_arrangement a WHERE a.verse_id = v.id);
+
+DROP TABLE IF EXISTS verse_arrangement;
+-- +goose StatementEnd
@@ -0,0 +1,24 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE verse_lines(
+    id SERIAL PRIMARY KEY,
+    verse_id INT NOT NULL REFERENCES verses(id) ON DELETE CASCADE,
+    line_number INT NOT NULL,
+    text TEXT NOT NULL,
+    UNIQUE (verse_id, line_number)
+);
+
+UPDATE verses
+SET text = REPLACE(REPLACE(text, E'\r\n', E'\n'), E'\r', E'\n')
+WHERE text LIKE E'%\r%';
+
+INSERT INTO verse_lines(verse_id, line_number, text)
+SELECT v.id, line.number - 1, line.text
+FROM verses v
+CROSS JOIN LATERAL STRING_TO_TABLE(v.text, E'\n') WITH ORDINALITY AS line(text, number);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS verse_lines;
+-- +goose StatementEnd
@@ -0,0 +1,19 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE songs ADD COLUMN language VARCHAR(35) NOT NULL DEFAULT '';
+
+CREATE TABLE verse_translations(
+    id SERIAL PRIMARY KEY,
+    song_id INT NOT NULL REFERENCES songs(id) ON DELETE CASCADE,
+    language VARCHAR(35) NOT NULL,
+    verse_number INT NOT NULL,
+    text TEXT NOT NULL,
+    UNIQUE (song_id, language, verse_number)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS verse_translations;
+ALTER TABLE songs DROP COLUMN IF EXISTS language;
+-- +goose StatementEnd
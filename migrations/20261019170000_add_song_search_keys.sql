@@ -0,0 +1,62 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE songs
+    ADD COLUMN group_search_key TEXT NOT NULL DEFAULT '',
+    ADD COLUMN title_search_key TEXT NOT NULL DEFAULT '';
+
+-- Ключ поиска в том виде, в котором его строит пакет search при написании миграции: NFC, замена кавычек,
+-- апострофов, тире и буквы ё, нижний регистр, транслитерация по ГОСТ 7.79-2000 (система Б) без апострофов
+-- и знаков мягкого и твердого знаков, схлопывание пробелов. Нижний регистр букв вне кириллицы зависит
+-- от локали базы, после изменения алгоритма ключи пересчитывает `cmd/backfill -reindex`
+CREATE FUNCTION pg_temp.song_search_key(s TEXT) RETURNS TEXT AS $$
+    SELECT BTRIM(REGEXP_REPLACE(
+        TRANSLATE(
+            REPLACE(REPLACE(REPLACE(REPLACE(REPLACE(REPLACE(REPLACE(REPLACE(
+                REGEXP_REPLACE(
+                    LOWER(TRANSLATE(
+                        TRANSLATE(NORMALIZE(s, NFC), '“”„«»‟″‘’‚‛′ʼ`‐‑‒–—―ёЁ', '"""""""''''''''''''''------еЕ'),
+                        'АБВГДЕЖЗИЙКЛМНОПРСТУФХЦЧШЩЪЫЬЭЮЯІЇЄҐЎ',
+                        'абвгдежзийклмнопрстуфхцчшщъыьэюяіїєґў')),
+                    'ц([еиыйієї])', 'cz\1', 'g'),
+                'ж', 'zh'), 'ч', 'ch'), 'щ', 'shh'), 'ш', 'sh'), 'ю', 'yu'), 'я', 'ya'), 'ї', 'yi'), 'є', 'ye'),
+            'абвгдезийклмнопрстуфхцыэіґўъь''`', 'abvgdezijklmnoprstufxcyeigu'),
+        '\s+', ' ', 'g'))
+$$ LANGUAGE SQL;
+
+UPDATE songs SET
+    group_search_key = pg_temp.song_search_key(group_name),
+    title_search_key = pg_temp.song_search_key(song_title);
+
+DROP FUNCTION pg_temp.song_search_key(TEXT);
+
+-- Уже сохраненные дубликаты не объединяются автоматически, потому что у них могут быть разные тексты:
+-- миграция перечисляет их и не применяется, пока они не будут объединены или удалены.
+-- Пустые ключи (название из одних апострофов) не считаются дубликатами и не попадают в индекс
+DO $$
+DECLARE
+    duplicates TEXT;
+BEGIN
+    SELECT STRING_AGG(FORMAT('"%s" by "%s": songs %s', title_search_key, group_search_key, ids), E'\n') INTO duplicates
+    FROM (
+        SELECT group_search_key, title_search_key, STRING_AGG(id::TEXT, ', ' ORDER BY id) AS ids, MIN(id) AS first_id
+        FROM songs
+        WHERE group_search_key <> '' AND title_search_key <> ''
+        GROUP BY group_search_key, title_search_key
+        HAVING COUNT(*) > 1
+        ORDER BY first_id
+    ) d;
+    IF duplicates IS NOT NULL THEN
+        RAISE EXCEPTION E'merge or delete duplicate songs before adding the unique search key index:\n%', duplicates;
+    END IF;
+END $$;
+
+CREATE UNIQUE INDEX idx_songs_search_keys_unique ON songs(group_search_key, title_search_key)
+    WHERE group_search_key <> '' AND title_search_key <> '';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_songs_search_keys_unique;
+ALTER TABLE songs DROP COLUMN IF EXISTS title_search_key;
+ALTER TABLE songs DROP COLUMN IF EXISTS group_search_key;
+-- +goose StatementEnd
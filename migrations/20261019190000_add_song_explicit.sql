@@ -0,0 +1,12 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE songs
+    ADD COLUMN explicit BOOLEAN,
+    ADD COLUMN explicit_override BOOLEAN;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE songs DROP COLUMN IF EXISTS explicit_override;
+ALTER TABLE songs DROP COLUMN IF EXISTS explicit;
+-- +goose StatementEnd
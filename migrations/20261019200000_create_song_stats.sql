@@ -0,0 +1,14 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE song_stats(
+    song_id INT PRIMARY KEY REFERENCES songs(id) ON DELETE CASCADE,
+    song_updated_at TIMESTAMP NOT NULL,
+    stats_version INT NOT NULL DEFAULT 0,
+    stats JSONB NOT NULL
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS song_stats;
+-- +goose StatementEnd
@@ -0,0 +1,27 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE song_terms(
+    song_id INT NOT NULL REFERENCES songs(id) ON DELETE CASCADE,
+    term TEXT NOT NULL,
+    count INT NOT NULL,
+    PRIMARY KEY (song_id, term)
+);
+
+CREATE INDEX idx_song_terms_term ON song_terms(term);
+
+CREATE TABLE term_frequencies(
+    term TEXT PRIMARY KEY,
+    df INT NOT NULL CHECK (df > 0)
+);
+
+CREATE TABLE song_term_documents(
+    song_id INT PRIMARY KEY REFERENCES songs(id) ON DELETE CASCADE
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS song_term_documents;
+DROP TABLE IF EXISTS term_frequencies;
+DROP TABLE IF EXISTS song_terms;
+-- +goose StatementEnd
@@ -0,0 +1,33 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE artists(
+    id SERIAL PRIMARY KEY,
+    name TEXT NOT NULL,
+    search_key TEXT NOT NULL UNIQUE,
+    country VARCHAR(2) NOT NULL DEFAULT '',
+    formed_year INT,
+    disbanded_year INT,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE artist_aliases(
+    id SERIAL PRIMARY KEY,
+    artist_id INT NOT NULL REFERENCES artists(id) ON DELETE CASCADE,
+    alias TEXT NOT NULL,
+    search_key TEXT NOT NULL,
+    UNIQUE (artist_id, alias)
+);
+
+CREATE INDEX idx_artist_aliases_search_key ON artist_aliases(search_key);
+
+ALTER TABLE songs ADD COLUMN artist_id INT REFERENCES artists(id);
+CREATE INDEX idx_songs_artist_id ON songs(artist_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE songs DROP COLUMN IF EXISTS artist_id;
+DROP TABLE IF EXISTS artist_aliases;
+DROP TABLE IF EXISTS artists;
+-- +goose StatementEnd
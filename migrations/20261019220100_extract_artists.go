@@ -0,0 +1,105 @@
+package migrations
+
+import (
+	"database/sql"
+	"github.com/pressly/goose"
+)
+
+func init() {
+	goose.AddMigration(upExtractArtists, downExtractArtists)
+}
+
+// artistSpellings Написания названия группы с одинаковым ключом поиска в порядке убывания частоты
+type artistSpellings struct {
+	searchKey string
+	spellings []string
+}
+
+// Исполнитель песен, у названия группы которых пустой ключ поиска (пустое название или одни апострофы)
+const (
+	unknownArtistName      = "Unknown artist"
+	unknownArtistSearchKey = "unknown artist"
+)
+
+// artistSearchKey Ключ поиска исполнителя песни: пустой ключ группы заменяется ключом неизвестного исполнителя
+const artistSearchKey = `CASE WHEN BTRIM(group_search_key) = '' THEN '` + unknownArtistSearchKey + `' ELSE group_search_key END`
+
+// upExtractArtists Создание исполнителей из названий групп песен. Названия с одинаковым ключом поиска
+// ("Би-2", "Bi-2", "БИ-2") становятся одним исполнителем: самое частое написание именем, остальные другими написаниями.
+// Название группы песни заменяется именем исполнителя, прежнее написание сохраняется в original_group_name.
+// Песни с пустым ключом группы относятся к исполнителю "Unknown artist", их написания не становятся именем
+// или другими написаниями
+func upExtractArtists(tx *sql.Tx) error {
+	if _, err := tx.Exec(`ALTER TABLE songs ADD COLUMN original_group_name TEXT`); err != nil {
+		return err
+	}
+
+	rows, err := tx.Query(`
+		SELECT ` + artistSearchKey + ` AS search_key, group_name, BTRIM(group_search_key) = '' AS blank
+		FROM songs
+		GROUP BY 1, group_name, 3
+		ORDER BY 1, COUNT(*) DESC, MIN(id)`)
+	if err != nil {
+		return err
+	}
+
+	artists := make([]*artistSpellings, 0)
+	for rows.Next() {
+		var searchKey, spelling string
+		var blank bool
+		if err = rows.Scan(&searchKey, &spelling, &blank); err != nil {
+			_ = rows.Close()
+			return err
+		}
+		if len(artists) == 0 || artists[len(artists)-1].searchKey != searchKey {
+			artists = append(artists, &artistSpellings{searchKey: searchKey})
+		}
+		if !blank {
+			last := artists[len(artists)-1]
+			last.spellings = append(last.spellings, spelling)
+		}
+	}
+	if err = rows.Err(); err != nil {
+		_ = rows.Close()
+		return err
+	}
+	if err = rows.Close(); err != nil {
+		return err
+	}
+
+	for _, artist := range artists {
+		if len(artist.spellings) == 0 {
+			artist.spellings = []string{unknownArtistName}
+		}
+		var artistId int64
+		err = tx.QueryRow(`INSERT INTO artists(name, search_key) VALUES($1, $2) RETURNING id`, artist.spellings[0], artist.searchKey).
+			Scan(&artistId)
+		if err != nil {
+			return err
+		}
+		for _, alias := range artist.spellings[1:] {
+			_, err = tx.Exec(`INSERT INTO artist_aliases(artist_id, alias, search_key) VALUES($1, $2, $3)`, artistId, alias, artist.searchKey)
+			if err != nil {
+				return err
+			}
+		}
+		_, err = tx.Exec(`UPDATE songs SET artist_id = $1, original_group_name = group_name, group_name = $2 WHERE `+artistSearchKey+` = $3`,
+			artistId, artist.spellings[0], artist.searchKey)
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = tx.Exec(`ALTER TABLE songs ALTER COLUMN artist_id SET NOT NULL`)
+	return err
+}
+
+// downExtractArtists Возврат прежних названий групп и снятие обязательности ссылки на исполнителя,
+// столбец ссылки удаляется предыдущей миграцией
+func downExtractArtists(tx *sql.Tx) error {
+	_, err := tx.Exec(`
+		UPDATE songs SET group_name = original_group_name WHERE original_group_name IS NOT NULL;
+		ALTER TABLE songs DROP COLUMN original_group_name;
+		ALTER TABLE songs ALTER COLUMN artist_id DROP NOT NULL`)
+	return err
+}
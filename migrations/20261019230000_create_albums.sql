@@ -0,0 +1,32 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE albums(
+    id SERIAL PRIMARY KEY,
+    artist_id INT REFERENCES artists(id),
+    title TEXT NOT NULL,
+    title_search_key TEXT NOT NULL,
+    release_date DATE,
+    album_type VARCHAR(16) NOT NULL CHECK (album_type IN ('lp', 'ep', 'single', 'compilation')),
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_albums_artist_id ON albums(artist_id);
+
+CREATE TABLE album_tracks(
+    album_id INT NOT NULL REFERENCES albums(id) ON DELETE CASCADE,
+    song_id INT NOT NULL REFERENCES songs(id) ON DELETE CASCADE,
+    disc_number INT NOT NULL DEFAULT 1 CHECK (disc_number > 0),
+    track_number INT NOT NULL CHECK (track_number > 0),
+    PRIMARY KEY (album_id, song_id),
+    CONSTRAINT album_tracks_position_key UNIQUE (album_id, disc_number, track_number) DEFERRABLE INITIALLY DEFERRED
+);
+
+CREATE INDEX idx_album_tracks_song_id ON album_tracks(song_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS album_tracks;
+DROP TABLE IF EXISTS albums;
+-- +goose StatementEnd
@@ -0,0 +1,20 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE song_artists(
+    song_id INT NOT NULL REFERENCES songs(id) ON DELETE CASCADE,
+    artist_id INT NOT NULL REFERENCES artists(id),
+    role VARCHAR(16) NOT NULL CHECK (role IN ('primary', 'featured', 'remixer', 'producer', 'songwriter', 'composer')),
+    position INT NOT NULL,
+    PRIMARY KEY (song_id, artist_id, role)
+);
+
+CREATE INDEX idx_song_artists_artist_id ON song_artists(artist_id);
+
+INSERT INTO song_artists(song_id, artist_id, role, position)
+SELECT id, artist_id, 'primary', 1 FROM songs WHERE artist_id IS NOT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS song_artists;
+-- +goose StatementEnd
@@ -0,0 +1,44 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE genres(
+    id SERIAL PRIMARY KEY,
+    parent_id INT REFERENCES genres(id),
+    name TEXT NOT NULL,
+    search_key TEXT NOT NULL UNIQUE,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_genres_parent_id ON genres(parent_id);
+
+CREATE TABLE song_genres(
+    song_id INT NOT NULL REFERENCES songs(id) ON DELETE CASCADE,
+    genre_id INT NOT NULL REFERENCES genres(id) ON DELETE CASCADE,
+    PRIMARY KEY (song_id, genre_id)
+);
+
+CREATE INDEX idx_song_genres_genre_id ON song_genres(genre_id);
+
+CREATE TABLE tags(
+    id SERIAL PRIMARY KEY,
+    name TEXT NOT NULL,
+    search_key TEXT NOT NULL UNIQUE
+);
+
+CREATE INDEX idx_tags_search_key_prefix ON tags(search_key text_pattern_ops);
+
+CREATE TABLE song_tags(
+    song_id INT NOT NULL REFERENCES songs(id) ON DELETE CASCADE,
+    tag_id INT NOT NULL REFERENCES tags(id) ON DELETE CASCADE,
+    PRIMARY KEY (song_id, tag_id)
+);
+
+CREATE INDEX idx_song_tags_tag_id ON song_tags(tag_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS song_tags;
+DROP TABLE IF EXISTS tags;
+DROP TABLE IF EXISTS song_genres;
+DROP TABLE IF EXISTS genres;
+-- +goose StatementEnd
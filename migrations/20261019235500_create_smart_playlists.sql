@@ -0,0 +1,26 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE smart_playlists(
+    id SERIAL PRIMARY KEY,
+    name TEXT NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    owner TEXT NOT NULL,
+    is_public BOOLEAN NOT NULL DEFAULT FALSE,
+    rule JSONB NOT NULL DEFAULT '{}',
+    genre_id INT GENERATED ALWAYS AS ((rule->>'genre_id')::INT) STORED
+        CONSTRAINT smart_playlists_genre_id_fkey REFERENCES genres(id),
+    sort VARCHAR(16) NOT NULL DEFAULT 'added' CHECK (sort IN ('added', 'title', 'group', 'release_date', 'random')),
+    descending BOOLEAN NOT NULL DEFAULT FALSE,
+    song_limit INT NOT NULL CHECK (song_limit > 0),
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_smart_playlists_owner ON smart_playlists(owner);
+CREATE INDEX idx_smart_playlists_genre_id ON smart_playlists(genre_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS smart_playlists;
+-- +goose StatementEnd
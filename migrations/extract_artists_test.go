@@ -0,0 +1,77 @@
+//go:build integration
+
+package migrations
+
+import (
+	"BestMusicLibrary/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"os"
+	"testing"
+)
+
+func getEnv(key, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
+
+// TestExtractArtistsBlankGroupKeys Миграция выполняется на временных таблицах songs и artists, которые в транзакции
+// закрывают одноименные таблицы схемы, и откатывается после теста
+func TestExtractArtistsBlankGroupKeys(t *testing.T) {
+	db, err := repository.NewPostgresDb(repository.Config{
+		Host:     getEnv("DB_HOST", "localhost"),
+		Port:     getEnv("DB_PORT", "5432"),
+		UserName: getEnv("DB_USER", "root"),
+		Password: getEnv("DB_PASSWORD", "root"),
+		DbName:   getEnv("DB_NAME", "song_library"),
+		SSLMode:  getEnv("DB_SSL_MODE", "disable"),
+	})
+	require.NoError(t, err)
+	defer func() {
+		_ = db.Close()
+	}()
+
+	tx, err := db.Begin()
+	require.NoError(t, err)
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	_, err = tx.Exec(`
+		CREATE TEMP TABLE artists(id SERIAL PRIMARY KEY, name TEXT NOT NULL, search_key TEXT NOT NULL UNIQUE);
+		CREATE TEMP TABLE artist_aliases(artist_id INT NOT NULL, alias TEXT NOT NULL, search_key TEXT NOT NULL);
+		CREATE TEMP TABLE songs(id SERIAL PRIMARY KEY, group_name TEXT NOT NULL, group_search_key TEXT NOT NULL, artist_id INT);
+		INSERT INTO songs(group_name, group_search_key) VALUES
+			('Кино', 'kino'), ('КИНО', 'kino'), ('Кино', 'kino'), ('', ''), ('''', '')`)
+	require.NoError(t, err)
+
+	require.NoError(t, upExtractArtists(tx))
+
+	rows, err := tx.Query(`SELECT a.name, s.group_name, COALESCE(s.original_group_name, '') FROM songs s JOIN artists a ON a.id = s.artist_id ORDER BY s.id`)
+	require.NoError(t, err)
+	songs := make([][3]string, 0)
+	for rows.Next() {
+		var song [3]string
+		require.NoError(t, rows.Scan(&song[0], &song[1], &song[2]))
+		songs = append(songs, song)
+	}
+	require.NoError(t, rows.Err())
+	require.NoError(t, rows.Close())
+	assert.Equal(t, [][3]string{
+		{"Кино", "Кино", "Кино"},
+		{"Кино", "Кино", "КИНО"},
+		{"Кино", "Кино", "Кино"},
+		{"Unknown artist", "Unknown artist", ""},
+		{"Unknown artist", "Unknown artist", "'"},
+	}, songs)
+
+	var artists, blankNames, blankAliases int
+	require.NoError(t, tx.QueryRow(`SELECT COUNT(*) FROM artists`).Scan(&artists))
+	require.NoError(t, tx.QueryRow(`SELECT COUNT(*) FROM artists WHERE search_key = '' OR BTRIM(name, ' ''') = ''`).Scan(&blankNames))
+	require.NoError(t, tx.QueryRow(`SELECT COUNT(*) FROM artist_aliases WHERE search_key = '' OR BTRIM(alias, ' ''') = ''`).Scan(&blankAliases))
+	assert.Equal(t, 2, artists)
+	assert.Zero(t, blankNames)
+	assert.Zero(t, blankAliases)
+}